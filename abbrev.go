@@ -0,0 +1,140 @@
+package urn
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Ellipsis marks an abbreviated ID.
+const Ellipsis = "…"
+
+// AbbreviateIDs maps each URN to a display form "urn:entity:<prefix>…"
+// whose ID prefix is the shortest one, at least minLen runes long, that is
+// unique among the IDs of the same entity in the set. IDs short enough to
+// need no truncation are shown in full without the ellipsis. Attributes are
+// dropped from the display form.
+func AbbreviateIDs(urns []string, minLen int) (map[string]string, error) {
+	parsed := make([]*URN, len(urns))
+	byEntity := make(map[string][]string)
+	for i, s := range urns {
+		u, err := Parse(s)
+		if err != nil {
+			return nil, &BatchError{Index: i, Input: s, Err: err}
+		}
+		parsed[i] = u
+		e := asciiToLower(u.Entity)
+		byEntity[e] = append(byEntity[e], u.ID)
+	}
+
+	need := make(map[string]map[string]int, len(byEntity))
+	for e, ids := range byEntity {
+		need[e] = uniquePrefixLens(ids)
+	}
+
+	out := make(map[string]string, len(urns))
+	for i, u := range parsed {
+		n := need[asciiToLower(u.Entity)][u.ID]
+		if n < minLen {
+			n = minLen
+		}
+		prefix, truncated := runePrefix(u.ID, n)
+		s, err := compose(asciiToLower(u.Entity), prefix, nil)
+		if err != nil {
+			return nil, &BatchError{Index: i, Input: urns[i], Err: err}
+		}
+		if truncated {
+			s += Ellipsis
+		}
+		out[urns[i]] = s
+	}
+	return out, nil
+}
+
+// uniquePrefixLens returns, for each distinct id, the number of runes
+// needed to tell it apart from every other distinct id.
+func uniquePrefixLens(ids []string) map[string]int {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	distinct := sorted[:0]
+	for i, id := range sorted {
+		if i == 0 || id != sorted[i-1] {
+			distinct = append(distinct, id)
+		}
+	}
+	lens := make(map[string]int, len(distinct))
+	for i, id := range distinct {
+		n := 1
+		if i > 0 {
+			n = max(n, commonRunes(id, distinct[i-1])+1)
+		}
+		if i < len(distinct)-1 {
+			n = max(n, commonRunes(id, distinct[i+1])+1)
+		}
+		lens[id] = n
+	}
+	return lens
+}
+
+// commonRunes returns the number of leading runes a and b share.
+func commonRunes(a, b string) int {
+	n := 0
+	for a != "" && b != "" {
+		ra, wa := utf8.DecodeRuneInString(a)
+		rb, wb := utf8.DecodeRuneInString(b)
+		if ra != rb || wa != wb {
+			break
+		}
+		a, b = a[wa:], b[wb:]
+		n++
+	}
+	return n
+}
+
+// runePrefix returns the first n runes of s and whether s was truncated.
+func runePrefix(s string, n int) (string, bool) {
+	i := 0
+	for ; n > 0 && i < len(s); n-- {
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+	return s[:i], i < len(s)
+}
+
+// ResolveAbbreviation returns the URN from the set that an abbreviation
+// produced by AbbreviateIDs refers to. An abbreviation without the
+// ellipsis must match an ID exactly; with it, the ID prefix must select
+// exactly one resource. URNs that differ only by attributes count as the
+// same resource, and the first is returned.
+func ResolveAbbreviation(urns []string, abbrev string) (string, error) {
+	prefixOnly := strings.HasSuffix(abbrev, Ellipsis)
+	ref, err := Parse(strings.TrimSuffix(abbrev, Ellipsis))
+	if err != nil {
+		return "", err
+	}
+	var match string
+	var matchID string
+	for i, s := range urns {
+		u, err := Parse(s)
+		if err != nil {
+			return "", &BatchError{Index: i, Input: s, Err: err}
+		}
+		if !asciiEqualFold(u.Entity, ref.Entity) {
+			continue
+		}
+		if u.ID != ref.ID && (!prefixOnly || !strings.HasPrefix(u.ID, ref.ID)) {
+			continue
+		}
+		if match != "" && u.ID != matchID {
+			return "", fmt.Errorf("Ambiguous abbreviation %q: matches %s and %s", abbrev, match, s)
+		}
+		if match == "" {
+			match, matchID = s, u.ID
+		}
+	}
+	if match == "" {
+		return "", fmt.Errorf("Unknown abbreviation %q", abbrev)
+	}
+	return match, nil
+}
@@ -0,0 +1,71 @@
+package urn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAbbreviateIDs(t *testing.T) {
+	urns := []string{
+		"urn:orders:abc123",
+		"urn:orders:abd999",
+		"urn:orders:xyz000:status:new",
+		"urn:customers:abc777",
+		"urn:orders:ab",
+	}
+	got, err := AbbreviateIDs(urns, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"urn:orders:abc123":            "urn:orders:abc…",
+		"urn:orders:abd999":            "urn:orders:abd…",
+		"urn:orders:xyz000:status:new": "urn:orders:xy…",
+		"urn:customers:abc777":         "urn:customers:ab…",
+		"urn:orders:ab":                "urn:orders:ab",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+	for full, abbrev := range got {
+		resolved, err := ResolveAbbreviation(urns, abbrev)
+		if err != nil {
+			t.Errorf("resolving %q: %v", abbrev, err)
+		} else if resolved != full {
+			t.Errorf("resolving %q: got %s, want %s", abbrev, resolved, full)
+		}
+	}
+}
+
+func TestAbbreviateIDsSingleElement(t *testing.T) {
+	got, err := AbbreviateIDs([]string{"urn:orders:65b2713b1267994147953b27"}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["urn:orders:65b2713b1267994147953b27"] != "urn:orders:65b2…" {
+		t.Errorf("unexpected abbreviation: %v", got)
+	}
+}
+
+func TestAbbreviateIDsUnicode(t *testing.T) {
+	got, _ := AbbreviateIDs([]string{"urn:tags:éa1", "urn:tags:éb2"}, 1)
+	if got["urn:tags:éa1"] != "urn:tags:%C3%A9a…" {
+		t.Errorf("unexpected abbreviation: %v", got)
+	}
+}
+
+func TestResolveAbbreviationAmbiguous(t *testing.T) {
+	urns := []string{"urn:orders:abc123", "urn:orders:abd999"}
+	_, err := ResolveAbbreviation(urns, "urn:orders:ab…")
+	if err == nil || !strings.Contains(err.Error(), "Ambiguous") {
+		t.Errorf("expected ambiguity error, got %v", err)
+	}
+	if _, err := ResolveAbbreviation(urns, "urn:orders:zz…"); err == nil {
+		t.Error("expected error for unknown abbreviation")
+	}
+	if _, err := ResolveAbbreviation(urns, "urn:orders:abc"); err == nil {
+		t.Error("expected abbreviation without ellipsis to require an exact ID")
+	}
+}
@@ -0,0 +1,58 @@
+package urn
+
+import "unicode/utf8"
+
+// asciiToLower lowercases only the ASCII letters in s, leaving other bytes
+// untouched. Unlike strings.ToLower it cannot change the byte length or
+// apply locale-sensitive mappings such as 'İ' to "i̇".
+func asciiToLower(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; 'A' <= c && c <= 'Z' {
+			b := []byte(s)
+			for j := i; j < len(b); j++ {
+				if c := b[j]; 'A' <= c && c <= 'Z' {
+					b[j] = c + 'a' - 'A'
+				}
+			}
+			return string(b)
+		}
+	}
+	return s
+}
+
+// EqualEntities reports whether two entities name the same entity: equal
+// after folding ASCII letters, as Equivalent and SameResource compare them.
+// Non-ASCII letters such as the Kelvin sign U+212A do not fold.
+func EqualEntities(a, b string) bool {
+	return asciiEqualFold(a, b)
+}
+
+// asciiEqualFold reports whether a and b are equal under ASCII case folding.
+func asciiEqualFold(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		ca, cb := a[i], b[i]
+		if 'A' <= ca && ca <= 'Z' {
+			ca += 'a' - 'A'
+		}
+		if 'A' <= cb && cb <= 'Z' {
+			cb += 'a' - 'A'
+		}
+		if ca != cb {
+			return false
+		}
+	}
+	return true
+}
+
+// isASCII reports whether s contains only ASCII bytes.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
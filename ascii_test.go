@@ -0,0 +1,65 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeASCIIOnly(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"urn:ORDERS:1", "urn:orders:1"},
+		{"URN:Orders:1", "urn:orders:1"},
+		// Non-ASCII letters are left alone rather than case-mapped.
+		{"urn:%C4%B0TEMS:1", "urn:%C4%B0tems:1"},
+		{"urn:%C4%B1tems:1", "urn:%C4%B1tems:1"},
+		{"urn:ABC%C4%B0:1", "urn:abc%C4%B0:1"},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.in)
+		if err != nil {
+			t.Errorf("Normalize(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if got := asciiToLower("İı"); got != "İı" {
+		t.Errorf("asciiToLower changed non-ASCII input: %q", got)
+	}
+}
+
+func TestSchemeCheckASCIIOnly(t *testing.T) {
+	if _, err := Parse("uRN:orders:1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// U+212A KELVIN SIGN folds to 'k' under Unicode rules; no such folds
+	// may sneak into the scheme.
+	p := NewProcessor(WithSchemeAliases(map[string]string{"k": "urn"}))
+	if _, err := p.Parse("K:orders:1"); err == nil {
+		t.Error("expected non-ASCII scheme to be rejected")
+	}
+}
+
+func TestCheckFlagsNonASCIIEntity(t *testing.T) {
+	for _, in := range []string{"urn:%C4%B0tems:1", "urn:items%C4%B1:1"} {
+		err := Check(in)
+		var ue *InvalidURNError
+		if !errors.As(err, &ue) || ue.Kind != KindInvalidEntity || ue.Reason != ReasonNonASCII {
+			t.Errorf("Check(%q) = %v, want non-ASCII entity error", in, err)
+		}
+	}
+	if _, err := Compose("İtems", "1"); err == nil || !strings.Contains(err.Error(), "non-ASCII") {
+		t.Errorf("Compose: got %v, want non-ASCII entity error", err)
+	}
+}
+
+func TestEqualEntities(t *testing.T) {
+	if !EqualEntities("Orders", "oRDERS") {
+		t.Error("ASCII letters should fold")
+	}
+	if EqualEntities("\u212Aeys", "keys") || EqualEntities("\u017Fhop", "shop") {
+		t.Error("non-ASCII letters should not fold")
+	}
+}
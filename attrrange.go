@@ -0,0 +1,75 @@
+package urn
+
+import "fmt"
+
+// AttrRange returns a copy of the attributes with indexes in [start, end).
+// The bounds are clamped to the available attributes, so a window past the
+// end is shortened and an empty window yields nil.
+func (u *URN) AttrRange(start, end int) []Attribute {
+	start = max(start, 0)
+	end = min(end, len(u.attributes))
+	if start >= end {
+		return nil
+	}
+	pairs := make([]Attribute, end-start)
+	copy(pairs, u.attributes[start:end])
+	return pairs
+}
+
+// AttributesRange returns the attributes of urnStr with indexes in
+// [start, end), clamped like AttrRange. Negative bounds are an error.
+// Attributes are scanned in order: those before start are checked but not
+// decoded, and scanning stops at end, so malformed attributes after the
+// window are not reported.
+func AttributesRange(urnStr string, start, end int) ([]Attribute, error) {
+	if start < 0 || end < 0 {
+		return nil, fmt.Errorf("Invalid attribute range: [%d, %d) has a negative bound", start, end)
+	}
+	o := &loadConfig().opts
+	_, _, off, err := parseHead(urnStr, o)
+	if err != nil || off < 0 || start >= end {
+		return nil, err
+	}
+	var pairs []Attribute
+	for n, i := 0, off; i >= 0 && n < end; n++ {
+		keyOff := i
+		var key, value string
+		key, i = nextSegment(urnStr, i)
+		valueOff := i
+		if i >= 0 {
+			value, i = nextSegment(urnStr, i)
+		} else if o.trailingFlag && key != "" {
+			value, valueOff = FlagValue, len(urnStr)
+		} else {
+			return nil, &InvalidURNError{
+				Kind:    KindUnpairedKey,
+				Offset:  keyOff,
+				Segment: key,
+			}
+		}
+		if key == "" || value == "" {
+			at := keyOff
+			if key != "" {
+				at = valueOff
+			}
+			return nil, &InvalidURNError{
+				Kind:    KindEmptyAttribute,
+				Reason:  ReasonValue,
+				Offset:  at,
+				Segment: key,
+				Key:     key,
+			}
+		}
+		if n < start {
+			continue
+		}
+		if key, err = unescapeAt(key, keyOff); err != nil {
+			return nil, err
+		}
+		if value, err = unescapeAt(value, valueOff); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, Attribute{Key: key, Value: value})
+	}
+	return pairs, nil
+}
@@ -0,0 +1,58 @@
+package urn
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAttrRange(t *testing.T) {
+	const s = "urn:a:1:k0:v0:k1:v1:k2:v2:k3:v3"
+	u, _ := Parse(s)
+	all := u.AttributePairs()
+	tests := []struct {
+		start, end int
+		want       []Attribute
+	}{
+		{1, 3, all[1:3]},
+		{0, 4, all},
+		{2, 100, all[2:]},
+		{4, 6, nil},
+		{10, 20, nil},
+		{3, 1, nil},
+	}
+	for _, tt := range tests {
+		if got := u.AttrRange(tt.start, tt.end); !slices.Equal(got, tt.want) {
+			t.Errorf("AttrRange(%d, %d) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+		got, err := AttributesRange(s, tt.start, tt.end)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("AttributesRange(%d, %d) = %v, %v, want %v", tt.start, tt.end, got, err, tt.want)
+		}
+	}
+	if got := u.AttrRange(-2, 1); !slices.Equal(got, all[:1]) {
+		t.Errorf("AttrRange(-2, 1) = %v", got)
+	}
+	if _, err := AttributesRange(s, -1, 2); err == nil {
+		t.Error("AttributesRange accepted a negative start")
+	}
+	if _, err := AttributesRange(s, 0, -2); err == nil {
+		t.Error("AttributesRange accepted a negative end")
+	}
+}
+
+func TestAttributesRangeStopsAtWindow(t *testing.T) {
+	const s = "urn:a:1:k0:v%41:k1:v1:k2:%zz:k3"
+	got, err := AttributesRange(s, 0, 2)
+	if want := []Attribute{{"k0", "vA"}, {"k1", "v1"}}; err != nil || !slices.Equal(got, want) {
+		t.Errorf("got %v, %v", got, err)
+	}
+	if _, err := AttributesRange(s, 2, 3); err == nil {
+		t.Error("malformed escape inside the window not reported")
+	}
+	if _, err := AttributesRange("urn:a:1::v:k1:v1", 1, 2); err == nil {
+		t.Error("empty key before the window not reported")
+	}
+	if _, err := AttributesRange("bad", 0, 1); err == nil {
+		t.Error("invalid head not reported")
+	}
+}
@@ -0,0 +1,50 @@
+package urn
+
+// Violation describes a check that ParseStrict would have failed, as
+// reported by ParseAudit. Kind, Offset and Segment are those of the error
+// ParseStrict would return, and Detail is its message.
+type Violation struct {
+	Kind    ErrorKind
+	Offset  int
+	Segment string
+	Detail  string
+}
+
+// ParseAudit parses urnStr like Parse but also runs every check of
+// ParseStrict under the same options, returning the violations instead
+// of failing. It is meant for measuring the impact of switching to
+// ParseStrict. An input that Parse rejects is an error; a clean input
+// yields no violations.
+func ParseAudit(urnStr string, opts ...Option) (*URN, []Violation, error) {
+	c := loadConfig()
+	o := c.opts
+	if len(opts) > 0 {
+		o = newOptions(append(append([]Option(nil), c.defaults...), opts...))
+	}
+	u, err := parse(urnStr, &o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sc := strictChecker{all: true}
+	sc.checkInput(urnStr)
+	if u.trailingFlag {
+		// ParseStrict does not accept a bare trailing key.
+		last := len(u.attributes) - 1
+		seg, off := segmentAt(urnStr, 2+2*last)
+		sc.fail(&InvalidURNError{
+			Kind:    KindUnpairedKey,
+			Reason:  ReasonValue,
+			Offset:  off,
+			Segment: seg,
+			Key:     u.attributes[last].Key,
+		})
+	}
+	sc.checkURN(urnStr, u, &o, c)
+
+	vs := make([]Violation, 0, len(sc.errs))
+	for _, e := range sc.errs {
+		vs = append(vs, Violation{Kind: e.Kind, Offset: e.Offset, Segment: e.Segment, Detail: e.Error()})
+	}
+	return u, vs, nil
+}
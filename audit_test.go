@@ -0,0 +1,62 @@
+package urn
+
+import "testing"
+
+func TestParseAudit(t *testing.T) {
+	RegisterIDValidator("a_b", NumericValidator)
+	SetReservedKeys("secret")
+	t.Cleanup(func() {
+		RegisterIDValidator("a_b", nil)
+		SetReservedKeys()
+	})
+
+	in := "urn:a_b:x\x01:secret:v:flag"
+	u, vs, err := ParseAudit(in, TrailingKeyAsFlag(), RejectControlChars())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Entity != "a_b" || u.ID != "x\x01" {
+		t.Errorf("parsed %+v", u)
+	}
+	want := []ErrorKind{
+		KindControlChar,   // raw control character in the input
+		KindUnpairedKey,   // bare trailing key
+		KindInvalidEntity, // '_' is not allowed in entities
+		KindInvalidID,     // NumericValidator
+		KindReservedKey,   // "secret"
+		KindControlChar,   // control character in the decoded ID
+	}
+	if len(vs) != len(want) {
+		t.Fatalf("got %d violations: %+v", len(vs), vs)
+	}
+	for i, v := range vs {
+		if v.Kind != want[i] || v.Detail == "" {
+			t.Errorf("violation %d = %+v, want kind %v", i, v, want[i])
+		}
+	}
+	if vs[4].Segment != "secret" || vs[4].Offset != len("urn:a_b:x\x01:") {
+		t.Errorf("reserved key located at %d %q", vs[4].Offset, vs[4].Segment)
+	}
+
+	// The first violation is the error ParseStrict returns.
+	_, strictErr := ParseStrict(in, RejectControlChars())
+	if strictErr == nil || strictErr.Error() != vs[0].Detail {
+		t.Errorf("ParseStrict = %v, first violation %q", strictErr, vs[0].Detail)
+	}
+}
+
+func TestParseAuditClean(t *testing.T) {
+	u, vs, err := ParseAudit("urn:user:1:k:v")
+	if err != nil || u == nil {
+		t.Fatal(err)
+	}
+	if vs == nil || len(vs) != 0 {
+		t.Errorf("violations = %#v, want an empty slice", vs)
+	}
+}
+
+func TestParseAuditParseError(t *testing.T) {
+	if _, _, err := ParseAudit("urn:user"); err == nil {
+		t.Error("ParseAudit accepted input Parse rejects")
+	}
+}
@@ -0,0 +1,34 @@
+package urn
+
+// Builder assembles a URN component by component. Attributes are emitted in
+// the order they are added.
+type Builder struct {
+	entity string
+	id     string
+	attrs  []Attribute
+}
+
+// NewBuilder starts a URN with the given entity and ID.
+func NewBuilder(entity, id string) *Builder {
+	return &Builder{entity: entity, id: id}
+}
+
+// Attr appends an attribute.
+func (b *Builder) Attr(key, value string) *Builder {
+	b.attrs = append(b.attrs, Attribute{Key: key, Value: value})
+	return b
+}
+
+// Build validates the components and returns the URN. The same rules as
+// ComposeAttrs apply, and every violation is reported.
+func (b *Builder) Build() (*URN, error) {
+	if err := validateComponents(b.entity, b.id, b.attrs); err != nil {
+		return nil, err
+	}
+	u := &URN{Entity: b.entity, ID: b.id}
+	if len(b.attrs) > 0 {
+		u.attributes = make([]Attribute, len(b.attrs))
+		copy(u.attributes, b.attrs)
+	}
+	return u, nil
+}
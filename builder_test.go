@@ -0,0 +1,28 @@
+package urn
+
+import "testing"
+
+func TestBuilder(t *testing.T) {
+	u, err := NewBuilder("order", "12345").
+		Attr("status", "shipped").
+		Attr("vendor", "amazon").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "urn:order:12345:status:shipped:vendor:amazon" {
+		t.Errorf("unexpected URN: %s", u.String())
+	}
+	if u.Raw() != "" {
+		t.Errorf("expected empty Raw for built URN, got %q", u.Raw())
+	}
+}
+
+func TestBuilderInvalid(t *testing.T) {
+	if _, err := NewBuilder("order", "1").Attr("status", "").Build(); err == nil {
+		t.Error("expected error for empty attribute value")
+	}
+	if _, err := NewBuilder("bad entity", "1").Build(); err == nil {
+		t.Error("expected error for invalid entity")
+	}
+}
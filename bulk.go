@@ -0,0 +1,156 @@
+package urn
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// contextCheckEvery is how many items the context-aware bulk operations
+// process between checks of ctx.Err().
+var contextCheckEvery = 1024
+
+// CanceledError reports that a bulk operation stopped because its context
+// was done. It wraps the context's error.
+type CanceledError struct {
+	// Processed is the number of items completed before stopping.
+	Processed int
+	Err       error
+}
+
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("Canceled after %d items: %s", e.Processed, e.Err)
+}
+
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// checkContext returns a *CanceledError when ctx is done, checking only
+// every contextCheckEvery items.
+func checkContext(ctx context.Context, processed int) error {
+	if processed%contextCheckEvery != 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return &CanceledError{Processed: processed, Err: err}
+	}
+	return nil
+}
+
+// ParseAll parses every string in urns. The result has one entry per
+// input, nil where parsing failed; the failures are returned joined, as
+// *BatchError values carrying their index.
+func ParseAll(urns []string) ([]*URN, error) {
+	return ParseAllContext(context.Background(), urns)
+}
+
+// ParseAllContext is ParseAll, stopping early with a *CanceledError when
+// ctx is done. The results parsed so far are returned with it.
+func ParseAllContext(ctx context.Context, urns []string) ([]*URN, error) {
+	out := make([]*URN, len(urns))
+	var errs []error
+	for i, s := range urns {
+		if err := checkContext(ctx, i); err != nil {
+			return out[:i], err
+		}
+		u, err := Parse(s)
+		if err != nil {
+			errs = append(errs, &BatchError{Index: i, Input: s, Err: err})
+			continue
+		}
+		out[i] = u
+	}
+	return out, errors.Join(errs...)
+}
+
+// Scanner reads URNs from a reader, one per line. Surrounding whitespace
+// and blank lines are skipped. Scanning stops at the first read or parse
+// error, or when its context is done.
+type Scanner struct {
+	ctx  context.Context
+	sc   *bufio.Scanner
+	line int
+	n    int
+	u    *URN
+	err  error
+}
+
+// NewScanner returns a Scanner reading from r.
+func NewScanner(r io.Reader) *Scanner {
+	return NewScannerContext(context.Background(), r)
+}
+
+// NewScannerContext returns a Scanner that stops with a *CanceledError
+// once ctx is done.
+func NewScannerContext(ctx context.Context, r io.Reader) *Scanner {
+	return &Scanner{ctx: ctx, sc: bufio.NewScanner(r)}
+}
+
+// Scan advances to the next URN, reporting whether there is one.
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	if debugGuards {
+		defer recoverPanic("Scanner.Scan", &s.err)
+	}
+	s.u = nil
+	for s.sc.Scan() {
+		s.line++
+		text := strings.TrimSpace(s.sc.Text())
+		if text == "" {
+			continue
+		}
+		if s.err = checkContext(s.ctx, s.n); s.err != nil {
+			return false
+		}
+		u, err := Parse(text)
+		if err != nil {
+			s.err = fmt.Errorf("line %d: %w", s.line, err)
+			return false
+		}
+		s.u = u
+		s.n++
+		return true
+	}
+	s.err = s.sc.Err()
+	return false
+}
+
+// URN returns the URN read by the last successful Scan.
+func (s *Scanner) URN() *URN {
+	return s.u
+}
+
+// Err returns the error that stopped scanning, or nil at end of input.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// EncodeJSONArrayContext is EncodeJSONArray, stopping with a
+// *CanceledError when ctx is done. The elements encoded before that stay
+// written, closed by the final ']'.
+func EncodeJSONArrayContext(ctx context.Context, w io.Writer, urns iter.Seq[*URN]) error {
+	var cancelErr error
+	n := 0
+	err := EncodeJSONArray(w, func(yield func(*URN) bool) {
+		for u := range urns {
+			if cancelErr = checkContext(ctx, n); cancelErr != nil {
+				return
+			}
+			if !yield(u) {
+				return
+			}
+			n++
+		}
+	})
+	if cancelErr != nil {
+		return cancelErr
+	}
+	return err
+}
@@ -0,0 +1,119 @@
+package urn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// cancelAfter is a context that reports itself canceled after its Err
+// method has been called n times.
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func checkEvery(t *testing.T, n int) {
+	old := contextCheckEvery
+	contextCheckEvery = n
+	t.Cleanup(func() { contextCheckEvery = old })
+}
+
+func manyURNs(n int) []string {
+	urns := make([]string, n)
+	for i := range urns {
+		urns[i] = fmt.Sprintf("urn:orders:%d", i)
+	}
+	return urns
+}
+
+func TestParseAll(t *testing.T) {
+	got, err := ParseAll([]string{"urn:a:1", "bad", "urn:b:2"})
+	var be *BatchError
+	if !errors.As(err, &be) || be.Index != 1 {
+		t.Errorf("err = %v", err)
+	}
+	if len(got) != 3 || got[0].ID != "1" || got[1] != nil || got[2].ID != "2" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestParseAllContextCanceled(t *testing.T) {
+	checkEvery(t, 10)
+	ctx := &cancelAfter{Context: context.Background(), n: 3}
+	got, err := ParseAllContext(ctx, manyURNs(100))
+	var ce *CanceledError
+	if !errors.As(err, &ce) || !errors.Is(err, context.Canceled) || ce.Processed != 30 {
+		t.Fatalf("err = %v", err)
+	}
+	if len(got) != 30 || got[29].ID != "29" {
+		t.Errorf("returned %d results", len(got))
+	}
+
+	cctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ParseAllContext(cctx, manyURNs(5)); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled context: %v", err)
+	}
+}
+
+func TestScanner(t *testing.T) {
+	sc := NewScanner(strings.NewReader("urn:a:1\n\n  urn:b:2  \nbad\nurn:c:3\n"))
+	var ids []string
+	for sc.Scan() {
+		ids = append(ids, sc.URN().ID)
+	}
+	if !slices.Equal(ids, []string{"1", "2"}) {
+		t.Errorf("ids = %v", ids)
+	}
+	if err := sc.Err(); err == nil || !strings.HasPrefix(err.Error(), "line 4: ") {
+		t.Errorf("Err = %v", err)
+	}
+}
+
+func TestScannerContextCanceled(t *testing.T) {
+	checkEvery(t, 10)
+	ctx := &cancelAfter{Context: context.Background(), n: 2}
+	sc := NewScannerContext(ctx, strings.NewReader(strings.Join(manyURNs(100), "\n")))
+	n := 0
+	for sc.Scan() {
+		n++
+	}
+	var ce *CanceledError
+	if !errors.As(sc.Err(), &ce) || ce.Processed != 20 || n != 20 {
+		t.Errorf("scanned %d, err = %v", n, sc.Err())
+	}
+}
+
+func TestEncodeJSONArrayContextCanceled(t *testing.T) {
+	checkEvery(t, 10)
+	ctx := &cancelAfter{Context: context.Background(), n: 1}
+	seq := func(yield func(*URN) bool) {
+		for _, s := range manyURNs(100) {
+			u, _ := Parse(s)
+			if !yield(u) {
+				return
+			}
+		}
+	}
+	var buf bytes.Buffer
+	err := EncodeJSONArrayContext(ctx, &buf, seq)
+	var ce *CanceledError
+	if !errors.As(err, &ce) || ce.Processed != 10 {
+		t.Fatalf("err = %v", err)
+	}
+	if n := strings.Count(buf.String(), "urn:"); n != 10 {
+		t.Errorf("encoded %d elements", n)
+	}
+}
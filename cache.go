@@ -0,0 +1,107 @@
+package urn
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CachingParser wraps Parse with a bounded LRU cache keyed on the input
+// string. It is safe for concurrent use. Cached URNs and errors are never
+// handed out directly; every call returns a fresh copy so callers cannot
+// poison the cache by mutating the result. Entries record the package
+// configuration they were parsed under, and an entry parsed before a
+// later SetDefaults or registration is parsed again.
+type CachingParser struct {
+	mu          sync.Mutex
+	size        int
+	cacheErrors bool
+	ll          *list.List
+	entries     map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key string
+	cfg *config
+	urn *URN
+	err error
+}
+
+// NewCachingParser creates a CachingParser holding at most size entries.
+// A size below 1 is treated as 1.
+func NewCachingParser(size int) *CachingParser {
+	if size < 1 {
+		size = 1
+	}
+	return &CachingParser{
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// WithNegativeCaching makes the parser cache parse errors as well as
+// successful results. It must be called before the parser is shared.
+func (p *CachingParser) WithNegativeCaching() *CachingParser {
+	p.cacheErrors = true
+	return p
+}
+
+// Parse returns the parsed URN for s, consulting the cache first.
+func (p *CachingParser) Parse(s string) (*URN, error) {
+	// Loaded before parsing, so a change made during Parse leaves the entry
+	// stale rather than mislabelled.
+	cfg := loadConfig()
+	p.mu.Lock()
+	if el, ok := p.entries[s]; ok && el.Value.(*cacheEntry).cfg == cfg {
+		p.ll.MoveToFront(el)
+		e := el.Value.(*cacheEntry)
+		p.mu.Unlock()
+		if e.err != nil {
+			return nil, cloneError(e.err)
+		}
+		return e.urn.Clone(), nil
+	}
+	p.mu.Unlock()
+
+	u, err := Parse(s)
+	if err != nil && !p.cacheErrors {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if el, ok := p.entries[s]; ok {
+		// Replace a stale entry in place.
+		el.Value = &cacheEntry{key: s, cfg: cfg, urn: u, err: err}
+		p.ll.MoveToFront(el)
+	} else {
+		p.entries[s] = p.ll.PushFront(&cacheEntry{key: s, cfg: cfg, urn: u, err: err})
+		if p.ll.Len() > p.size {
+			oldest := p.ll.Back()
+			p.ll.Remove(oldest)
+			delete(p.entries, oldest.Value.(*cacheEntry).key)
+		}
+	}
+	if err != nil {
+		return nil, cloneError(err)
+	}
+	return u.Clone(), nil
+}
+
+// cloneError returns a copy of a cached parse error, so that a caller
+// changing the fields of an *InvalidURNError does not change what later
+// callers see. Other errors are returned as they are.
+func cloneError(err error) error {
+	if ue, ok := err.(*InvalidURNError); ok {
+		c := *ue
+		return &c
+	}
+	return err
+}
+
+// Len returns the number of cached entries.
+func (p *CachingParser) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.ll.Len()
+}
@@ -0,0 +1,136 @@
+package urn
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCachingParserReturnsClones(t *testing.T) {
+	p := NewCachingParser(8)
+	u, err := p.Parse("urn:orders:1234:status:pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Entity = "poisoned"
+	// Attributes are shared copy-on-write, so change them the way the
+	// package does.
+	u.set(&options{}, "status", "poisoned")
+
+	again, err := p.Parse("urn:orders:1234:status:pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Entity != "orders" || again.Attributes()["status"] != "pending" {
+		t.Errorf("cache was poisoned: %+v", again)
+	}
+}
+
+func TestCachingParserEviction(t *testing.T) {
+	p := NewCachingParser(2)
+	p.Parse("urn:a:1")
+	p.Parse("urn:b:2")
+	p.Parse("urn:a:1")
+	p.Parse("urn:c:3")
+	if p.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", p.Len())
+	}
+	p.mu.Lock()
+	_, hasA := p.entries["urn:a:1"]
+	_, hasB := p.entries["urn:b:2"]
+	p.mu.Unlock()
+	if !hasA || hasB {
+		t.Errorf("expected urn:b:2 to be evicted (hasA=%v hasB=%v)", hasA, hasB)
+	}
+}
+
+func TestCachingParserErrors(t *testing.T) {
+	p := NewCachingParser(4)
+	if _, err := p.Parse("invalid"); err == nil {
+		t.Fatal("expected error")
+	}
+	if p.Len() != 0 {
+		t.Errorf("errors should not be cached by default")
+	}
+
+	p = NewCachingParser(4).WithNegativeCaching()
+	if _, err := p.Parse("invalid"); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := p.Parse("invalid"); err == nil {
+		t.Fatal("expected cached error")
+	}
+	if p.Len() != 1 {
+		t.Errorf("expected cached error entry, got %d entries", p.Len())
+	}
+}
+
+func TestCachingParserConcurrent(t *testing.T) {
+	p := NewCachingParser(16)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				s := fmt.Sprintf("urn:orders:%d:worker:%d", i%32, g)
+				u, err := p.Parse(s)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if u.String() != s {
+					t.Errorf("expected %s, got %s", s, u.String())
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func BenchmarkParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Parse("urn:orders:1234:vendor:amazon:status:shipped")
+	}
+}
+
+func BenchmarkCachingParserHit(b *testing.B) {
+	p := NewCachingParser(16)
+	for i := 0; i < b.N; i++ {
+		p.Parse("urn:orders:1234:vendor:amazon:status:shipped")
+	}
+}
+
+func TestCachingParserFollowsDefaults(t *testing.T) {
+	p := NewCachingParser(4).WithNegativeCaching()
+	if _, err := p.Parse("urn:a:1:b:2:c:3"); err != nil {
+		t.Fatal(err)
+	}
+	SetDefaults(WithMaxSegments(4))
+	t.Cleanup(func() { SetDefaults() })
+	if _, err := p.Parse("urn:a:1:b:2:c:3"); err == nil {
+		t.Error("cached result ignored SetDefaults")
+	}
+	SetDefaults()
+	if _, err := p.Parse("urn:a:1:b:2:c:3"); err != nil {
+		t.Errorf("cached error outlived SetDefaults: %v", err)
+	}
+	if p.Len() != 1 {
+		t.Errorf("stale entries were not replaced: %d entries", p.Len())
+	}
+}
+
+func TestCachingParserCopiesErrors(t *testing.T) {
+	p := NewCachingParser(4).WithNegativeCaching()
+	_, err := p.Parse("invalid")
+	ue, ok := err.(*InvalidURNError)
+	if !ok {
+		t.Fatalf("err = %T, want *InvalidURNError", err)
+	}
+	ue.Message = "poisoned"
+	_, err = p.Parse("invalid")
+	if err.Error() == "poisoned" {
+		t.Error("cached error was poisoned")
+	}
+}
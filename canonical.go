@@ -0,0 +1,208 @@
+package urn
+
+import (
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CanonicalOrder documents how Canonical, Hash and Set order attributes:
+//
+//   - Attributes sort by key, then by value.
+//   - Keys and values compare byte-wise on their escaped form, exactly as
+//     EscapeSegment produces it. Comparison is case-sensitive, so "B"
+//     sorts before "a", and "a%2Fb" (decoded "a/b") sorts before "a.b"
+//     because '%' < '.'.
+//   - Duplicate keys are kept, ordered by value; pairs equal in both key
+//     and value are kept as duplicates.
+//
+// Comparing escaped bytes lets any implementation reproduce the order from
+// the URN string alone, without decoding.
+const CanonicalOrder = "escaped-bytewise-key-then-value"
+
+// CompareAttributes compares two attributes under CanonicalOrder, returning
+// -1, 0 or +1.
+func CompareAttributes(a, b Attribute) int {
+	if c := compareEscaped(a.Key, b.Key); c != 0 {
+		return c
+	}
+	return compareEscaped(a.Value, b.Value)
+}
+
+// compareEscaped compares escape(a) and escape(b) without building them.
+func compareEscaped(a, b string) int {
+	var ea, eb [3]byte
+	var na, nb int // pending escaped bytes of the current input byte
+	i, j := 0, 0
+	for {
+		if na == 0 && i < len(a) {
+			na = escapedBytes(a[i], &ea)
+			i++
+		}
+		if nb == 0 && j < len(b) {
+			nb = escapedBytes(b[j], &eb)
+			j++
+		}
+		switch {
+		case na == 0 && nb == 0:
+			return 0
+		case na == 0:
+			return -1
+		case nb == 0:
+			return 1
+		}
+		ca, cb := ea[len(ea)-na], eb[len(eb)-nb]
+		if ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+		na--
+		nb--
+	}
+}
+
+// escapedBytes writes the escaped form of c right-aligned into buf and
+// returns its length.
+func escapedBytes(c byte, buf *[3]byte) int {
+	if shouldEscape(c) {
+		buf[0], buf[1], buf[2] = '%', upperhex[c>>4], upperhex[c&15]
+		return 3
+	}
+	buf[2] = c
+	return 1
+}
+
+// Canonical returns the canonical string form of the URN: the "urn"
+// scheme, the entity with ASCII letters lowercased, and the attributes in
+// CanonicalOrder, all escaped as by EscapeSegment. Two URNs describing the
+// same resource and attributes have the same canonical form. A valid
+// checksum attribute is left out, and the length limit is not applied.
+// Attributes left unparsed under MaxAttributesParsed follow verbatim.
+func (u *URN) Canonical() string {
+	return canonicalForm(u.Entity, u.ID, u.userAttributes(), u.rest)
+}
+
+// canonicalForm builds the canonical form of the given components,
+// sorting pairs in place.
+func canonicalForm(entity, id string, pairs []Attribute, rest string) string {
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return CompareAttributes(pairs[i], pairs[j]) < 0
+	})
+	var b strings.Builder
+	b.WriteString("urn:")
+	b.WriteString(escape(asciiToLower(entity)))
+	b.WriteByte(':')
+	b.WriteString(escape(id))
+	for _, p := range pairs {
+		b.WriteByte(':')
+		b.WriteString(escape(p.Key))
+		b.WriteByte(':')
+		b.WriteString(escape(p.Value))
+	}
+	if rest != "" {
+		// Only the parsed attributes can be reordered.
+		b.WriteByte(':')
+		b.WriteString(rest)
+	}
+	return b.String()
+}
+
+// Canonical parses urnStr and returns its canonical form. See
+// (*URN).Canonical. Normalizers registered with RegisterNormalizer in
+// SetDefaults run before the attributes are sorted.
+func Canonical(urnStr string) (string, error) {
+	return defaultProcessor.Canonical(urnStr)
+}
+
+// Hash returns the 64-bit FNV-1a hash of the canonical form, so it is
+// stable across processes and equal for URNs with equal canonical forms.
+func (u *URN) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(u.Canonical()))
+	return h.Sum64()
+}
+
+// Set is a set of URNs keyed by canonical form, so attribute order and
+// entity case do not create distinct members. For entities with an
+// IDComparator registered before their members are added, IDs match with
+// the comparator and the first member added keeps its ID. The zero value
+// is an empty set ready to use. A Set is not safe for concurrent use.
+type Set struct {
+	m map[string]*URN
+	// shapes indexes the keys of members with an IDComparator by shape.
+	shapes map[string][]string
+}
+
+// find returns the key of the member matching u, or u's own key.
+func (s *Set) find(u *URN) (key string, found bool) {
+	key = u.Canonical()
+	if _, ok := s.m[key]; ok {
+		return key, true
+	}
+	if cmp := loadConfig().idComparator(u.Entity); cmp != nil {
+		for _, k := range s.shapes[u.shape()] {
+			if cmp(s.m[k].ID, u.ID) {
+				return k, true
+			}
+		}
+	}
+	return key, false
+}
+
+// Add inserts u and reports whether it was not already present.
+func (s *Set) Add(u *URN) bool {
+	key, found := s.find(u)
+	if found {
+		return false
+	}
+	if s.m == nil {
+		s.m = make(map[string]*URN)
+	}
+	s.m[key] = u.Clone()
+	if loadConfig().idComparator(u.Entity) != nil {
+		if s.shapes == nil {
+			s.shapes = make(map[string][]string)
+		}
+		shape := u.shape()
+		s.shapes[shape] = append(s.shapes[shape], key)
+	}
+	return true
+}
+
+// Contains reports whether a URN with the same canonical form is present.
+func (s *Set) Contains(u *URN) bool {
+	_, found := s.find(u)
+	return found
+}
+
+// Remove deletes u and reports whether it was present.
+func (s *Set) Remove(u *URN) bool {
+	key, found := s.find(u)
+	if !found {
+		return false
+	}
+	shape := s.m[key].shape()
+	delete(s.m, key)
+	if keys := s.shapes[shape]; keys != nil {
+		s.shapes[shape] = slices.DeleteFunc(keys, func(k string) bool { return k == key })
+	}
+	return true
+}
+
+// Len returns the number of members.
+func (s *Set) Len() int {
+	return len(s.m)
+}
+
+// Canonical returns the canonical forms of the members in sorted order.
+func (s *Set) Canonical() []string {
+	keys := make([]string, 0, len(s.m))
+	for k := range s.m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+package urn
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestCanonicalGolden(t *testing.T) {
+	inputs := []string{
+		// Keys differing only by case: uppercase sorts first.
+		"urn:Orders:1:b:1:B:2:a:3:A:4",
+		// Escaped order differs from decoded order.
+		"urn:orders:1:a.b:1:a%2Fb:2",
+		"urn:orders:1:k:a.b:k:a%2Fb",
+		// Duplicate keys with different values.
+		"urn:orders:1:tag:red:tag:blue:tag:green:tag:blue",
+		// Lowercase hex and needlessly escaped input are re-escaped.
+		"URN:orders:a%2fb:z:%41:y:%c3%a9",
+		"urn:orders:1",
+	}
+	var b strings.Builder
+	for _, in := range inputs {
+		c, err := Canonical(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.WriteString(in + "\n\t" + c + "\n")
+	}
+	got := b.String()
+
+	golden := filepath.Join("testdata", "canonical.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("canonical forms differ from %s:\n%s", golden, got)
+	}
+}
+
+func TestCompareAttributes(t *testing.T) {
+	tests := []struct {
+		a, b Attribute
+		want int
+	}{
+		{Attribute{"a", "1"}, Attribute{"b", "1"}, -1},
+		{Attribute{"B", "1"}, Attribute{"a", "1"}, -1},
+		{Attribute{"a/b", "1"}, Attribute{"a.b", "1"}, -1}, // "%2F" < "."
+		{Attribute{"a", "x"}, Attribute{"a", "x"}, 0},
+		{Attribute{"a", "y"}, Attribute{"a", "x"}, 1},
+		{Attribute{"ab", "1"}, Attribute{"a", "1"}, 1},
+		{Attribute{"a\xff", "1"}, Attribute{"a~", "1"}, -1}, // "%FF" < "~"
+	}
+	for _, tt := range tests {
+		if got := CompareAttributes(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareAttributes(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := strings.Compare(
+			escape(tt.a.Key)+"\x00"+escape(tt.a.Value),
+			escape(tt.b.Key)+"\x00"+escape(tt.b.Value)); got != tt.want {
+			t.Errorf("escaped comparison of %v, %v = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHashAndSet(t *testing.T) {
+	a, _ := Parse("urn:orders:1:vendor:amazon:status:new")
+	b, _ := Parse("urn:ORDERS:1:status:new:vendor:amazon")
+	c, _ := Parse("urn:orders:1:status:new")
+	if a.Hash() != b.Hash() {
+		t.Error("equivalent URNs hash differently")
+	}
+	if a.Hash() == c.Hash() {
+		t.Error("different URNs hash equally")
+	}
+
+	var s Set
+	if !s.Add(a) || s.Add(b) || !s.Add(c) {
+		t.Error("unexpected Add results")
+	}
+	if s.Len() != 2 || !s.Contains(b) {
+		t.Errorf("unexpected set: %v", s.Canonical())
+	}
+	if !s.Remove(b) || s.Contains(a) || s.Len() != 1 {
+		t.Error("Remove did not use canonical form")
+	}
+}
@@ -0,0 +1,35 @@
+package urn
+
+// RemainingCapacity returns how many more bytes the composed form of urnStr
+// can grow before it reaches MaxURNLength. Lengths are measured on the
+// canonical "urn:" form with escaping applied, as Compose emits it, so the
+// result can be negative for input that only fits the limit because it
+// leaves characters unescaped.
+func RemainingCapacity(urnStr string) (int, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return 0, err
+	}
+	return MaxURNLength - composedLen(u.Entity, u.ID, u.attributes), nil
+}
+
+// FitsWithAttribute reports whether urnStr still fits MaxURNLength once
+// the attribute key=value is appended. Each byte that Compose escapes is
+// counted as the three bytes of its %XX form. When the attribute does not
+// fit, missing is the number of bytes by which it exceeds the limit;
+// otherwise missing is 0. A key or value the attribute policy rejects is
+// an error.
+func FitsWithAttribute(urnStr, key, value string) (fits bool, missing int, err error) {
+	remaining, err := RemainingCapacity(urnStr)
+	if err != nil {
+		return false, 0, err
+	}
+	if err := validatePairs(loadConfig(), []Attribute{{Key: key, Value: value}}); err != nil {
+		return false, 0, err
+	}
+	need := 2 + escapedLen(key) + escapedLen(value)
+	if need > remaining {
+		return false, need - remaining, nil
+	}
+	return true, 0, nil
+}
@@ -0,0 +1,67 @@
+package urn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRemainingCapacity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"urn:user:1", MaxURNLength - len("urn:user:1")},
+		{"URN:user:1:k:v", MaxURNLength - len("urn:user:1:k:v")},
+		// Escapes count at their encoded length.
+		{"urn:user:a%3Ab", MaxURNLength - len("urn:user:a%3Ab")},
+		{"urn:user:" + strings.Repeat("x", MaxURNLength-len("urn:user:")), 0},
+	}
+	for _, tt := range tests {
+		got, err := RemainingCapacity(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("RemainingCapacity(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := RemainingCapacity("bad"); err == nil {
+		t.Error("RemainingCapacity accepted an invalid URN")
+	}
+}
+
+func TestFitsWithAttribute(t *testing.T) {
+	base := "urn:user:1"
+	room := MaxURNLength - len(base) - len(":k:")
+
+	// Every ':' escapes to three bytes, tripling the value's length.
+	value := strings.Repeat(":", room/3)
+	fits, missing, err := FitsWithAttribute(base, "k", value)
+	if err != nil || !fits || missing != 0 {
+		t.Errorf("fitting value: %v, %d, %v", fits, missing, err)
+	}
+	s, err := ComposeAttrs("user", "1", []Attribute{{Key: "k", Value: value}})
+	if err != nil || len(s) > MaxURNLength {
+		t.Errorf("composing the fitting value: %d bytes, %v", len(s), err)
+	}
+
+	value += ":"
+	fits, missing, err = FitsWithAttribute(base, "k", value)
+	if want := 3*len(value) - room; err != nil || fits || missing != want {
+		t.Errorf("oversized value: %v, %d, %v; want missing %d", fits, missing, err, want)
+	}
+	if _, err := ComposeAttrs("user", "1", []Attribute{{Key: "k", Value: value}}); err == nil {
+		t.Error("ComposeAttrs accepted the value FitsWithAttribute rejected")
+	}
+
+	// A raw value of exactly the remaining room fits; one more byte misses by one.
+	fits, missing, _ = FitsWithAttribute(base, "k", strings.Repeat("v", room))
+	if !fits || missing != 0 {
+		t.Errorf("exact fit: %v, %d", fits, missing)
+	}
+	fits, missing, _ = FitsWithAttribute(base, "k", strings.Repeat("v", room+1))
+	if fits || missing != 1 {
+		t.Errorf("one over: %v, %d", fits, missing)
+	}
+
+	if _, _, err := FitsWithAttribute(base, "k", ""); err == nil {
+		t.Error("FitsWithAttribute accepted an empty value")
+	}
+}
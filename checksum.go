@@ -0,0 +1,92 @@
+package urn
+
+import (
+	"hash/crc32"
+	"slices"
+	"strings"
+)
+
+// ChecksumKey is the attribute under which WithChecksum stores a URN's
+// checksum. An attribute under it whose value is the checksum of the rest
+// of the URN is ignored by Canonical, Equal and Equivalent; any other
+// value is ordinary data. The key is not reserved unless passed to
+// SetReservedKeys.
+const ChecksumKey = "c"
+
+// checksumAlphabet is Crockford's base32, which avoids the easily confused
+// letters I, L, O and U.
+const checksumAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Checksum returns a 4-character base32 check code for urnStr: the low 20
+// bits of the CRC-32 of its canonical form. Because the canonical form
+// sorts attributes and folds entity case, reordering attributes or
+// changing entity case does not change the checksum; an existing valid
+// checksum attribute is ignored.
+func Checksum(urnStr string) (string, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return "", err
+	}
+	return u.checksum(), nil
+}
+
+func (u *URN) checksum() string {
+	return checksumOf(u.Canonical())
+}
+
+// checksumOf returns the check code of a canonical form.
+func checksumOf(canonical string) string {
+	sum := crc32.ChecksumIEEE([]byte(canonical))
+	var b [4]byte
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = checksumAlphabet[sum&31]
+		sum >>= 5
+	}
+	return string(b[:])
+}
+
+// WithChecksum returns urnStr with its checksum stored under ChecksumKey,
+// replacing a previous valid checksum. An attribute under ChecksumKey that
+// does not verify is kept, and covered by the new checksum.
+func WithChecksum(urnStr string) (string, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return "", err
+	}
+	pairs := append(u.userAttributes(), Attribute{Key: ChecksumKey, Value: u.checksum()})
+	return composeRest("urn", u.Entity, u.ID, pairs, u.rest)
+}
+
+// VerifyChecksum reports whether the checksum attribute of urnStr matches
+// the rest of the URN. It reports false when there is no checksum. The
+// comparison ignores case and reads I and L as 1 and O as 0, as Crockford's
+// base32 prescribes for codes read aloud.
+func VerifyChecksum(urnStr string) (bool, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return false, err
+	}
+	return u.checksumIndex() >= 0, nil
+}
+
+// checksumIndex returns the index of the first attribute under
+// ChecksumKey whose value is the checksum of the URN without that
+// attribute, or -1.
+func (u *URN) checksumIndex() int {
+	for i, p := range u.attributes {
+		if p.Key != ChecksumKey {
+			continue
+		}
+		others := slices.Delete(slices.Clone(u.attributes), i, i+1)
+		if normalizeChecksum(p.Value) == checksumOf(canonicalForm(u.Entity, u.ID, others, u.rest)) {
+			return i
+		}
+	}
+	return -1
+}
+
+var checksumReplacer = strings.NewReplacer("I", "1", "L", "1", "O", "0")
+
+func normalizeChecksum(s string) string {
+	return checksumReplacer.Replace(strings.ToUpper(s))
+}
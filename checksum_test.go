@@ -0,0 +1,106 @@
+package urn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithChecksum(t *testing.T) {
+	s, err := WithChecksum("urn:orders:1234:vendor:amazon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, _ := Checksum("urn:orders:1234:vendor:amazon")
+	if len(sum) != 4 || s != "urn:orders:1234:vendor:amazon:c:"+sum {
+		t.Errorf("got %s (checksum %s)", s, sum)
+	}
+	if ok, err := VerifyChecksum(s); !ok || err != nil {
+		t.Errorf("VerifyChecksum(%s) = %v, %v", s, ok, err)
+	}
+	if ok, _ := VerifyChecksum(strings.ToLower(s)); !ok {
+		t.Error("checksum comparison should ignore case")
+	}
+	if !IsValid(s) {
+		t.Error("checksummed URN should pass Check")
+	}
+
+	again, _ := WithChecksum(s)
+	if again != s {
+		t.Errorf("WithChecksum is not idempotent: %s", again)
+	}
+}
+
+func TestVerifyChecksumTampered(t *testing.T) {
+	s, _ := WithChecksum("urn:orders:1234:vendor:amazon")
+	tampered := strings.Replace(s, ":1234:", ":1243:", 1)
+	if ok, _ := VerifyChecksum(tampered); ok {
+		t.Error("transposed ID digits not detected")
+	}
+	if ok, _ := VerifyChecksum("urn:orders:1234"); ok {
+		t.Error("missing checksum reported as valid")
+	}
+}
+
+func TestChecksumIgnoresAttributeOrder(t *testing.T) {
+	// Reordering attributes keeps the canonical form, so it keeps the
+	// checksum valid.
+	s, _ := WithChecksum("urn:orders:1:a:1:b:2")
+	sum, _, _ := Value(s, ChecksumKey)
+	reordered := "urn:orders:1:b:2:c:" + sum + ":a:1"
+	if ok, _ := VerifyChecksum(reordered); !ok {
+		t.Error("reordering attributes invalidated the checksum")
+	}
+}
+
+func TestChecksumKeyIsUserData(t *testing.T) {
+	s, err := Compose("orders", "1", map[string]string{ChecksumKey: "red"})
+	if err != nil {
+		t.Fatalf("user key %q rejected: %v", ChecksumKey, err)
+	}
+	red, _ := Parse(s)
+	blue, _ := Parse("urn:orders:1:c:blue")
+	if red.Equal(blue) || red.Equivalent(blue) || red.Canonical() == blue.Canonical() || red.Hash() == blue.Hash() {
+		t.Error("an attribute that is not a checksum was ignored")
+	}
+	var set Set
+	set.Add(red)
+	if set.Add(blue); set.Len() != 2 {
+		t.Errorf("Set merged distinct URNs: %v", set.Canonical())
+	}
+
+	// A user value is kept and covered by the checksum added next to it.
+	sum, err := WithChecksum(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := VerifyChecksum(sum); !ok {
+		t.Errorf("VerifyChecksum(%s) = false", sum)
+	}
+	if u, _ := Parse(sum); !u.Equal(red) {
+		t.Errorf("%s should equal %s", sum, s)
+	}
+
+	SetReservedKeys(ChecksumKey)
+	t.Cleanup(func() { SetReservedKeys() })
+	if _, err := Compose("orders", "1", map[string]string{ChecksumKey: "X"}); err == nil {
+		t.Error("expected the reserved checksum key to be rejected")
+	}
+	if IsValid(sum) {
+		t.Errorf("reserved user value passed Check: %s", sum)
+	}
+	if valid, _ := WithChecksum("urn:orders:1:a:1"); !IsValid(valid) {
+		t.Error("a valid checksum should pass Check when its key is reserved")
+	}
+}
+
+func TestChecksumIgnoredInComparisons(t *testing.T) {
+	a, _ := WithChecksum("urn:orders:1:a:1")
+	ua, _ := Parse(a)
+	ub, _ := Parse("urn:orders:1:a:1")
+	if !ua.Equal(ub) || !ua.Equivalent(ub) {
+		t.Error("checksum attribute should not affect comparisons")
+	}
+	if id, _ := Identity(a); id != "urn:orders:1" {
+		t.Errorf("Identity = %s", id)
+	}
+}
@@ -0,0 +1,68 @@
+package urn
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type uuidSourceHolder struct {
+	f func() uuid.UUID
+}
+
+type clockHolder struct {
+	f func() time.Time
+}
+
+var (
+	uuidSource atomic.Pointer[uuidSourceHolder]
+	clock      atomic.Pointer[clockHolder]
+)
+
+// SetUUIDSource makes CreateUUID and UUIDGenerator take their UUIDs from f
+// instead of uuid.New, for deterministic tests. Passing nil restores
+// uuid.New. The returned function reinstates the previous source and
+// suits t.Cleanup:
+//
+//	t.Cleanup(urn.SetUUIDSource(urntest.FixedUUIDSource(id)))
+//
+// The source is package-wide, so tests that set it must not run in
+// parallel with tests that generate UUIDs.
+func SetUUIDSource(f func() uuid.UUID) (restore func()) {
+	var h *uuidSourceHolder
+	if f != nil {
+		h = &uuidSourceHolder{f: f}
+	}
+	prev := uuidSource.Swap(h)
+	return func() { uuidSource.Store(prev) }
+}
+
+// SetClock makes the helpers that read the current time, such as
+// ExpiresIn and Expired, call f instead of time.Now. Passing nil restores
+// time.Now. Like SetUUIDSource, it returns a function reinstating the
+// previous clock.
+func SetClock(f func() time.Time) (restore func()) {
+	var h *clockHolder
+	if f != nil {
+		h = &clockHolder{f: f}
+	}
+	prev := clock.Swap(h)
+	return func() { clock.Store(prev) }
+}
+
+// newUUID returns a UUID from the installed source.
+func newUUID() uuid.UUID {
+	if h := uuidSource.Load(); h != nil {
+		return h.f()
+	}
+	return uuid.New()
+}
+
+// currentTime returns the time from the installed clock.
+func currentTime() time.Time {
+	if h := clock.Load(); h != nil {
+		return h.f()
+	}
+	return time.Now()
+}
@@ -0,0 +1,55 @@
+package urn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSetUUIDSource(t *testing.T) {
+	ids := []uuid.UUID{
+		uuid.MustParse("00000000-0000-4000-8000-000000000001"),
+		uuid.MustParse("00000000-0000-4000-8000-000000000002"),
+	}
+	n := 0
+	restore := SetUUIDSource(func() uuid.UUID {
+		id := ids[n%len(ids)]
+		n++
+		return id
+	})
+	t.Cleanup(restore)
+
+	if got := CreateUUID("orders"); got != "urn:orders:00000000-0000-4000-8000-000000000001" {
+		t.Errorf("CreateUUID = %q", got)
+	}
+	if got, _ := New("orders", UUIDGenerator); got != "urn:orders:00000000-0000-4000-8000-000000000002" {
+		t.Errorf("New = %q", got)
+	}
+
+	inner := SetUUIDSource(nil)
+	if got := CreateUUID("orders"); got == "urn:orders:"+ids[0].String() || got == "urn:orders:"+ids[1].String() {
+		t.Errorf("CreateUUID after SetUUIDSource(nil) = %q, want random", got)
+	}
+	inner()
+	if got := CreateUUID("orders"); got != "urn:orders:"+ids[0].String() {
+		t.Errorf("CreateUUID after restore = %q, want the injected source again", got)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	t.Cleanup(SetClock(func() time.Time { return at }))
+
+	s, err := ExpiresIn("urn:job:1", time.Hour)
+	if err != nil || s != "urn:job:1:exp:1704168245" {
+		t.Fatalf("ExpiresIn = %q, %v", s, err)
+	}
+	if expired, _ := Expired(s); expired {
+		t.Error("Expired before the expiry")
+	}
+	at = at.Add(time.Hour)
+	if expired, _ := Expired(s); !expired {
+		t.Error("not Expired at the expiry")
+	}
+}
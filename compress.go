@@ -0,0 +1,74 @@
+package urn
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CompressedMarker prefixes attribute values stored by
+// SetCompressedAttribute in compressed form.
+const CompressedMarker = "~z~"
+
+// maxDecompressedLen bounds the output of CompressedValue so a crafted
+// value cannot expand without limit.
+const maxDecompressedLen = 64 << 10
+
+// SetCompressedAttribute sets key to value like AddAttribute, storing the
+// value deflate-compressed and base64url-encoded behind CompressedMarker
+// when that is shorter than the escaped plain value. Values that already
+// start with CompressedMarker are always compressed, so CompressedValue
+// can tell the two forms apart. Read the value back with CompressedValue.
+func SetCompressedAttribute(urnStr, key, value string) (string, error) {
+	stored := value
+	if c := compressValue(value); len(c) < escapedLen(value) || strings.HasPrefix(value, CompressedMarker) {
+		stored = c
+	}
+	return AddAttribute(urnStr, key, stored)
+}
+
+// CompressedValue returns the value of key, decompressing it when it was
+// stored compressed by SetCompressedAttribute. Corrupt compressed data is
+// an error.
+func CompressedValue(urnStr, key string) (string, bool, error) {
+	v, found, err := Value(urnStr, key)
+	if err != nil || !found {
+		return "", found, err
+	}
+	if !strings.HasPrefix(v, CompressedMarker) {
+		return v, true, nil
+	}
+	plain, err := decompressValue(v[len(CompressedMarker):])
+	if err != nil {
+		return "", true, fmt.Errorf("Invalid compressed attribute %q: %w", key, err)
+	}
+	return plain, true, nil
+}
+
+func compressValue(value string) string {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression)
+	w.Write([]byte(value))
+	w.Close()
+	return CompressedMarker + base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+func decompressValue(encoded string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	out, err := io.ReadAll(io.LimitReader(r, maxDecompressedLen+1))
+	if err != nil {
+		return "", err
+	}
+	if len(out) > maxDecompressedLen {
+		return "", fmt.Errorf("decompressed value exceeds %d bytes", maxDecompressedLen)
+	}
+	return string(out), nil
+}
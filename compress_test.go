@@ -0,0 +1,57 @@
+package urn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompressedAttributeRoundTrip(t *testing.T) {
+	blob := `{"items":[` + strings.Repeat(`{"sku":"A-100","qty":1},`, 12) + `{}]}`
+	s, err := SetCompressedAttribute("urn:cart:1", "data", blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _, _ := Value(s, "data")
+	if !strings.HasPrefix(raw, CompressedMarker) {
+		t.Errorf("expected compressed storage, got %q", raw)
+	}
+	if len(s) > MaxURNLength {
+		t.Errorf("composed URN too long: %d", len(s))
+	}
+	got, found, err := CompressedValue(s, "data")
+	if err != nil || !found || got != blob {
+		t.Errorf("CompressedValue = %q, %v, %v", got, found, err)
+	}
+}
+
+func TestCompressedAttributeStoresPlainWhenNotShorter(t *testing.T) {
+	s, err := SetCompressedAttribute("urn:cart:1", "token", "a8Kx_3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "urn:cart:1:token:a8Kx_3" {
+		t.Errorf("got %s", s)
+	}
+	if got, _, _ := CompressedValue(s, "token"); got != "a8Kx_3" {
+		t.Errorf("got %q", got)
+	}
+
+	// A plain value that looks compressed is always compressed.
+	s, _ = SetCompressedAttribute("urn:cart:1", "k", CompressedMarker+"x")
+	if got, _, err := CompressedValue(s, "k"); err != nil || got != CompressedMarker+"x" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
+
+func TestCompressedValueTampered(t *testing.T) {
+	for _, v := range []string{CompressedMarker + "!!!", CompressedMarker + "AAAA"} {
+		s, _ := AddAttribute("urn:cart:1", "data", v)
+		_, found, err := CompressedValue(s, "data")
+		if err == nil || !found || !strings.Contains(err.Error(), `Invalid compressed attribute "data"`) {
+			t.Errorf("CompressedValue(%q): got %v, %v", v, found, err)
+		}
+	}
+	if _, found, err := CompressedValue("urn:cart:1", "data"); found || err != nil {
+		t.Errorf("missing key: got %v, %v", found, err)
+	}
+}
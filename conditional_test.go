@@ -0,0 +1,65 @@
+package urn
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompareAndSwapAttribute(t *testing.T) {
+	const in = "urn:orders:1:status:pending:vendor:amazon"
+	tests := []struct {
+		key, old string
+		want     string
+		swapped  bool
+	}{
+		{"status", "pending", "urn:orders:1:status:shipped:vendor:amazon", true},
+		{"status", "created", in, false},
+		{"carrier", "", in, false},
+	}
+	for _, tt := range tests {
+		got, swapped, err := CompareAndSwapAttribute(in, tt.key, tt.old, "shipped")
+		if err != nil || got != tt.want || swapped != tt.swapped {
+			t.Errorf("CompareAndSwapAttribute(%s, %s) = %s, %v, %v", tt.key, tt.old, got, swapped, err)
+		}
+	}
+	if _, _, err := CompareAndSwapAttribute("bad", "k", "a", "b"); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestSetAttributeIf(t *testing.T) {
+	absent := func(_ string, exists bool) bool { return !exists }
+	s, ok, err := SetAttributeIf("urn:orders:1", "owner", "alice", absent)
+	if err != nil || !ok || s != "urn:orders:1:owner:alice" {
+		t.Errorf("got %s, %v, %v", s, ok, err)
+	}
+	s, ok, _ = SetAttributeIf(s, "owner", "bob", absent)
+	if ok || s != "urn:orders:1:owner:alice" {
+		t.Errorf("condition ignored: %s", s)
+	}
+
+	SetReservedKeys("internal")
+	t.Cleanup(func() { SetReservedKeys() })
+	always := func(string, bool) bool { return true }
+	if _, _, err := SetAttributeIf("urn:orders:1", "internal", "x", always); err == nil {
+		t.Error("expected reserved key error")
+	}
+}
+
+func ExampleCompareAndSwapAttribute() {
+	s, swapped, _ := CompareAndSwapAttribute("urn:orders:1:status:pending", "status", "pending", "shipped")
+	fmt.Println(s, swapped)
+	s, swapped, _ = CompareAndSwapAttribute(s, "status", "pending", "cancelled")
+	fmt.Println(s, swapped)
+	// Output:
+	// urn:orders:1:status:shipped true
+	// urn:orders:1:status:shipped false
+}
+
+func ExampleSetAttributeIf() {
+	// Raise the priority, but never lower it.
+	raise := func(current string, exists bool) bool { return !exists || current < "3" }
+	s, _, _ := SetAttributeIf("urn:job:7:priority:1", "priority", "3", raise)
+	fmt.Println(s)
+	// Output: urn:job:7:priority:3
+}
@@ -0,0 +1,91 @@
+package urn
+
+import (
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// config is an immutable snapshot of the package-level configuration: the
+// default options and the per-entity registries. Each call loads one
+// snapshot and reads everything from it; writers copy the current
+// snapshot, change the copy and swap it in, so no call observes a
+// partially applied change.
+type config struct {
+	defaults     []Option
+	opts         options
+	idValidators map[string]IDValidator
+	reservedKeys map[string]bool
+	keyFormat    *regexp.Regexp
+	subResources map[string]map[string]bool
+	errFormatter func(*InvalidURNError) string
+	// entityAliases maps lowercased deprecated entities to their
+	// replacement, with chains already resolved.
+	entityAliases map[string]string
+	idComparators map[string]IDComparator
+}
+
+var (
+	currentConfig atomic.Pointer[config]
+	configMu      sync.Mutex // serializes writers
+)
+
+func init() {
+	currentConfig.Store(&config{})
+}
+
+func loadConfig() *config {
+	return currentConfig.Load()
+}
+
+// updateConfig publishes a modified copy of the current snapshot. The
+// copy shares maps with the old snapshot, so f must replace rather than
+// mutate any map it changes.
+func updateConfig(f func(c *config)) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	c := *currentConfig.Load()
+	f(&c)
+	currentConfig.Store(&c)
+}
+
+// SetDefaults replaces the options used by the package-level functions
+// (Parse, ParseStrict, Compose, Value, ...). Calling it with no options
+// restores the built-in behavior. Registrations such as
+// RegisterIDValidator are kept.
+//
+// Package-level defaults affect every user of the package in the process;
+// libraries should prefer their own Processor from NewProcessor.
+func SetDefaults(opts ...Option) {
+	defaults := append([]Option(nil), opts...)
+	o := newOptions(defaults)
+	updateConfig(func(c *config) {
+		c.defaults = defaults
+		c.opts = o
+	})
+}
+
+// Defaults returns a copy of the options last passed to SetDefaults.
+func Defaults() []Option {
+	return append([]Option(nil), loadConfig().defaults...)
+}
+
+func (c *config) idValidator(entity string) IDValidator {
+	return c.idValidators[asciiToLower(entity)]
+}
+
+func setIDValidator(entity string, v IDValidator) {
+	key := asciiToLower(entity)
+	updateConfig(func(c *config) {
+		m := make(map[string]IDValidator, len(c.idValidators)+1)
+		for k, v := range c.idValidators {
+			m[k] = v
+		}
+		if v == nil {
+			delete(m, key)
+		} else {
+			m[key] = v
+		}
+		c.idValidators = m
+	})
+}
@@ -0,0 +1,103 @@
+package urn
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSetDefaults(t *testing.T) {
+	t.Cleanup(func() { SetDefaults() })
+
+	if _, err := Parse("u:orders:1"); err == nil {
+		t.Fatal("alias accepted without defaults")
+	}
+	SetDefaults(WithSchemeAliases(map[string]string{"u": "urn"}), WithOutputScheme("u"))
+	if _, err := Parse("u:orders:1"); err != nil {
+		t.Errorf("alias rejected under defaults: %v", err)
+	}
+	if s, _ := Compose("orders", "1"); s != "u:orders:1" {
+		t.Errorf("Compose ignored defaults: %s", s)
+	}
+	if len(Defaults()) != 2 {
+		t.Errorf("Defaults() returned %d options", len(Defaults()))
+	}
+
+	// Per-call options stack on the defaults.
+	if _, err := ParseStrict("u:notes:1:k:%09", RejectControlChars()); err == nil {
+		t.Error("expected per-call option to apply")
+	}
+	if _, err := ParseStrict("u:notes:1"); err != nil {
+		t.Errorf("defaults lost with per-call options: %v", err)
+	}
+
+	// Processors are unaffected.
+	if _, err := NewProcessor().Parse("u:orders:1"); err == nil {
+		t.Error("Processor picked up package defaults")
+	}
+
+	SetDefaults()
+	if _, err := Parse("u:orders:1"); err == nil {
+		t.Error("SetDefaults() did not restore built-in behavior")
+	}
+}
+
+func TestDefaultsReturnsCopy(t *testing.T) {
+	t.Cleanup(func() { SetDefaults() })
+	SetDefaults(RejectControlChars())
+	d := Defaults()
+	d[0] = DiscardRaw()
+	if u, _ := Parse("urn:orders:1"); u.Raw() == "" {
+		t.Error("mutating Defaults() changed the configuration")
+	}
+}
+
+// TestSetDefaultsConcurrent flips between two configurations while parsing.
+// A torn configuration, with the alias from one and the segment limit from
+// the other, would let the probe parse.
+func TestSetDefaultsConcurrent(t *testing.T) {
+	t.Cleanup(func() { SetDefaults() })
+	const probe = "u:orders:1:k:v:x:y"
+	strict := []Option{WithSchemeAliases(map[string]string{"u": "urn"}), WithMaxSegments(5)}
+
+	stop := make(chan struct{})
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				SetDefaults(strict...)
+			} else {
+				SetDefaults()
+			}
+			RegisterIDValidator("orders", NumericValidator)
+			RegisterIDValidator("orders", nil)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				if _, err := Parse(probe); err == nil {
+					t.Error("parsed under a torn configuration")
+					return
+				}
+				if _, err := Parse("urn:orders:1"); err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				IsValid("urn:orders:1")
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	<-writerDone
+}
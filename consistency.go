@@ -0,0 +1,100 @@
+package urn
+
+import "sort"
+
+// Mismatch reports an attribute on which a URN and a set of fields
+// disagree. InURN and InFields report which sides have the key; a key
+// missing from one side leaves the corresponding value empty.
+type Mismatch struct {
+	Key        string
+	URNValue   string
+	FieldValue string
+	InURN      bool
+	InFields   bool
+}
+
+// MissingPolicy decides how ConsistentWith treats a key present on only
+// one side.
+type MissingPolicy int
+
+const (
+	// IgnoreMissing skips keys present on only one side.
+	IgnoreMissing MissingPolicy = iota
+	// ReportMissing reports keys present on only one side as mismatches.
+	ReportMissing
+)
+
+type consistency struct {
+	missingInURN    MissingPolicy
+	missingInFields MissingPolicy
+}
+
+// ConsistencyOption configures ConsistentWith.
+type ConsistencyOption func(*consistency)
+
+// MissingInURN sets the policy for field keys the URN has no attribute
+// for. The default is IgnoreMissing.
+func MissingInURN(p MissingPolicy) ConsistencyOption {
+	return func(c *consistency) {
+		c.missingInURN = p
+	}
+}
+
+// MissingInFields sets the policy for URN attributes absent from the
+// fields. The default is IgnoreMissing.
+func MissingInFields(p MissingPolicy) ConsistencyOption {
+	return func(c *consistency) {
+		c.missingInFields = p
+	}
+}
+
+// ConsistentWith reports whether the URN's attributes agree with fields.
+// Keys present on both sides must have equal values; keys present on one
+// side only are handled according to the options. Mismatches list URN
+// attributes in URN order followed by field-only keys in sorted order. For
+// repeated URN keys the first value is compared.
+func (u *URN) ConsistentWith(fields map[string]string, opts ...ConsistencyOption) (bool, []Mismatch) {
+	var c consistency
+	for _, opt := range opts {
+		opt(&c)
+	}
+	var mismatches []Mismatch
+	seen := make(map[string]bool, len(u.attributes))
+	for _, p := range u.attributes {
+		if seen[p.Key] {
+			continue
+		}
+		seen[p.Key] = true
+		fv, ok := fields[p.Key]
+		switch {
+		case !ok && c.missingInFields == ReportMissing:
+			mismatches = append(mismatches, Mismatch{Key: p.Key, URNValue: p.Value, InURN: true})
+		case ok && fv != p.Value:
+			mismatches = append(mismatches, Mismatch{Key: p.Key, URNValue: p.Value, FieldValue: fv, InURN: true, InFields: true})
+		}
+	}
+	if c.missingInURN == ReportMissing {
+		var missing []string
+		for k := range fields {
+			if !seen[k] {
+				missing = append(missing, k)
+			}
+		}
+		sort.Strings(missing)
+		for _, k := range missing {
+			mismatches = append(mismatches, Mismatch{Key: k, FieldValue: fields[k], InFields: true})
+		}
+	}
+	return len(mismatches) == 0, mismatches
+}
+
+// ConsistentWith parses urnStr and reports whether its attributes agree
+// with fields. See (*URN).ConsistentWith.
+func ConsistentWith(urnStr string, fields map[string]string, opts ...ConsistencyOption) (bool, []Mismatch, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return false, nil, err
+	}
+	ok, mismatches := u.ConsistentWith(fields, opts...)
+	return ok, mismatches, nil
+}
@@ -0,0 +1,49 @@
+package urn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConsistentWith(t *testing.T) {
+	const s = "urn:orders:1:tenant:acme:region:eu"
+
+	ok, mismatches, err := ConsistentWith(s, map[string]string{"tenant": "acme", "region": "eu"})
+	if err != nil || !ok || mismatches != nil {
+		t.Errorf("agreement: got %v, %v, %v", ok, mismatches, err)
+	}
+
+	ok, mismatches, _ = ConsistentWith(s, map[string]string{"tenant": "acme", "region": "us"})
+	want := []Mismatch{{Key: "region", URNValue: "eu", FieldValue: "us", InURN: true, InFields: true}}
+	if ok || !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("disagreement: got %v, %+v", ok, mismatches)
+	}
+
+	if _, _, err := ConsistentWith("bad", nil); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestConsistentWithMissingPolicies(t *testing.T) {
+	u, _ := Parse("urn:orders:1:tenant:acme:region:eu")
+	fields := map[string]string{"tenant": "acme", "zone": "b", "app": "x"}
+
+	if ok, _ := u.ConsistentWith(fields); !ok {
+		t.Error("missing keys should be ignored by default")
+	}
+
+	ok, mismatches := u.ConsistentWith(fields, MissingInURN(ReportMissing))
+	want := []Mismatch{
+		{Key: "app", FieldValue: "x", InFields: true},
+		{Key: "zone", FieldValue: "b", InFields: true},
+	}
+	if ok || !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("MissingInURN: got %v, %+v", ok, mismatches)
+	}
+
+	ok, mismatches = u.ConsistentWith(fields, MissingInFields(ReportMissing))
+	want = []Mismatch{{Key: "region", URNValue: "eu", InURN: true}}
+	if ok || !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("MissingInFields: got %v, %+v", ok, mismatches)
+	}
+}
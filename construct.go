@@ -0,0 +1,39 @@
+package urn
+
+// NewURN returns a URN built from the given components, applying the same
+// rules as ComposeAttrs. It is the sanctioned way to construct a URN;
+// struct literals bypass validation and are only checked when serialized.
+func NewURN(entity, id string, attrs ...Attribute) (*URN, error) {
+	if err := validateComponents(entity, id, attrs); err != nil {
+		return nil, err
+	}
+	u := &URN{Entity: entity, ID: id}
+	if len(attrs) > 0 {
+		u.attributes = make([]Attribute, len(attrs))
+		copy(u.attributes, attrs)
+	}
+	return u, nil
+}
+
+// Valid reports whether the URN can be serialized: the entity and ID and
+// every attribute key and value are non-empty, the entity matches the
+// entity format, and the escaped result fits within MaxURNLength. An
+// entity read by Parse, which is lenient about its format, is exempt, so
+// everything Parse accepts is Valid unless escaping pushes it past
+// MaxURNLength; URNs derived from a parsed one keep the exemption. The key
+// policy set with SetReservedKeys and SetKeyFormat is not applied. String,
+// StringE and MarshalText check Valid.
+func (u *URN) Valid() error {
+	var errs errorList
+	if u.Entity == "" || u.ID == "" {
+		errs.add(&InvalidURNError{Kind: KindEmptyComponent, Op: OpCompose})
+	}
+	if u.Entity != "" && !u.parsedEntity {
+		errs.add(checkEntity(nil, &loadConfig().opts, u.Entity, OpCompose))
+	}
+	for _, p := range u.attributes {
+		errs.add(validatePair(nil, p))
+	}
+	errs.add(checkLength(u.Entity, u.ID, u.attributes, MaxURNLength))
+	return errs.err()
+}
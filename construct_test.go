@@ -0,0 +1,117 @@
+package urn
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestNewURN(t *testing.T) {
+	u, err := NewURN("orders", "1", Attribute{"vendor", "amazon"})
+	if err != nil || u.String() != "urn:orders:1:vendor:amazon" {
+		t.Fatalf("NewURN = %v, %v", u, err)
+	}
+	var e *InvalidURNError
+	if _, err := NewURN("bad entity!", "1"); !errors.As(err, &e) || e.Kind != KindInvalidEntity {
+		t.Errorf("invalid entity: %v", err)
+	}
+	if _, err := NewURN("orders", "1", Attribute{"vendor", ""}); !errors.As(err, &e) || e.Kind != KindEmptyAttribute {
+		t.Errorf("empty value: %v", err)
+	}
+}
+
+func TestInvalidLiteralsDoNotSerialize(t *testing.T) {
+	tests := []struct {
+		u    *URN
+		kind ErrorKind
+	}{
+		{&URN{Entity: "bad entity!", ID: "1"}, KindInvalidEntity},
+		{&URN{Entity: "orders", ID: strings.Repeat("é", 50)}, KindTooLong},
+		{&URN{Entity: "orders"}, KindEmptyComponent},
+		{&URN{}, KindEmptyComponent},
+		{&URN{Entity: "orders", ID: "1", attributes: []Attribute{{"", "x"}}}, KindEmptyAttribute},
+	}
+	for _, tt := range tests {
+		if s := tt.u.String(); s != "" {
+			t.Errorf("String() = %q, want empty", s)
+		}
+		_, err := tt.u.StringE()
+		var e *InvalidURNError
+		if !errors.As(err, &e) || e.Kind != tt.kind {
+			t.Errorf("StringE(%#v) error = %v, want kind %v", tt.u, err, tt.kind)
+		}
+		if _, err := tt.u.MarshalText(); err == nil {
+			t.Errorf("MarshalText(%#v) succeeded", tt.u)
+		}
+	}
+
+	u := &URN{Entity: "orders", ID: "1"}
+	if s, err := u.StringE(); err != nil || s != "urn:orders:1" {
+		t.Errorf("valid literal: %q, %v", s, err)
+	}
+}
+
+func TestLenientlyParsedURNsSerialize(t *testing.T) {
+	for _, in := range []string{"urn:a:1", "urn:my_entity:1", "urn:user.name:1", "urn:bad%20entity%21:1"} {
+		u, err := Parse(in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", in, err)
+		}
+		if s := u.String(); s != in {
+			t.Errorf("Parse(%q).String() = %q", in, s)
+		}
+		if s := u.WithAttribute("k", "v").String(); s != in+":k:v" {
+			t.Errorf("WithAttribute on %q = %q", in, s)
+		}
+		if b, err := u.MarshalText(); err != nil || string(b) != in {
+			t.Errorf("MarshalText on %q = %q, %v", in, b, err)
+		}
+	}
+}
+
+func TestParsedURNsAreValid(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "Z", "0", "-", "_", ".", "!", "%41", "%3a", "%c3%a9", "é", "~", "@", "%", ":"}
+	var parsed int
+	for i := 0; i < 5000; i++ {
+		var b strings.Builder
+		b.WriteString("urn")
+		for n := 1 + rng.Intn(6); n > 0; n-- {
+			b.WriteByte(':')
+			for m := rng.Intn(7); m > 0; m-- {
+				b.WriteString(alphabet[rng.Intn(len(alphabet))])
+			}
+		}
+		in := b.String()
+		u, err := Parse(in)
+		if err != nil {
+			continue
+		}
+		parsed++
+		if err := u.Valid(); err != nil {
+			t.Fatalf("Parse(%q) is not Valid: %v", in, err)
+		}
+		s := u.String()
+		if s == "" {
+			t.Fatalf("Parse(%q).String() is empty", in)
+		}
+		if _, err := Parse(s); err != nil {
+			t.Fatalf("String() of %q = %q does not parse: %v", in, s, err)
+		}
+	}
+	if parsed < 100 {
+		t.Errorf("corpus too small: %d inputs parsed", parsed)
+	}
+}
+
+func TestValidKeepsSystemKeys(t *testing.T) {
+	s, err := WithChecksum("urn:orders:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := Parse(s)
+	if err := u.Valid(); err != nil {
+		t.Errorf("checksummed URN invalid: %v", err)
+	}
+}
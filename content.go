@@ -0,0 +1,109 @@
+package urn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DigestEncoding selects how FromContent writes the SHA-256 digest into
+// the ID.
+type DigestEncoding int
+
+const (
+	// DigestHex writes 64 lowercase hex digits.
+	DigestHex DigestEncoding = iota
+	// DigestBase32 writes 52 lowercase base32 (RFC 4648) characters
+	// without padding.
+	DigestBase32
+)
+
+var digestBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type contentConfig struct {
+	encoding DigestEncoding
+}
+
+// ContentOption configures FromContent, FromBytes and FromString.
+type ContentOption func(*contentConfig)
+
+// WithDigestEncoding sets the digest encoding. The default is DigestHex.
+func WithDigestEncoding(e DigestEncoding) ContentOption {
+	return func(c *contentConfig) {
+		c.encoding = e
+	}
+}
+
+// FromContent returns a content-addressed URN whose ID is the SHA-256
+// digest of everything read from r. The reader is streamed, so identical
+// content yields the same URN however it is chunked.
+func FromContent(entity string, r io.Reader, opts ...ContentOption) (string, error) {
+	var c contentConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	sum, err := contentDigest(r)
+	if err != nil {
+		return "", err
+	}
+	var id string
+	switch c.encoding {
+	case DigestHex:
+		id = hex.EncodeToString(sum)
+	case DigestBase32:
+		id = strings.ToLower(digestBase32.EncodeToString(sum))
+	default:
+		return "", fmt.Errorf("Cannot compose URN: unknown digest encoding %d", c.encoding)
+	}
+	return Compose(entity, id)
+}
+
+// FromBytes is FromContent for an in-memory payload.
+func FromBytes(entity string, data []byte, opts ...ContentOption) (string, error) {
+	return FromContent(entity, bytes.NewReader(data), opts...)
+}
+
+// FromString is FromContent for a string payload.
+func FromString(entity, data string, opts ...ContentOption) (string, error) {
+	return FromContent(entity, strings.NewReader(data), opts...)
+}
+
+// VerifyContent reports whether the ID of urnStr is the SHA-256 digest of
+// the content read from r, in either digest encoding. Attributes are
+// ignored. An ID that is not a digest is an error.
+func VerifyContent(urnStr string, r io.Reader) (bool, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return false, err
+	}
+	var want []byte
+	switch len(u.ID) {
+	case hex.EncodedLen(sha256.Size):
+		want, err = hex.DecodeString(u.ID)
+	case digestBase32.EncodedLen(sha256.Size):
+		want, err = digestBase32.DecodeString(strings.ToUpper(u.ID))
+	default:
+		err = fmt.Errorf("unexpected length %d", len(u.ID))
+	}
+	if err != nil {
+		return false, fmt.Errorf("Invalid content URN: ID is not a SHA-256 digest: %w", err)
+	}
+	sum, err := contentDigest(r)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(sum, want), nil
+}
+
+// contentDigest streams r through SHA-256.
+func contentDigest(r io.Reader) ([]byte, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,79 @@
+package urn
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// chunkReader returns data in reads of at most n bytes.
+type chunkReader struct {
+	data []byte
+	n    int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	k := copy(p[:min(len(p), r.n)], r.data)
+	r.data = r.data[k:]
+	return k, nil
+}
+
+func TestFromContentChunking(t *testing.T) {
+	payload := bytes.Repeat([]byte("content-addressed "), 1000)
+	want, err := FromBytes("blobs", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(want, "urn:blobs:") || len(want) != len("urn:blobs:")+64 {
+		t.Fatalf("unexpected URN %s", want)
+	}
+	readers := []io.Reader{
+		iotest.OneByteReader(bytes.NewReader(payload)),
+		iotest.HalfReader(bytes.NewReader(payload)),
+		&chunkReader{data: payload, n: 7},
+		io.MultiReader(bytes.NewReader(payload[:5]), bytes.NewReader(payload[5:])),
+	}
+	for i, r := range readers {
+		if got, err := FromContent("blobs", r); err != nil || got != want {
+			t.Errorf("reader %d: %s, %v", i, got, err)
+		}
+	}
+	if got, _ := FromString("blobs", string(payload)); got != want {
+		t.Errorf("FromString = %s", got)
+	}
+}
+
+func TestFromContentEncodings(t *testing.T) {
+	const hexID = "urn:blobs:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if got, _ := FromString("blobs", "hello"); got != hexID {
+		t.Errorf("hex = %s", got)
+	}
+	b32, err := FromString("blobs", "hello", WithDigestEncoding(DigestBase32))
+	if err != nil || len(b32) != len("urn:blobs:")+52 || b32 != strings.ToLower(b32) {
+		t.Errorf("base32 = %s, %v", b32, err)
+	}
+	for _, s := range []string{hexID, b32} {
+		if ok, err := VerifyContent(s, strings.NewReader("hello")); !ok || err != nil {
+			t.Errorf("VerifyContent(%s) = %v, %v", s, ok, err)
+		}
+		if ok, _ := VerifyContent(s, strings.NewReader("hello!")); ok {
+			t.Errorf("VerifyContent(%s) accepted different content", s)
+		}
+	}
+	if _, err := VerifyContent("urn:blobs:123", strings.NewReader("")); err == nil {
+		t.Error("non-digest ID accepted")
+	}
+}
+
+func TestFromContentReadError(t *testing.T) {
+	boom := errors.New("boom")
+	if _, err := FromContent("blobs", iotest.ErrReader(boom)); !errors.Is(err, boom) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package urn
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// DuplicateDetector reports URNs whose identity (see Identity) was already
+// seen, ignoring attributes and entity case. IDs of entities with an
+// IDComparator are compared with it against every ID recorded for the
+// entity; they are stored in full even by a hashed detector. It is safe
+// for concurrent use.
+type DuplicateDetector struct {
+	mu     sync.Mutex
+	hashed bool
+	keys   map[string]struct{}
+	hashes map[uint64]struct{}
+	// ids holds the IDs seen per lowercased entity with a comparator.
+	ids map[string][]string
+}
+
+// NewDuplicateDetector returns a detector that stores each identity string.
+func NewDuplicateDetector() *DuplicateDetector {
+	return &DuplicateDetector{keys: make(map[string]struct{})}
+}
+
+// NewHashedDuplicateDetector returns a detector that stores only a 64-bit
+// FNV-1a hash of each identity, using a fixed 8 bytes per entry. Distinct
+// identities can collide, so Seen may report a false duplicate: with n
+// identities the chance of any collision is about n²/2⁶⁵, roughly 3·10⁻⁸
+// for a million rows. It never misses a real duplicate.
+func NewHashedDuplicateDetector() *DuplicateDetector {
+	return &DuplicateDetector{hashed: true, hashes: make(map[uint64]struct{})}
+}
+
+// Seen records the identity of urnStr and reports whether it had been seen
+// before. Unparsable input is an error and is not recorded.
+func (d *DuplicateDetector) Seen(urnStr string) (dup bool, err error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return false, err
+	}
+	entity := asciiToLower(u.Entity)
+	if cmp := loadConfig().idComparator(entity); cmp != nil {
+		return d.seenWith(cmp, entity, u.ID), nil
+	}
+	id, err := compose(entity, u.ID, nil)
+	if err != nil {
+		return false, err
+	}
+	var sum uint64
+	if d.hashed {
+		h := fnv.New64a()
+		h.Write([]byte(id))
+		sum = h.Sum64()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.hashed {
+		_, dup = d.hashes[sum]
+		d.hashes[sum] = struct{}{}
+	} else {
+		_, dup = d.keys[id]
+		d.keys[id] = struct{}{}
+	}
+	return dup, nil
+}
+
+// seenWith records id for entity unless cmp matches a recorded ID.
+func (d *DuplicateDetector) seenWith(cmp IDComparator, entity, id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, seen := range d.ids[entity] {
+		if cmp(seen, id) {
+			return true
+		}
+	}
+	if d.ids == nil {
+		d.ids = make(map[string][]string)
+	}
+	d.ids[entity] = append(d.ids[entity], id)
+	return false
+}
+
+// Count returns the number of distinct identities recorded.
+func (d *DuplicateDetector) Count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := len(d.keys) + len(d.hashes)
+	for _, ids := range d.ids {
+		n += len(ids)
+	}
+	return n
+}
+
+// Reset forgets every recorded identity.
+func (d *DuplicateDetector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.ids = nil
+	if d.hashed {
+		d.hashes = make(map[uint64]struct{})
+	} else {
+		d.keys = make(map[string]struct{})
+	}
+}
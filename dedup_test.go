@@ -0,0 +1,63 @@
+package urn
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDuplicateDetector(t *testing.T) {
+	for name, d := range map[string]*DuplicateDetector{
+		"exact":  NewDuplicateDetector(),
+		"hashed": NewHashedDuplicateDetector(),
+	} {
+		inputs := []struct {
+			urn string
+			dup bool
+		}{
+			{"urn:orders:1", false},
+			{"urn:ORDERS:1", true},               // entity case variant
+			{"urn:orders:1:vendor:amazon", true}, // attribute variant
+			{"urn:orders:2:vendor:amazon", false},
+			{"urn:invoices:1", false},
+		}
+		for _, in := range inputs {
+			dup, err := d.Seen(in.urn)
+			if err != nil || dup != in.dup {
+				t.Errorf("%s: Seen(%s) = %v, %v; want %v", name, in.urn, dup, err, in.dup)
+			}
+		}
+		if _, err := d.Seen("bad"); err == nil {
+			t.Errorf("%s: expected error for invalid input", name)
+		}
+		if d.Count() != 3 {
+			t.Errorf("%s: Count() = %d, want 3", name, d.Count())
+		}
+		d.Reset()
+		if dup, _ := d.Seen("urn:orders:1"); dup || d.Count() != 1 {
+			t.Errorf("%s: Reset did not clear state", name)
+		}
+	}
+}
+
+func TestDuplicateDetectorConcurrent(t *testing.T) {
+	d := NewHashedDuplicateDetector()
+	var dups atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				if dup, _ := d.Seen(fmt.Sprintf("urn:orders:%d", i)); dup {
+					dups.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if d.Count() != 500 || dups.Load() != 7*500 {
+		t.Errorf("Count() = %d, duplicates = %d", d.Count(), dups.Load())
+	}
+}
@@ -0,0 +1,72 @@
+package urn
+
+import "slices"
+
+// shareAttributes marks the attributes of u, a fresh copy of another URN,
+// as shared with it. The array is clipped so that appending to either
+// slice reallocates instead of writing into spare capacity.
+func (u *URN) shareAttributes() {
+	if u.attributes != nil {
+		u.attributes = slices.Clip(u.attributes)
+		u.sharedAttrs = true
+	}
+}
+
+// ownAttributes copies the attributes of u if they are shared, leaving
+// room for extra more, so they can be changed in place.
+func (u *URN) ownAttributes(extra int) {
+	if !u.sharedAttrs {
+		return
+	}
+	own := make([]Attribute, len(u.attributes), len(u.attributes)+extra)
+	copy(own, u.attributes)
+	u.attributes, u.sharedAttrs = own, false
+}
+
+// derive returns a copy of u for a With method, sharing its attributes
+// and without the input string it was parsed from.
+func (u *URN) derive() *URN {
+	c := *u
+	c.shareAttributes()
+	c.raw = ""
+	c.transients = u.cloneTransients(nil)
+	return &c
+}
+
+// WithAttribute returns a copy of the URN with key set to value: the first
+// matching attribute gets the new value, or the attribute is appended. The
+// copy shares unchanged state with u, so deriving many URNs from one
+// template is cheap. Like NewURN, the result is only checked when it is
+// serialized; see Valid.
+func (u *URN) WithAttribute(key, value string) *URN {
+	c := u.derive()
+	c.set(&loadConfig().opts, key, value)
+	return c
+}
+
+// WithoutAttribute returns a copy of the URN without the attributes
+// matching key.
+func (u *URN) WithoutAttribute(key string) *URN {
+	c := u.derive()
+	o := &loadConfig().opts
+	i := slices.IndexFunc(c.attributes, func(a Attribute) bool { return o.keyEqual(a.Key, key) })
+	if i < 0 {
+		return c
+	}
+	kept := make([]Attribute, i, len(c.attributes)-1)
+	copy(kept, c.attributes[:i])
+	for _, a := range c.attributes[i+1:] {
+		if !o.keyEqual(a.Key, key) {
+			kept = append(kept, a)
+		}
+	}
+	c.attributes, c.sharedAttrs = kept, false
+	return c
+}
+
+// WithID returns a copy of the URN with the given ID.
+func (u *URN) WithID(id string) *URN {
+	c := u.derive()
+	c.ID = id
+	return c
+}
@@ -0,0 +1,148 @@
+package urn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func templateURN(tb testing.TB, n int) *URN {
+	tb.Helper()
+	var b strings.Builder
+	b.WriteString("urn:item:tmpl")
+	for i := range n {
+		fmt.Fprintf(&b, ":k%d:v%d", i, i)
+	}
+	u, err := Parse(b.String())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return u
+}
+
+func TestWithAttribute(t *testing.T) {
+	parent, _ := Parse("urn:item:1:a:1:b:2")
+	replaced := parent.WithAttribute("a", "9")
+	appended := parent.WithAttribute("c", "3")
+	if got := replaced.String(); got != "urn:item:1:a:9:b:2" {
+		t.Errorf("replace: %q", got)
+	}
+	if got := appended.String(); got != "urn:item:1:a:1:b:2:c:3" {
+		t.Errorf("append: %q", got)
+	}
+	if got := parent.String(); got != "urn:item:1:a:1:b:2" {
+		t.Errorf("parent changed: %q", got)
+	}
+	if replaced.Raw() != "" {
+		t.Errorf("derived URN kept the parsed input: %q", replaced.Raw())
+	}
+
+	// Appending to siblings never writes into a shared array.
+	x := parent.Clone().WithAttribute("x", "1")
+	y := parent.Clone().WithAttribute("y", "1")
+	if x.String() != "urn:item:1:a:1:b:2:x:1" || y.String() != "urn:item:1:a:1:b:2:y:1" {
+		t.Errorf("siblings: %q, %q", x, y)
+	}
+
+	if s := parent.WithAttribute("", "v").String(); s != "" {
+		t.Errorf("invalid attribute serialized as %q", s)
+	}
+}
+
+func TestWithoutAttributeAndWithID(t *testing.T) {
+	parent, _ := Parse("urn:item:1:a:1:b:2:a:3")
+	if got := parent.WithoutAttribute("a").String(); got != "urn:item:1:b:2" {
+		t.Errorf("WithoutAttribute = %q", got)
+	}
+	if got := parent.WithoutAttribute("zz").String(); got != parent.String() {
+		t.Errorf("WithoutAttribute of a missing key = %q", got)
+	}
+	if got := parent.WithID("2").String(); got != "urn:item:2:a:1:b:2:a:3" {
+		t.Errorf("WithID = %q", got)
+	}
+	if parent.ID != "1" || len(parent.AttributePairs()) != 3 {
+		t.Errorf("parent changed: %v", parent)
+	}
+}
+
+func TestCloneCopyOnWrite(t *testing.T) {
+	parent := templateURN(t, 15)
+	if n := testing.AllocsPerRun(100, func() { parent.Clone() }); n != 1 {
+		t.Errorf("Clone allocates %v times, want 1", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { parent.WithAttribute("k3", "x") }); n != 2 {
+		t.Errorf("WithAttribute allocates %v times, want 2", n)
+	}
+
+	c := parent.Clone()
+	c.set(&options{}, "k0", "changed")
+	if v, _ := parent.get("k0"); v != "v0" {
+		t.Errorf("changing a clone changed the parent: k0 = %q", v)
+	}
+}
+
+func TestSharedAttributesConcurrentReads(t *testing.T) {
+	parent := templateURN(t, 15)
+	children := make([]*URN, 64)
+	for i := range children {
+		children[i] = parent.Clone()
+	}
+	var wg sync.WaitGroup
+	for i, c := range children {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if c.String() != parent.String() || c.Canonical() == "" {
+					t.Error("sibling differs from parent")
+					return
+				}
+				d := c.WithAttribute("k1", strconv.Itoa(i))
+				if v, _ := d.get("k1"); v != strconv.Itoa(i) {
+					t.Errorf("derived k1 = %q", v)
+					return
+				}
+				_ = parent.Clone().WithAttribute("new", "x").String()
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := parent.get("k1"); v != "v1" {
+		t.Errorf("parent k1 = %q", v)
+	}
+}
+
+var deriveSink *URN
+
+func BenchmarkDeriveChildren(b *testing.B) {
+	parent := templateURN(b, 15)
+	b.Run("WithAttribute", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for i := range 10_000 {
+				deriveSink = parent.WithAttribute("k7", strconv.Itoa(i&7))
+			}
+		}
+	})
+	b.Run("Clone", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for range 10_000 {
+				deriveSink = parent.Clone()
+			}
+		}
+	})
+	b.Run("DeepCopy", func(b *testing.B) {
+		// The former Clone, which copied the attributes eagerly.
+		b.ReportAllocs()
+		for b.Loop() {
+			for range 10_000 {
+				c := *parent
+				c.attributes = append([]Attribute(nil), parent.attributes...)
+				deriveSink = &c
+			}
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package urn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is a separator style for the segments after the scheme.
+type Dialect int
+
+const (
+	// DialectColon is the standard form, "urn:orders:1234:vendor:amazon".
+	DialectColon Dialect = iota
+	// DialectSlash is the path-flavored form, "urn:orders/1234/vendor/amazon".
+	DialectSlash
+)
+
+// String returns the dialect name.
+func (d Dialect) String() string {
+	switch d {
+	case DialectColon:
+		return "colon"
+	case DialectSlash:
+		return "slash"
+	}
+	return fmt.Sprintf("Dialect(%d)", int(d))
+}
+
+// separator returns the segment separator of the dialect.
+func (d Dialect) separator() string {
+	if d == DialectSlash {
+		return "/"
+	}
+	return ":"
+}
+
+// ParseAny parses a URN in either dialect. The dialect is taken from the
+// separator that ends the entity, and the other separator must then not
+// appear unescaped anywhere after the scheme. Escaping, validation and
+// error offsets are the same as for Parse; Dialect reports which form was
+// found.
+func ParseAny(urnStr string) (*URN, error) {
+	o := &loadConfig().opts
+	start := o.schemeEnd(urnStr)
+	if start < 0 {
+		return parse(urnStr, o)
+	}
+	rest := urnStr[start:]
+	d := DialectColon
+	if i := strings.IndexAny(rest, ":/"); i >= 0 && rest[i] == '/' {
+		d = DialectSlash
+	}
+	other := DialectSlash
+	if d == DialectSlash {
+		other = DialectColon
+	}
+	if i := strings.Index(rest, other.separator()); i >= 0 {
+		return nil, &InvalidURNError{
+			Kind:   KindMixedSeparators,
+			Offset: start + i,
+			Value:  other.separator(),
+		}
+	}
+	if d == DialectColon {
+		return parse(urnStr, o)
+	}
+	// Segments never contain an unescaped separator, so swapping them
+	// keeps every offset and segment intact.
+	u, err := parse(urnStr[:start]+strings.ReplaceAll(rest, "/", ":"), o)
+	if err != nil {
+		return nil, err
+	}
+	u.dialect = DialectSlash
+	if u.raw != "" {
+		u.raw = urnStr
+	}
+	return u, nil
+}
+
+// Dialect returns the dialect the URN was parsed from. URNs parsed by
+// anything other than ParseAny, and built URNs, report DialectColon.
+func (u *URN) Dialect() Dialect {
+	return u.dialect
+}
+
+// StringAs returns the URN in dialect d. String always uses DialectColon.
+func (u *URN) StringAs(d Dialect) string {
+	s := u.String()
+	if d != DialectSlash || s == "" {
+		return s
+	}
+	return "urn:" + strings.ReplaceAll(s[len("urn:"):], ":", "/")
+}
@@ -0,0 +1,60 @@
+package urn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAnyDialects(t *testing.T) {
+	tests := []struct {
+		in      string
+		dialect Dialect
+		colon   string
+	}{
+		{"urn:orders/1234/vendor/amazon", DialectSlash, "urn:orders:1234:vendor:amazon"},
+		{"urn:orders:1234:vendor:amazon", DialectColon, "urn:orders:1234:vendor:amazon"},
+		{"urn:orders/a%2Fb/path/x%3Ay", DialectSlash, "urn:orders:a%2Fb:path:x%3Ay"},
+		{"urn:orders/1234", DialectSlash, "urn:orders:1234"},
+	}
+	for _, tt := range tests {
+		u, err := ParseAny(tt.in)
+		if err != nil {
+			t.Errorf("ParseAny(%q): %v", tt.in, err)
+			continue
+		}
+		if u.Dialect() != tt.dialect || u.String() != tt.colon {
+			t.Errorf("ParseAny(%q) = %s, %v", tt.in, u, u.Dialect())
+		}
+		if got := u.StringAs(tt.dialect); got != tt.in {
+			t.Errorf("StringAs(%v) = %s, want %s", tt.dialect, got, tt.in)
+		}
+		if u.Raw() != tt.in {
+			t.Errorf("Raw() = %s", u.Raw())
+		}
+	}
+
+	u, _ := ParseAny("urn:orders/a%2fb/path/x")
+	if u.ID != "a/b" || u.RawID() != "a%2fb" || u.RawAttributePairs()[0].Value != "x" {
+		t.Errorf("slash raw segments: %q %q %v", u.ID, u.RawID(), u.RawAttributePairs())
+	}
+}
+
+func TestParseAnyMixedSeparators(t *testing.T) {
+	tests := []struct {
+		in     string
+		kind   ErrorKind
+		offset int
+	}{
+		{"urn:orders/1234:vendor:amazon", KindMixedSeparators, 15},
+		{"urn:orders:1234/vendor/amazon", KindMixedSeparators, 15},
+		{"urn:orders/1234/vendor", KindUnpairedKey, 16},
+		{"urn:orders//x", KindEmptyComponent, 11},
+	}
+	for _, tt := range tests {
+		_, err := ParseAny(tt.in)
+		var e *InvalidURNError
+		if !errors.As(err, &e) || e.Kind != tt.kind || e.Offset != tt.offset {
+			t.Errorf("ParseAny(%q) = %v", tt.in, err)
+		}
+	}
+}
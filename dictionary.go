@@ -0,0 +1,128 @@
+package urn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Dictionary assigns small integer codes to URNs, for use as surrogate
+// keys. Codes are given out in increasing order starting at 1, one per
+// canonical form, so equivalent URNs share a code. A Dictionary is safe
+// for concurrent use.
+type Dictionary struct {
+	mu    sync.RWMutex
+	codes map[string]uint64
+	// urns holds the canonical form for code i+1.
+	urns []string
+}
+
+// NewDictionary creates an empty Dictionary.
+func NewDictionary() *Dictionary {
+	return &Dictionary{codes: make(map[string]uint64)}
+}
+
+// Encode returns the code of urnStr's canonical form, assigning the next
+// code if it has none yet.
+func (d *Dictionary) Encode(urnStr string) (uint64, error) {
+	key, err := Canonical(urnStr)
+	if err != nil {
+		return 0, err
+	}
+	d.mu.RLock()
+	code, ok := d.codes[key]
+	d.mu.RUnlock()
+	if ok {
+		return code, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if code, ok := d.codes[key]; ok {
+		return code, nil
+	}
+	d.urns = append(d.urns, key)
+	code = uint64(len(d.urns))
+	d.codes[key] = code
+	return code, nil
+}
+
+// Decode returns the canonical URN assigned code.
+func (d *Dictionary) Decode(code uint64) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if code == 0 || code > uint64(len(d.urns)) {
+		return "", false
+	}
+	return d.urns[code-1], true
+}
+
+// Len returns the number of codes assigned.
+func (d *Dictionary) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.urns)
+}
+
+// Snapshot serializes the dictionary for Restore. The format is the
+// uvarint count of entries followed by each canonical URN in code order,
+// prefixed with its uvarint length.
+func (d *Dictionary) Snapshot() []byte {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	n := binary.MaxVarintLen64
+	for _, s := range d.urns {
+		n += binary.MaxVarintLen64 + len(s)
+	}
+	b := make([]byte, 0, n)
+	b = binary.AppendUvarint(b, uint64(len(d.urns)))
+	for _, s := range d.urns {
+		b = binary.AppendUvarint(b, uint64(len(s)))
+		b = append(b, s...)
+	}
+	return b
+}
+
+var errTruncatedSnapshot = errors.New("truncated")
+
+// Restore replaces the contents of the dictionary with a Snapshot, so
+// every URN gets back its code and new URNs continue the sequence. On
+// error the dictionary is left unchanged.
+func (d *Dictionary) Restore(data []byte) error {
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return fmt.Errorf("Invalid dictionary snapshot: %w", errTruncatedSnapshot)
+	}
+	data = data[n:]
+	// Every entry takes at least two bytes, which bounds the allocation.
+	if count > uint64(len(data)/2) {
+		return fmt.Errorf("Invalid dictionary snapshot: %w", errTruncatedSnapshot)
+	}
+	urns := make([]string, 0, count)
+	codes := make(map[string]uint64, count)
+	for i := range count {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return fmt.Errorf("Invalid dictionary snapshot: entry %d: %w", i+1, errTruncatedSnapshot)
+		}
+		s := string(data[n : n+int(size)])
+		data = data[n+int(size):]
+		if c, err := Canonical(s); err != nil || c != s {
+			return fmt.Errorf("Invalid dictionary snapshot: entry %d %q is not a canonical URN", i+1, s)
+		}
+		if _, dup := codes[s]; dup {
+			return fmt.Errorf("Invalid dictionary snapshot: entry %d %q is a duplicate", i+1, s)
+		}
+		urns = append(urns, s)
+		codes[s] = i + 1
+	}
+	if len(data) > 0 {
+		return fmt.Errorf("Invalid dictionary snapshot: %d trailing bytes", len(data))
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.urns, d.codes = urns, codes
+	return nil
+}
@@ -0,0 +1,110 @@
+package urn
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestDictionary(t *testing.T) {
+	d := NewDictionary()
+	a, err := d.Encode("urn:user:1:b:2:a:1")
+	if err != nil || a != 1 {
+		t.Fatalf("Encode = %d, %v", a, err)
+	}
+	// Equivalent forms share the code.
+	if c, _ := d.Encode("urn:USER:1:a:1:b:2"); c != a {
+		t.Errorf("equivalent URN got code %d, want %d", c, a)
+	}
+	if c, _ := d.Encode("urn:user:2"); c != 2 {
+		t.Errorf("second URN got code %d", c)
+	}
+	if s, ok := d.Decode(a); !ok || s != "urn:user:1:a:1:b:2" {
+		t.Errorf("Decode(%d) = %q, %v", a, s, ok)
+	}
+	for _, code := range []uint64{0, 3} {
+		if _, ok := d.Decode(code); ok {
+			t.Errorf("Decode(%d) found a URN", code)
+		}
+	}
+	if _, err := d.Encode("bad"); err == nil {
+		t.Error("Encode accepted an invalid URN")
+	}
+	if d.Len() != 2 {
+		t.Errorf("Len = %d", d.Len())
+	}
+}
+
+func TestDictionaryRestore(t *testing.T) {
+	d := NewDictionary()
+	for _, s := range []string{"urn:user:1", "urn:order:a%3Ab", "urn:user:2:k:v"} {
+		d.Encode(s)
+	}
+	snap := d.Snapshot()
+
+	r := NewDictionary()
+	r.Encode("urn:other:x") // replaced by Restore
+	if err := r.Restore(snap); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"urn:user:1", "urn:order:a%3Ab", "urn:user:2:k:v"} {
+		want, _ := d.Encode(s)
+		if got, _ := r.Encode(s); got != want {
+			t.Errorf("%s: restored code %d, want %d", s, got, want)
+		}
+	}
+	if c, _ := r.Encode("urn:other:x"); c != 4 {
+		t.Errorf("new URN after restore got code %d, want 4", c)
+	}
+}
+
+func TestDictionaryRestoreErrors(t *testing.T) {
+	d := NewDictionary()
+	d.Encode("urn:user:1")
+	d.Encode("urn:user:2")
+	snap := d.Snapshot()
+
+	bad := map[string][]byte{
+		"empty":     nil,
+		"truncated": snap[:len(snap)-1],
+		"trailing":  append(append([]byte{}, snap...), 0),
+		"huge":      {0xff, 0xff, 0xff, 0xff, 0x0f},
+		"invalid":   {1, 3, 'b', 'a', 'd'},
+		"noncanon":  {1, 10, 'u', 'r', 'n', ':', 'U', 'S', 'E', 'R', ':', '1'},
+		"duplicate": {2, 10, 'u', 'r', 'n', ':', 'u', 's', 'e', 'r', ':', '1', 10, 'u', 'r', 'n', ':', 'u', 's', 'e', 'r', ':', '1'},
+	}
+	for name, data := range bad {
+		if err := d.Restore(data); err == nil {
+			t.Errorf("%s: Restore accepted %q", name, data)
+		}
+	}
+	if d.Len() != 2 {
+		t.Errorf("failed Restore changed the dictionary: Len = %d", d.Len())
+	}
+}
+
+func TestDictionaryConcurrentEncode(t *testing.T) {
+	d := NewDictionary()
+	const workers = 32
+	codes := make([]uint64, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				d.Encode(fmt.Sprintf("urn:user:%d", j))
+			}
+			codes[i], _ = d.Encode("urn:user:shared")
+		}()
+	}
+	wg.Wait()
+	for _, c := range codes {
+		if c != codes[0] {
+			t.Fatalf("codes differ: %v", codes)
+		}
+	}
+	if d.Len() != 101 {
+		t.Errorf("Len = %d, want 101", d.Len())
+	}
+}
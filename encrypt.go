@@ -0,0 +1,75 @@
+package urn
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// EncryptedMarker prefixes attribute values stored by
+// SetEncryptedAttribute.
+const EncryptedMarker = "~e~"
+
+// SetEncryptedAttribute sets key to value like AddAttribute, storing the
+// value sealed with aead as EncryptedMarker followed by
+// base64url(nonce || ciphertext), with a fresh random nonce per call. The
+// lowercased entity, the ID and key are the associated data, so the stored
+// value only decrypts under the same key of a URN for the same resource.
+// The length limit
+// applies to the encoded value. Read it back with DecryptAttribute.
+func SetEncryptedAttribute(urnStr, key, value string, aead cipher.AEAD) (string, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("Cannot encrypt attribute %q: %w", key, err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), encryptionAD(u, key))
+	return AddAttribute(urnStr, key, EncryptedMarker+base64.RawURLEncoding.EncodeToString(sealed))
+}
+
+// DecryptAttribute returns the value of key stored by
+// SetEncryptedAttribute, decrypted with aead. A value without
+// EncryptedMarker, a malformed one and one that fails authentication,
+// because of a wrong AEAD key or because it was copied from another
+// resource or attribute, are errors.
+func DecryptAttribute(urnStr, key string, aead cipher.AEAD) (string, bool, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return "", false, err
+	}
+	v, found := u.get(key)
+	if !found {
+		return "", false, nil
+	}
+	encoded, ok := strings.CutPrefix(v, EncryptedMarker)
+	if !ok {
+		return "", true, fmt.Errorf("Invalid encrypted attribute %q: value is not encrypted", key)
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", true, fmt.Errorf("Invalid encrypted attribute %q: %w", key, err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", true, fmt.Errorf("Invalid encrypted attribute %q: value too short", key)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, encryptionAD(u, key))
+	if err != nil {
+		return "", true, fmt.Errorf("Invalid encrypted attribute %q: %w", key, err)
+	}
+	return string(plain), true, nil
+}
+
+// encryptionAD returns the associated data binding a ciphertext to the
+// attribute key of the resource u names, length-prefixed like SigningBytes
+// so the boundaries between entity, ID and key are unambiguous.
+func encryptionAD(u *URN, key string) []byte {
+	b := appendSigningString(nil, asciiToLower(u.Entity))
+	b = appendSigningString(b, u.ID)
+	return appendSigningString(b, key)
+}
@@ -0,0 +1,119 @@
+package urn
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testAEAD(t *testing.T, key string) cipher.AEAD {
+	t.Helper()
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aead
+}
+
+func TestEncryptedAttributeRoundTrip(t *testing.T) {
+	aead := testAEAD(t, "0123456789abcdef")
+	s, err := SetEncryptedAttribute("urn:user:42:tier:gold", "email", "ada@example.com", aead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(s, "ada") || strings.Contains(s, "example") {
+		t.Errorf("URN leaks plaintext: %s", s)
+	}
+	raw, _, _ := Value(s, "email")
+	if !strings.HasPrefix(raw, EncryptedMarker) {
+		t.Errorf("stored value %q lacks marker", raw)
+	}
+	if v, _, _ := Value(s, "tier"); v != "gold" {
+		t.Errorf("tier = %q, want other attributes kept", v)
+	}
+
+	got, found, err := DecryptAttribute(s, "email", aead)
+	if err != nil || !found || got != "ada@example.com" {
+		t.Errorf("DecryptAttribute = %q, %v, %v", got, found, err)
+	}
+	// The entity is compared case-insensitively.
+	if got, _, err := DecryptAttribute(strings.Replace(s, "user", "USER", 1), "email", aead); err != nil || got != "ada@example.com" {
+		t.Errorf("DecryptAttribute(upper entity) = %q, %v", got, err)
+	}
+
+	again, _ := SetEncryptedAttribute("urn:user:42", "email", "ada@example.com", aead)
+	if a, _, _ := Value(again, "email"); a == raw {
+		t.Error("two encryptions produced the same value; nonce reused")
+	}
+
+	if _, found, err := DecryptAttribute(s, "phone", aead); found || err != nil {
+		t.Errorf("DecryptAttribute(missing) = %v, %v", found, err)
+	}
+}
+
+func TestEncryptedAttributeTransplant(t *testing.T) {
+	aead := testAEAD(t, "0123456789abcdef")
+	s, err := SetEncryptedAttribute("urn:user:42", "email", "ada@example.com", aead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stolen, _, _ := Value(s, "email")
+	for _, target := range []string{"urn:user:43", "urn:admin:42", "urn:user4:2"} {
+		moved, err := AddAttribute(target, "email", stolen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, found, err := DecryptAttribute(moved, "email", aead); !found || err == nil {
+			t.Errorf("DecryptAttribute on %s = %v, %v, want authentication failure", target, found, err)
+		}
+	}
+}
+
+func TestEncryptedAttributeMovedToAnotherKey(t *testing.T) {
+	aead := testAEAD(t, "0123456789abcdef")
+	s, err := SetEncryptedAttribute("urn:user:42", "email", "ada@example.com", aead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stolen, _, _ := Value(s, "email")
+	moved, err := AddAttribute(s, "phone", stolen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := DecryptAttribute(moved, "phone", aead); !found || err == nil {
+		t.Errorf("DecryptAttribute(phone) = %v, %v, want authentication failure", found, err)
+	}
+	if v, _, err := DecryptAttribute(moved, "email", aead); err != nil || v != "ada@example.com" {
+		t.Errorf("DecryptAttribute(email) = %q, %v", v, err)
+	}
+}
+
+func TestEncryptedAttributeErrors(t *testing.T) {
+	aead := testAEAD(t, "0123456789abcdef")
+	s, err := SetEncryptedAttribute("urn:user:42", "email", "ada@example.com", aead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := DecryptAttribute(s, "email", testAEAD(t, "fedcba9876543210")); err == nil {
+		t.Error("DecryptAttribute with wrong key succeeded")
+	}
+	for _, v := range []string{"plain", EncryptedMarker + "!!", EncryptedMarker + "AAAA"} {
+		in, _ := AddAttribute("urn:user:42", "email", v)
+		if _, found, err := DecryptAttribute(in, "email", aead); !found || err == nil {
+			t.Errorf("DecryptAttribute(%q) = %v, %v, want error", v, found, err)
+		}
+	}
+
+	long := strings.Repeat("x", MaxURNLength)
+	_, err = SetEncryptedAttribute("urn:user:42", "note", long[:MaxURNLength*3/4-40], aead)
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Kind != KindTooLong {
+		t.Errorf("SetEncryptedAttribute(oversized after encoding) = %v, want KindTooLong", err)
+	}
+}
@@ -0,0 +1,82 @@
+package urn
+
+import (
+	"fmt"
+)
+
+// SetEntityAliases replaces the set of deprecated entity names and their
+// replacements, e.g. {"customer": "customers"}. Aliases match
+// ASCII case-insensitively. Chains are resolved at registration, so with
+// a→b and b→c both a and b map to c; cycles are an error and leave the
+// previous set in place. Calling it with an empty map clears the set.
+//
+// ParseWithAliases maps aliases to their replacement, and Compose,
+// ComposeAttrs, Builder and NewURN reject them unless AllowDeprecated is
+// in effect.
+func SetEntityAliases(aliases map[string]string) error {
+	var resolved map[string]string
+	if len(aliases) > 0 {
+		lower := make(map[string]string, len(aliases))
+		for from, to := range aliases {
+			if from == "" || to == "" {
+				return fmt.Errorf("Invalid entity alias: %q → %q has an empty side", from, to)
+			}
+			lower[asciiToLower(from)] = to
+		}
+		resolved = make(map[string]string, len(lower))
+		for from, to := range lower {
+			seen := map[string]bool{from: true}
+			for {
+				next, ok := lower[asciiToLower(to)]
+				if !ok {
+					break
+				}
+				if seen[asciiToLower(to)] {
+					return fmt.Errorf("Invalid entity alias: cycle through %q", from)
+				}
+				seen[asciiToLower(to)] = true
+				to = next
+			}
+			resolved[from] = to
+		}
+	}
+	updateConfig(func(c *config) {
+		c.entityAliases = resolved
+	})
+	return nil
+}
+
+// AllowDeprecated lets Compose and friends use entities registered as
+// aliases with SetEntityAliases.
+func AllowDeprecated() Option {
+	return func(o *options) {
+		o.allowDeprecated = true
+	}
+}
+
+// ParseWithAliases parses a URN like Parse and replaces a deprecated
+// entity with its registered replacement. AliasedFrom reports the entity
+// found in the input.
+func ParseWithAliases(urnStr string) (*URN, error) {
+	return defaultProcessor.ParseWithAliases(urnStr)
+}
+
+// ParseWithAliases is the Processor form of the package-level
+// ParseWithAliases.
+func (p *Processor) ParseWithAliases(urnStr string) (*URN, error) {
+	o, c := p.snapshot()
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return nil, err
+	}
+	if target, ok := c.entityAliases[asciiToLower(u.Entity)]; ok {
+		u.aliasedFrom, u.Entity = u.Entity, target
+	}
+	return u, nil
+}
+
+// AliasedFrom returns the deprecated entity ParseWithAliases replaced, and
+// whether a replacement happened.
+func (u *URN) AliasedFrom() (string, bool) {
+	return u.aliasedFrom, u.aliasedFrom != ""
+}
@@ -0,0 +1,77 @@
+package urn
+
+import (
+	"errors"
+	"testing"
+)
+
+func setAliases(t *testing.T, aliases map[string]string) {
+	t.Helper()
+	if err := SetEntityAliases(aliases); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetEntityAliases(nil) })
+}
+
+func TestParseWithAliases(t *testing.T) {
+	setAliases(t, map[string]string{"customer": "customers"})
+
+	u, err := ParseWithAliases("urn:Customer:42:tier:gold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Entity != "customers" || u.String() != "urn:customers:42:tier:gold" {
+		t.Errorf("got %s", u)
+	}
+	if from, ok := u.AliasedFrom(); !ok || from != "Customer" {
+		t.Errorf("AliasedFrom = %q, %v", from, ok)
+	}
+
+	u, _ = ParseWithAliases("urn:customers:42")
+	if _, ok := u.AliasedFrom(); ok {
+		t.Error("canonical entity reported as aliased")
+	}
+	u, _ = Parse("urn:customer:42")
+	if u.Entity != "customer" {
+		t.Error("Parse applied aliases")
+	}
+}
+
+func TestAliasChains(t *testing.T) {
+	setAliases(t, map[string]string{"a": "b", "b": "c"})
+	for _, in := range []string{"urn:a:1", "urn:b:1"} {
+		if u, _ := ParseWithAliases(in); u.Entity != "c" {
+			t.Errorf("ParseWithAliases(%s) entity = %s, want c", in, u.Entity)
+		}
+	}
+	for _, aliases := range []map[string]string{
+		{"a": "b", "b": "a"},
+		{"x": "y", "y": "z", "z": "X"},
+		{"a": "a"},
+		{"a": ""},
+	} {
+		if err := SetEntityAliases(aliases); err == nil {
+			t.Errorf("SetEntityAliases(%v) succeeded", aliases)
+		}
+	}
+	if u, _ := ParseWithAliases("urn:a:1"); u.Entity != "c" {
+		t.Error("a rejected registration replaced the previous set")
+	}
+}
+
+func TestComposeRejectsDeprecatedEntity(t *testing.T) {
+	setAliases(t, map[string]string{"customer": "customers"})
+	var e *InvalidURNError
+	if _, err := Compose("customer", "1"); !errors.As(err, &e) || e.Kind != KindInvalidEntity {
+		t.Errorf("Compose with alias: %v", err)
+	}
+	if _, err := NewBuilder("Customer", "1").Build(); err == nil {
+		t.Error("Builder accepted alias")
+	}
+	if s, err := NewProcessor(AllowDeprecated()).Compose("customer", "1"); err != nil || s != "urn:customer:1" {
+		t.Errorf("AllowDeprecated: %s, %v", s, err)
+	}
+	if _, err := Compose("customers", "1"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,56 @@
+package urn
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FromEnv reads a URN from the environment variable name, trims
+// surrounding whitespace and parses it with ParseStrict. Errors name the
+// variable, e.g. "env SERVICE_URN: Invalid URN: ...".
+func FromEnv(name string) (*URN, error) {
+	return FromLookup(os.LookupEnv, name)
+}
+
+// MustFromEnv is like FromEnv but panics on error. It is intended for
+// program start-up.
+func MustFromEnv(name string) *URN {
+	u, err := FromEnv(name)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// FromEnvDefault is like FromEnv but parses fallback when the variable is
+// unset or blank.
+func FromEnvDefault(name, fallback string) (*URN, error) {
+	return fromLookup(os.LookupEnv, name, &fallback)
+}
+
+// FromLookup is FromEnv with a custom lookup function in place of
+// os.LookupEnv, for tests and alternative configuration sources.
+func FromLookup(lookup func(string) (string, bool), name string) (*URN, error) {
+	return fromLookup(lookup, name, nil)
+}
+
+func fromLookup(lookup func(string) (string, bool), name string, fallback *string) (*URN, error) {
+	v, ok := lookup(name)
+	v = strings.TrimSpace(v)
+	if v == "" {
+		switch {
+		case fallback != nil:
+			v = strings.TrimSpace(*fallback)
+		case !ok:
+			return nil, fmt.Errorf("env %s: not set", name)
+		default:
+			return nil, fmt.Errorf("env %s: empty", name)
+		}
+	}
+	u, err := ParseStrict(v)
+	if err != nil {
+		return nil, fmt.Errorf("env %s: %w", name, err)
+	}
+	return u, nil
+}
@@ -0,0 +1,64 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func lookupFrom(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestFromLookup(t *testing.T) {
+	lookup := lookupFrom(map[string]string{
+		"VALID":   "  urn:service:billing\n",
+		"EMPTY":   "   ",
+		"INVALID": "service:billing",
+	})
+
+	u, err := FromLookup(lookup, "VALID")
+	if err != nil || u.Entity != "service" || u.ID != "billing" {
+		t.Errorf("valid: got %v, %v", u, err)
+	}
+
+	tests := []struct{ name, msg string }{
+		{"UNSET", "env UNSET: not set"},
+		{"EMPTY", "env EMPTY: empty"},
+		{"INVALID", "env INVALID: Invalid URN:"},
+	}
+	for _, tt := range tests {
+		_, err := FromLookup(lookup, tt.name)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.msg) {
+			t.Errorf("%s: got %v, want prefix %q", tt.name, err, tt.msg)
+		}
+	}
+
+	_, err = FromLookup(lookup, "INVALID")
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) {
+		t.Errorf("parse error not wrapped: %v", err)
+	}
+}
+
+func TestFromEnvDefault(t *testing.T) {
+	fallback := "urn:service:default"
+	lookup := lookupFrom(map[string]string{"EMPTY": "", "SET": "urn:service:x"})
+	for name, want := range map[string]string{"UNSET": "default", "EMPTY": "default", "SET": "x"} {
+		u, err := fromLookup(lookup, name, &fallback)
+		if err != nil || u.ID != want {
+			t.Errorf("%s: got %v, %v; want ID %s", name, u, err, want)
+		}
+	}
+
+	t.Setenv("URN_TEST_SERVICE", "urn:service:real")
+	if u := MustFromEnv("URN_TEST_SERVICE"); u.ID != "real" {
+		t.Errorf("got %v", u)
+	}
+	if _, err := FromEnvDefault("URN_TEST_UNSET", "bad"); err == nil {
+		t.Error("expected error for invalid fallback")
+	}
+}
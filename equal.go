@@ -0,0 +1,77 @@
+package urn
+
+import "slices"
+
+// Equal reports whether u and other are identical: same entity, ID and
+// attributes in the same order, compared exactly after decoding, except
+// that IDs use the comparator registered with RegisterIDComparator. A
+// valid checksum attribute is ignored.
+func (u *URN) Equal(other *URN) bool {
+	if u.Entity != other.Entity || u.rest != other.rest || !loadConfig().sameID(u.Entity, u.ID, other.ID) {
+		return false
+	}
+	a, b := u.userAttributes(), other.userAttributes()
+	if len(a) != len(b) {
+		return false
+	}
+	for i, p := range a {
+		if p != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// userAttributes returns a copy of the attributes without a valid
+// checksum attribute (see checksumIndex).
+func (u *URN) userAttributes() []Attribute {
+	pairs := slices.Clone(u.attributes)
+	if i := u.checksumIndex(); i >= 0 {
+		pairs = slices.Delete(pairs, i, i+1)
+	}
+	return pairs
+}
+
+// allUserAttributes is userAttributes followed by the attributes
+// MaxAttributesParsed left unparsed (see AllAttributePairs).
+func (u *URN) allUserAttributes() ([]Attribute, error) {
+	all, err := u.AllAttributePairs()
+	if err != nil {
+		return nil, err
+	}
+	return append(u.userAttributes(), all[len(u.attributes):]...), nil
+}
+
+// Equivalent reports whether u and other have the same canonical form:
+// entities compare ASCII case-insensitively and attribute order is
+// ignored, while IDs, keys and values compare exactly after decoding.
+// IDs of entities with a registered IDComparator compare with it.
+func (u *URN) Equivalent(other *URN) bool {
+	cmp := loadConfig().idComparator(u.Entity)
+	if cmp == nil {
+		return u.Canonical() == other.Canonical()
+	}
+	return u.shape() == other.shape() && cmp(u.ID, other.ID)
+}
+
+// shape returns the canonical form with the ID left out, for comparing
+// URNs whose IDs need a comparator.
+func (u *URN) shape() string {
+	v := *u
+	v.ID = ""
+	return v.Canonical()
+}
+
+// Equivalent parses both strings and reports whether they are equivalent.
+// See (*URN).Equivalent.
+func Equivalent(a, b string) (bool, error) {
+	ua, err := Parse(a)
+	if err != nil {
+		return false, err
+	}
+	ub, err := Parse(b)
+	if err != nil {
+		return false, err
+	}
+	return ua.Equivalent(ub), nil
+}
@@ -0,0 +1,28 @@
+package urn
+
+import "testing"
+
+func TestEqualAndEquivalent(t *testing.T) {
+	tests := []struct {
+		a, b              string
+		equal, equivalent bool
+	}{
+		{"urn:orders:1:a:1:b:2", "urn:orders:1:a:1:b:2", true, true},
+		{"urn:orders:1:a:1:b:2", "urn:orders:1:b:2:a:1", false, true},
+		{"urn:orders:1", "urn:ORDERS:1", false, true},
+		{"urn:orders:a%2fb", "URN:orders:a%2Fb", true, true},
+		{"urn:orders:1", "urn:orders:2", false, false},
+		{"urn:orders:1:a:1", "urn:orders:1:A:1", false, false},
+		{"urn:orders:1:a:1:a:1", "urn:orders:1:a:1", false, false},
+	}
+	for _, tt := range tests {
+		a, _ := Parse(tt.a)
+		b, _ := Parse(tt.b)
+		if got := a.Equal(b); got != tt.equal {
+			t.Errorf("Equal(%s, %s) = %v", tt.a, tt.b, got)
+		}
+		if got, err := Equivalent(tt.a, tt.b); err != nil || got != tt.equivalent {
+			t.Errorf("Equivalent(%s, %s) = %v, %v", tt.a, tt.b, got, err)
+		}
+	}
+}
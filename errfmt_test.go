@@ -0,0 +1,263 @@
+package urn
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// errorCases produces one error per construction site. Their default
+// messages are pinned in testdata/errors.golden and must not change.
+var errorCases = []struct {
+	name string
+	err  func() error
+}{
+	{"empty", func() error { _, err := ParseStrict(""); return err }},
+	{"scheme", func() error { _, err := Parse("nope:a:1"); return err }},
+	{"too long", func() error { _, err := Parse("urn:a:" + strings.Repeat("x", 300)); return err }},
+	{"strict too long", func() error { _, err := ParseStrict("urn:a:" + strings.Repeat("x", 300)); return err }},
+	{"missing", func() error { _, err := Parse("urn:a"); return err }},
+	{"empty component", func() error { _, err := Parse("urn::1"); return err }},
+	{"unpaired", func() error { _, err := Parse("urn:a:1:k"); return err }},
+	{"empty attribute", func() error { _, err := Parse("urn:a:1:k:"); return err }},
+	{"empty attribute key", func() error { _, err := Parse("urn:a:1::v"); return err }},
+	{"segments", func() error { _, err := NewProcessor(WithMaxSegments(5)).Parse("urn:a:1:k:v:j:w"); return err }},
+	{"malformed escape", func() error { _, err := Parse("urn:a:1:k:%zz"); return err }},
+	{"control char", func() error { _, err := ParseStrict("urn:ab:1\x01"); return err }},
+	{"non-ASCII entity", func() error { _, err := ParseStrict("urn:ördr:1"); return err }},
+	{"strict entity", func() error { _, err := ParseStrict("urn:a:1"); return err }},
+	{"encoded entity", func() error { _, err := ParseStrict("urn:%61b:1"); return err }},
+	{"ID control", func() error { _, err := ParseStrict("urn:ab:a%01", RejectControlChars()); return err }},
+	{"attribute control", func() error { _, err := ParseStrict("urn:ab:1:k:%01", RejectControlChars()); return err }},
+	{"invalid ID", func() error {
+		RegisterIDValidator("ab", NumericValidator)
+		defer RegisterIDValidator("ab", nil)
+		_, err := ParseStrict("urn:ab:x")
+		return err
+	}},
+	{"UTF-8 ID", func() error { _, err := ParseStrict("urn:ab:%FF"); return err }},
+	{"UTF-8 key", func() error { _, err := ParseStrict("urn:ab:1:%FF:v"); return err }},
+	{"UTF-8 value", func() error { _, err := ParseStrict("urn:ab:1:k:%FF"); return err }},
+	{"mixed separators", func() error { _, err := ParseAny("urn:ab:1/x"); return err }},
+	{"mixed slash", func() error { _, err := ParseAny("urn:ab/1:x"); return err }},
+	{"strict reserved key", func() error {
+		SetReservedKeys("secret")
+		defer SetReservedKeys()
+		_, err := ParseStrict("urn:ab:1:secret:v")
+		return err
+	}},
+	{"missing parameter", func() error {
+		_, err := URNFromRequest(httptest.NewRequest("GET", "/", nil), "id", nil)
+		return err
+	}},
+	{"empty entity part", func() error { _, err := EntityParts("urn:a..b:1"); return err }},
+	{"namespaced empty", func() error { _, err := ComposeNamespaced(nil, "1"); return err }},
+	{"namespaced part", func() error { _, err := ComposeNamespaced([]string{"ok", "bad part"}, "1"); return err }},
+	{"nested nil", func() error { _, err := SetURNAttribute("urn:ab:1", "k", nil); return err }},
+	{"nested empty", func() error { _, err := SetURNAttribute("urn:ab:1", "k", &URN{}); return err }},
+	{"prefix entity", func() error { _, _, err := PrefixRange("bad entity", "x"); return err }},
+	{"prefix composer", func() error {
+		c, err := NewPrefixComposer("ab")
+		if err != nil {
+			return err
+		}
+		_, err = c.Compose("")
+		return err
+	}},
+	{"unterminated quote", func() error { _, err := NewProcessor(WithQuoting()).Parse(`urn:ab:1:k:"abc`); return err }},
+	{"data after quote", func() error { _, err := NewProcessor(WithQuoting()).Parse(`urn:ab:1:k:"a"b`); return err }},
+	{"raw segments", func() error {
+		_, err := AppendAttributeRaw("urn:ab:1"+strings.Repeat(":k:v", 30), "k", "v")
+		return err
+	}},
+	{"raw too long", func() error {
+		_, err := AppendAttributeRaw("urn:ab:"+strings.Repeat("x", 240), "k", strings.Repeat("v", 20))
+		return err
+	}},
+	{"raw scheme", func() error { _, err := AppendAttributeRaw("nope:a:1", "k", "v"); return err }},
+	{"raw empty component", func() error { _, err := AppendAttributeRaw("urn::1", "k", "v"); return err }},
+	{"raw empty attribute", func() error { _, err := AppendAttributeRaw("urn:ab:1::v", "k", "v"); return err }},
+	{"raw missing", func() error { _, err := AppendAttributeRaw("urn:ab", "k", "v"); return err }},
+	{"raw unpaired", func() error { _, err := AppendAttributeRaw("urn:ab:1:k", "k", "v"); return err }},
+	{"filter scheme", func() error { _, err := RequiredKeys("nope:a:1", []string{"k"}, nil); return err }},
+	{"filter empty component", func() error { _, err := RequiredKeys("urn::1", []string{"k"}, nil); return err }},
+	{"filter empty attribute", func() error { _, err := RequiredKeys("urn:ab:1::v", []string{"k"}, nil); return err }},
+	{"filter missing", func() error { _, err := RequiredKeys("urn:ab", []string{"k"}, nil); return err }},
+	{"filter unpaired", func() error { _, err := RequiredKeys("urn:ab:1:k", []string{"k"}, nil); return err }},
+	{"replace empty ID", func() error { _, err := ReplaceID("urn:ab:1", ""); return err }},
+	{"replace rejected ID", func() error {
+		RegisterIDValidator("ab", NumericValidator)
+		defer RegisterIDValidator("ab", nil)
+		_, err := ReplaceID("urn:ab:1", "x")
+		return err
+	}},
+	{"replace entity", func() error { _, err := ReplaceEntity("urn:ab:1", "bad entity"); return err }},
+	{"replace too long", func() error { _, err := ReplaceID("urn:ab:1", strings.Repeat("x", 260)); return err }},
+	{"reference unpaired", func() error { _, err := ResolveReference("urn:ab:1", "::k"); return err }},
+	{"reference empty attribute", func() error { _, err := ResolveReference("urn:ab:1", "::k:"); return err }},
+	{"sub-resource", func() error {
+		RegisterSubResources("order", "line")
+		defer RegisterSubResources("order")
+		_, err := AppendSubResource("urn:order:1", "nope", "2")
+		return err
+	}},
+	{"namespace separator", func() error { _, err := AddNamespacedAttribute("urn:ab:1", "ns", "a.b", "v"); return err }},
+	{"empty namespace part", func() error { _, err := AddAttribute("urn:ab:1", "ns..k", "v"); return err }},
+	{"namespace part format", func() error {
+		SetKeyFormat(regexp.MustCompile(`^[a-z]+$`))
+		defer SetKeyFormat(nil)
+		_, err := AddAttribute("urn:ab:1", "ns.K1", "v")
+		return err
+	}},
+	{"reserved key", func() error {
+		SetReservedKeys("secret")
+		defer SetReservedKeys()
+		_, err := AddAttribute("urn:ab:1", "secret", "v")
+		return err
+	}},
+	{"key format", func() error {
+		SetKeyFormat(regexp.MustCompile(`^[a-z]+$`))
+		defer SetKeyFormat(nil)
+		_, err := AddAttribute("urn:ab:1", "K", "v")
+		return err
+	}},
+	{"compose", func() error { _, err := Compose("", "1"); return err }},
+	{"entity", func() error { _, err := Compose("bad entity", "1"); return err }},
+	{"compose non-ASCII", func() error { _, err := Compose("ördr", "1"); return err }},
+	{"compose alias", func() error {
+		if err := SetEntityAliases(map[string]string{"old": "new"}); err != nil {
+			return err
+		}
+		defer SetEntityAliases(nil)
+		_, err := Compose("old", "1")
+		return err
+	}},
+	{"compose empty key", func() error { _, err := ComposeAttrs("ab", "1", []Attribute{{"", "x"}}); return err }},
+	{"compose empty value", func() error { _, err := ComposeAttrs("ab", "1", []Attribute{{"k", ""}}); return err }},
+	{"compose unpaired", func() error { return ValidateComponentsPairs("ab", "1", "k") }},
+	{"compose too long", func() error { _, err := Compose("ab", strings.Repeat("x", 260)); return err }},
+	{"compose rest too long", func() error {
+		u, err := NewProcessor(MaxAttributesParsed(1)).Parse("urn:ab:1:a:1:b:" + strings.Repeat("x", 200))
+		if err != nil {
+			return err
+		}
+		u.ID = strings.Repeat("y", 50)
+		_, err = u.StringE()
+		return err
+	}},
+	{"literal", func() error { _, err := (&URN{}).StringE(); return err }},
+	{"range unpaired", func() error { _, err := AttributesRange("urn:ab:1:k", 0, 5); return err }},
+	{"range empty attribute", func() error { _, err := AttributesRange("urn:ab:1:k:", 0, 5); return err }},
+}
+
+func TestDefaultErrorMessagesUnchanged(t *testing.T) {
+	var b strings.Builder
+	for _, tc := range errorCases {
+		err := tc.err()
+		if err == nil {
+			t.Errorf("%s: no error", tc.name)
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n", tc.name, err)
+	}
+	_, vs, err := ParseAudit("urn:ab:1:k:v:flag", TrailingKeyAsFlag())
+	if err != nil || len(vs) == 0 {
+		t.Fatalf("ParseAudit = %v, %v", vs, err)
+	}
+	fmt.Fprintf(&b, "audit flag: %s\n", vs[0].Detail)
+	got := b.String()
+
+	golden := filepath.Join("testdata", "errors.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("error messages differ from %s:\n%s", golden, got)
+	}
+}
+
+func TestSetErrorFormatter(t *testing.T) {
+	defaults := make([]string, len(errorCases))
+	for i, tc := range errorCases {
+		defaults[i] = tc.err().Error()
+	}
+	SetErrorFormatter(func(e *InvalidURNError) string {
+		return fmt.Sprintf("[%s] offset=%d limit=%d actual=%d", e.Kind, e.Offset, e.Limit, e.Actual)
+	})
+	t.Cleanup(func() { SetErrorFormatter(nil) })
+
+	for i, tc := range errorCases {
+		err := tc.err()
+		var e *InvalidURNError
+		if !errors.As(err, &e) {
+			t.Errorf("%s: %T is not an *InvalidURNError", tc.name, err)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "["+e.Kind.String()+"]") {
+			t.Errorf("%s: %q did not route through the formatter", tc.name, err)
+		}
+		if DefaultErrorFormatter(e) != defaults[i] {
+			t.Errorf("%s: DefaultErrorFormatter = %q, want %q", tc.name, DefaultErrorFormatter(e), defaults[i])
+		}
+	}
+
+	_, err := Parse("urn:a:" + strings.Repeat("x", 300))
+	if got := err.Error(); got != "[too long] offset=255 limit=255 actual=306" {
+		t.Errorf("structured fields: %s", got)
+	}
+
+	SetErrorFormatter(nil)
+	if _, err := Parse("urn:a"); err.Error() != "Invalid URN: Missing entity or ID component" {
+		t.Errorf("default not restored: %v", err)
+	}
+}
+
+func TestErrorFieldsPopulated(t *testing.T) {
+	tests := []struct {
+		err  func() error
+		want InvalidURNError
+	}{
+		{func() error { _, err := Parse("urn:a:1:k:"); return err },
+			InvalidURNError{Kind: KindEmptyAttribute, Reason: ReasonValue, Key: "k"}},
+		{func() error { _, err := Compose("bad entity", "1"); return err },
+			InvalidURNError{Kind: KindInvalidEntity, Op: OpCompose, Entity: "bad entity"}},
+		{func() error { _, err := ComposeNamespaced([]string{"ok", "bad part"}, "1"); return err },
+			InvalidURNError{Kind: KindInvalidEntity, Reason: ReasonEntityPart, Op: OpCompose, Value: "bad part", Index: 1}},
+		{func() error { _, err := ParseStrict("urn:ab:1:k:%FF"); return err },
+			InvalidURNError{Kind: KindInvalidUTF8, Reason: ReasonValue, Key: "k"}},
+		{func() error { _, err := ParseAny("urn:ab:1/x"); return err },
+			InvalidURNError{Kind: KindMixedSeparators, Value: "/"}},
+		{func() error { _, err := ReplaceID("urn:ab:1", ""); return err },
+			InvalidURNError{Kind: KindEmptyComponent, Reason: ReasonID, Op: OpCompose}},
+	}
+	for _, tt := range tests {
+		var e *InvalidURNError
+		if err := tt.err(); !errors.As(err, &e) {
+			t.Errorf("%v is not an *InvalidURNError", err)
+			continue
+		}
+		got := InvalidURNError{Kind: e.Kind, Reason: e.Reason, Op: e.Op, Entity: e.Entity, ID: e.ID, Key: e.Key, Value: e.Value, Index: e.Index}
+		if got != tt.want {
+			t.Errorf("%s: fields = %+v, want %+v", e, got, tt.want)
+		}
+		if e.Message != "" {
+			t.Errorf("%s: Message is set", e)
+		}
+	}
+
+	e := &InvalidURNError{Kind: KindScheme, Message: "custom"}
+	if e.Error() != "custom" {
+		t.Errorf("Message override = %q", e.Error())
+	}
+}
@@ -0,0 +1,349 @@
+package urn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTooManySegments is wrapped by the InvalidURNError returned when an
+// input has more colon-separated segments than the parse limit allows.
+var ErrTooManySegments = errors.New("too many segments")
+
+// ErrInvalidUTF8 is wrapped by the errors returned when a decoded
+// component is not valid UTF-8.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// ErrorKind classifies an InvalidURNError.
+type ErrorKind int
+
+const (
+	// KindUnknown is the zero ErrorKind.
+	KindUnknown ErrorKind = iota
+	// KindEmpty reports an empty input string.
+	KindEmpty
+	// KindTooLong reports an input or composed URN over the length limit.
+	KindTooLong
+	// KindScheme reports a missing or unknown scheme.
+	KindScheme
+	// KindMissingComponent reports a URN without an entity or ID segment.
+	KindMissingComponent
+	// KindEmptyComponent reports an empty entity or ID.
+	KindEmptyComponent
+	// KindInvalidEntity reports an entity that does not match the entity format.
+	KindInvalidEntity
+	// KindInvalidID reports an ID rejected by a registered IDValidator.
+	KindInvalidID
+	// KindUnpairedKey reports a trailing attribute key without a value.
+	KindUnpairedKey
+	// KindEmptyAttribute reports an attribute with an empty key or value.
+	KindEmptyAttribute
+	// KindMalformedEscape reports an invalid percent-encoding sequence.
+	KindMalformedEscape
+	// KindControlChar reports a control character in the input or a
+	// decoded component.
+	KindControlChar
+	// KindReservedKey reports an attribute key reserved by SetReservedKeys.
+	KindReservedKey
+	// KindInvalidKey reports an attribute key rejected by SetKeyFormat.
+	KindInvalidKey
+	// KindTooManySegments reports an input over the segment limit; the error
+	// wraps ErrTooManySegments.
+	KindTooManySegments
+	// KindMixedSeparators reports input to ParseAny that uses both the
+	// colon and the slash dialect separators.
+	KindMixedSeparators
+	// KindEncodedEntity reports percent-encoding in the scheme or entity,
+	// which ParseStrict rejects.
+	KindEncodedEntity
+	// KindInvalidUTF8 reports a component that decodes to invalid UTF-8;
+	// the error wraps ErrInvalidUTF8.
+	KindInvalidUTF8
+)
+
+var kindNames = [...]string{
+	KindUnknown:          "unknown",
+	KindEmpty:            "empty",
+	KindTooLong:          "too long",
+	KindScheme:           "scheme",
+	KindMissingComponent: "missing component",
+	KindEmptyComponent:   "empty component",
+	KindInvalidEntity:    "invalid entity",
+	KindInvalidID:        "invalid id",
+	KindUnpairedKey:      "unpaired key",
+	KindEmptyAttribute:   "empty attribute",
+	KindMalformedEscape:  "malformed escape",
+	KindControlChar:      "control character",
+	KindReservedKey:      "reserved key",
+	KindInvalidKey:       "invalid key",
+	KindTooManySegments:  "too many segments",
+	KindMixedSeparators:  "mixed separators",
+	KindEncodedEntity:    "encoded entity",
+	KindInvalidUTF8:      "invalid UTF-8",
+}
+
+func (k ErrorKind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "unknown"
+}
+
+// ErrorOp is the operation an InvalidURNError arose from. It selects the
+// prefix of the default message.
+type ErrorOp int
+
+const (
+	// OpParse is parsing or checking a URN string: "Invalid URN".
+	OpParse ErrorOp = iota
+	// OpCompose is building a URN from components: "Cannot compose URN".
+	OpCompose
+	// OpAttribute is checking an attribute key against the key policy:
+	// "Invalid attribute".
+	OpAttribute
+	// OpReference is resolving a relative reference: "Invalid reference".
+	OpReference
+	// OpSetAttribute is setting a nested URN attribute: "Cannot set URN
+	// attribute".
+	OpSetAttribute
+)
+
+var opPrefixes = [...]string{
+	OpParse:        "Invalid URN",
+	OpCompose:      "Cannot compose URN",
+	OpAttribute:    "Invalid attribute",
+	OpReference:    "Invalid reference",
+	OpSetAttribute: "Cannot set URN attribute",
+}
+
+func (op ErrorOp) prefix() string {
+	if op >= 0 && int(op) < len(opPrefixes) {
+		return opPrefixes[op]
+	}
+	return opPrefixes[OpParse]
+}
+
+// ErrorReason refines the Kind of an InvalidURNError for kinds that cover
+// several situations. ReasonNone is the common case of each kind.
+type ErrorReason int
+
+const (
+	// ReasonNone is the zero ErrorReason.
+	ReasonNone ErrorReason = iota
+	// ReasonID places a KindEmptyComponent, KindControlChar or
+	// KindInvalidUTF8 error in the ID.
+	ReasonID
+	// ReasonKey places a KindEmptyAttribute, KindControlChar or
+	// KindInvalidUTF8 error in the attribute key named by Key.
+	ReasonKey
+	// ReasonValue places a KindEmptyAttribute, KindUnpairedKey,
+	// KindControlChar or KindInvalidUTF8 error in the value of the
+	// attribute named by Key.
+	ReasonValue
+	// ReasonNonASCII is a KindInvalidEntity error for an entity with
+	// non-ASCII characters.
+	ReasonNonASCII
+	// ReasonDeprecatedAlias is a KindInvalidEntity error for an entity
+	// that is a deprecated alias of Want.
+	ReasonDeprecatedAlias
+	// ReasonEmptyEntityPart is a KindInvalidEntity error for a dotted
+	// entity with an empty part.
+	ReasonEmptyEntityPart
+	// ReasonEntityPart is a KindInvalidEntity error for the entity part
+	// Value at position Index.
+	ReasonEntityPart
+	// ReasonComposed is a KindTooLong error measured on a composed URN.
+	ReasonComposed
+	// ReasonMissingParameter is a KindEmpty error for the missing request
+	// parameter named by Segment.
+	ReasonMissingParameter
+	// ReasonUnterminatedQuote is a KindMalformedEscape error for a quoted
+	// value without its closing quote.
+	ReasonUnterminatedQuote
+	// ReasonDataAfterQuote is a KindMalformedEscape error for data between
+	// a closing quote and the next separator.
+	ReasonDataAfterQuote
+	// ReasonNamespaceSeparator is a KindInvalidKey error for a key that
+	// contains the namespace separator Value.
+	ReasonNamespaceSeparator
+	// ReasonEmptyNamespacePart is a KindInvalidKey error for a namespaced
+	// key with an empty part.
+	ReasonEmptyNamespacePart
+	// ReasonNotSubResource is a KindInvalidKey error for a key that is not
+	// a registered sub-resource type of Entity.
+	ReasonNotSubResource
+	// ReasonNilNested is a KindMissingComponent error for a nil nested URN.
+	ReasonNilNested
+)
+
+// InvalidURNError is returned when a URN string is malformed.
+//
+// Kind classifies the error, Reason refines it and Op names the operation
+// that failed. Offset is the byte offset into the input at which the
+// problem was found and Segment the raw segment concerned; both are only
+// meaningful for errors produced while parsing. For KindTooLong and
+// KindTooManySegments, Limit is the bound that was exceeded and Actual the
+// measured length or segment count, or 0 when counting stopped at the
+// limit. Entity, ID and Key hold the decoded components concerned, Value
+// the offending text and Want what was expected, where the kind has them.
+//
+// Error renders the error through the formatter installed with
+// SetErrorFormatter, so applications can translate errors from these
+// fields. Message, when set, replaces the default English text; errors
+// from this package leave it empty.
+type InvalidURNError struct {
+	Kind    ErrorKind
+	Reason  ErrorReason
+	Op      ErrorOp
+	Offset  int
+	Segment string
+	Limit   int
+	Actual  int
+	Entity  string
+	ID      string
+	Key     string
+	Value   string
+	Want    string
+	Index   int
+	Message string
+	Err     error
+}
+
+func (e *InvalidURNError) Error() string {
+	if f := loadConfig().errFormatter; f != nil {
+		return f(e)
+	}
+	return DefaultErrorFormatter(e)
+}
+
+// DefaultErrorFormatter renders the default English message from Kind,
+// Reason, Op and the structured fields. Custom formatters can fall back to
+// it for kinds they do not translate.
+func DefaultErrorFormatter(e *InvalidURNError) string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Kind == KindTooLong {
+		var tl *TooLongError
+		if errors.As(e.Err, &tl) {
+			return tl.Error()
+		}
+		if e.Reason == ReasonComposed {
+			return fmt.Sprintf("Composed URN is too long (%d chars, max %d)", e.Actual, e.Limit)
+		}
+	}
+	return e.Op.prefix() + ": " + e.detail()
+}
+
+// detail renders the default message after its prefix.
+func (e *InvalidURNError) detail() string {
+	switch e.Kind {
+	case KindEmpty:
+		if e.Reason == ReasonMissingParameter {
+			return fmt.Sprintf("Missing parameter %q", e.Segment)
+		}
+		return "Empty string"
+	case KindTooLong:
+		return fmt.Sprintf("Too long (%d chars, max %d)", e.Actual, e.Limit)
+	case KindScheme:
+		return "Must start with the 'urn:' scheme"
+	case KindMissingComponent:
+		if e.Reason == ReasonNilNested {
+			return "nested URN is nil"
+		}
+		return "Missing entity or ID component"
+	case KindEmptyComponent:
+		switch {
+		case e.Op == OpParse:
+			return "Entity or ID is empty"
+		case e.Reason == ReasonID:
+			return "'id' is required"
+		}
+		return "'entity' and 'id' are required"
+	case KindInvalidEntity:
+		switch e.Reason {
+		case ReasonNonASCII:
+			return fmt.Sprintf("Entity %q contains non-ASCII characters", e.Entity)
+		case ReasonDeprecatedAlias:
+			return fmt.Sprintf("Entity %q is a deprecated alias of %q", e.Entity, e.Want)
+		case ReasonEmptyEntityPart:
+			return fmt.Sprintf("Empty part in entity %q", e.Entity)
+		case ReasonEntityPart:
+			return fmt.Sprintf("Invalid entity part %d %q", e.Index, e.Value)
+		}
+		return fmt.Sprintf("Invalid entity %q", e.Entity)
+	case KindInvalidID:
+		return fmt.Sprintf("ID %q rejected for entity %s: %s", e.ID, e.Entity, e.Err)
+	case KindUnpairedKey:
+		if e.Reason == ReasonValue {
+			return fmt.Sprintf("Attribute %s missing value", e.Key)
+		}
+		return "Attribute key without value"
+	case KindEmptyAttribute:
+		switch {
+		case e.Reason == ReasonNone:
+			return "Empty attribute key or value"
+		case e.Reason == ReasonKey && e.Op == OpCompose:
+			return "Attribute key is empty"
+		}
+		return fmt.Sprintf("Attribute %s missing value", e.Key)
+	case KindMalformedEscape:
+		switch e.Reason {
+		case ReasonUnterminatedQuote:
+			return "Unterminated quoted value"
+		case ReasonDataAfterQuote:
+			return "Unexpected data after quoted value"
+		}
+		return fmt.Sprintf("Malformed escape sequence in segment %q", e.Segment)
+	case KindControlChar:
+		switch e.Reason {
+		case ReasonID:
+			return "ID contains control characters"
+		case ReasonKey, ReasonValue:
+			return fmt.Sprintf("Attribute %q contains control characters", e.Key)
+		}
+		return fmt.Sprintf("Unescaped control character at position %d", e.Offset)
+	case KindReservedKey:
+		return fmt.Sprintf("Key %q is reserved", e.Key)
+	case KindInvalidKey:
+		switch e.Reason {
+		case ReasonNamespaceSeparator:
+			return fmt.Sprintf("Key %q contains namespace separator %q", e.Key, e.Value)
+		case ReasonEmptyNamespacePart:
+			return fmt.Sprintf("Key %q has an empty namespace part", e.Key)
+		case ReasonNotSubResource:
+			return fmt.Sprintf("%q is not a sub-resource of %s", e.Key, e.Entity)
+		}
+		return fmt.Sprintf("Key %q does not match %s", e.Key, e.Want)
+	case KindTooManySegments:
+		return fmt.Sprintf("Too many segments (max %d)", e.Limit)
+	case KindMixedSeparators:
+		d := DialectColon
+		if e.Value == DialectColon.separator() {
+			d = DialectSlash
+		}
+		return fmt.Sprintf("Unescaped %q in %s dialect URN", e.Value, d)
+	case KindEncodedEntity:
+		return fmt.Sprintf("Percent-encoded scheme or entity %q", e.Segment)
+	case KindInvalidUTF8:
+		switch e.Reason {
+		case ReasonKey:
+			return fmt.Sprintf("Attribute key %q is not valid UTF-8", e.Key)
+		case ReasonValue:
+			return fmt.Sprintf("Value of attribute %q is not valid UTF-8", e.Key)
+		}
+		return "ID is not valid UTF-8"
+	}
+	return e.Kind.String()
+}
+
+// SetErrorFormatter makes every *InvalidURNError render through f. Pass
+// nil to restore DefaultErrorFormatter.
+func SetErrorFormatter(f func(*InvalidURNError) string) {
+	updateConfig(func(c *config) {
+		c.errFormatter = f
+	})
+}
+
+func (e *InvalidURNError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,71 @@
+package urn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	if err := Check("urn:orders:1234:status:pending"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cases := []struct {
+		input   string
+		kind    ErrorKind
+		offset  int
+		segment string
+	}{
+		{"", KindEmpty, 0, ""},
+		{"urn:long:" + string(make([]byte, 300)), KindTooLong, MaxURNLength, ""},
+		{"urn:orders:1\n", KindControlChar, 12, ""},
+		{"isbn:orders:1", KindScheme, 0, ""},
+		{"urn:orders", KindMissingComponent, 10, ""},
+		{"urn:orders:", KindEmptyComponent, 11, ""},
+		{"urn:o:1", KindInvalidEntity, 4, "o"},
+		{"urn:orders:1:status", KindUnpairedKey, 13, "status"},
+		{"urn:orders:1:a:1:status:", KindEmptyAttribute, 24, "status"},
+		{"urn:orders:1:vendor:a%zzb", KindMalformedEscape, 20, "a%zzb"},
+	}
+	messages := make(map[string]ErrorKind)
+	for _, c := range cases {
+		err := Check(c.input)
+		var ue *InvalidURNError
+		if !errors.As(err, &ue) {
+			t.Errorf("%q: expected *InvalidURNError, got %v", c.input, err)
+			continue
+		}
+		if ue.Kind != c.kind || ue.Offset != c.offset || ue.Segment != c.segment {
+			t.Errorf("%q: got kind=%s offset=%d segment=%q, want kind=%s offset=%d segment=%q",
+				c.input, ue.Kind, ue.Offset, ue.Segment, c.kind, c.offset, c.segment)
+		}
+		if prev, ok := messages[ue.Error()]; ok && prev != ue.Kind {
+			t.Errorf("message %q shared by kinds %s and %s", ue.Error(), prev, ue.Kind)
+		}
+		messages[ue.Error()] = ue.Kind
+		if IsValid(c.input) {
+			t.Errorf("%q: IsValid disagrees with Check", c.input)
+		}
+	}
+}
+
+func TestCheckInvalidID(t *testing.T) {
+	RegisterIDValidator("product", NumericValidator)
+	t.Cleanup(func() { RegisterIDValidator("product", nil) })
+
+	var ue *InvalidURNError
+	if !errors.As(Check("urn:product:abc:vendor:x"), &ue) {
+		t.Fatal("expected *InvalidURNError")
+	}
+	if ue.Kind != KindInvalidID || ue.Offset != 12 || ue.Segment != "abc" {
+		t.Errorf("unexpected error: %+v", ue)
+	}
+}
+
+func TestErrorKindString(t *testing.T) {
+	if KindUnpairedKey.String() != "unpaired key" {
+		t.Errorf("unexpected name: %s", KindUnpairedKey)
+	}
+	if ErrorKind(999).String() != "unknown" {
+		t.Errorf("unexpected name for out-of-range kind")
+	}
+}
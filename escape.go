@@ -0,0 +1,119 @@
+package urn
+
+import (
+	"net/url"
+	"strings"
+)
+
+const upperhex = "0123456789ABCDEF"
+
+// shouldEscape reports whether c must be percent-encoded inside a segment.
+// It follows url.PathEscape, except that ':' is always encoded so that a
+// segment can never be mistaken for a separator.
+func shouldEscape(c byte) bool {
+	if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+		return false
+	}
+	switch c {
+	case '-', '_', '.', '~', '$', '&', '+', '=', '@':
+		return false
+	}
+	return true
+}
+
+// escapedLen returns the length of escape(s) without building it.
+func escapedLen(s string) int {
+	n := len(s)
+	for i := 0; i < len(s); i++ {
+		if shouldEscape(s[i]) {
+			n += 2
+		}
+	}
+	return n
+}
+
+// escape percent-encodes a single URN segment.
+func escape(s string) string {
+	n := escapedLen(s)
+	if n == len(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(n)
+	writeEscape(&b, s)
+	return b.String()
+}
+
+// writeEscape writes the escaped form of s to b.
+func writeEscape(b *strings.Builder, s string) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if shouldEscape(c) {
+			b.WriteByte('%')
+			b.WriteByte(upperhex[c>>4])
+			b.WriteByte(upperhex[c&15])
+		} else {
+			b.WriteByte(c)
+		}
+	}
+}
+
+// EscapeSegment percent-encodes s exactly as Compose encodes an entity,
+// ID, key or value: ASCII letters, digits and -_.~$&+=@ are kept, and every
+// other byte, including ':' and control characters, becomes %XX with
+// uppercase hex digits.
+func EscapeSegment(s string) string {
+	return escape(s)
+}
+
+// UnescapeSegment decodes a segment exactly as Parse does. It is the
+// inverse of EscapeSegment and also accepts lowercase hex digits.
+func UnescapeSegment(s string) (string, error) {
+	return unescape(s)
+}
+
+// appendEscape appends the escaped form of s to dst.
+func appendEscape(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if shouldEscape(c) {
+			dst = append(dst, '%', upperhex[c>>4], upperhex[c&15])
+		} else {
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// unescape decodes a single percent-encoded URN segment.
+func unescape(s string) (string, error) {
+	return unescapeAt(s, 0)
+}
+
+// unescapeAt decodes a segment found at byte offset off of the input,
+// reporting that offset on failure.
+func unescapeAt(s string, off int) (string, error) {
+	if !strings.Contains(s, "%") {
+		return s, nil
+	}
+	v, err := url.PathUnescape(s)
+	if err != nil {
+		return "", &InvalidURNError{
+			Kind:    KindMalformedEscape,
+			Offset:  off,
+			Segment: s,
+		}
+	}
+	return v, nil
+}
+
+// indexControl returns the index of the first control character
+// (0x00–0x1F, 0x7F) in s, or -1.
+func indexControl(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] == 0x7f {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,86 @@
+package urn
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestComposeEscapesControlChars(t *testing.T) {
+	for _, c := range []struct{ raw, escaped string }{
+		{"\n", "%0A"},
+		{"\t", "%09"},
+		{"\x00", "%00"},
+		{"\x7f", "%7F"},
+	} {
+		s, err := Compose("notes", "a"+c.raw+"b", map[string]string{"text": c.raw})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "urn:notes:a" + c.escaped + "b:text:" + c.escaped
+		if s != want {
+			t.Errorf("expected %s, got %s", want, s)
+		}
+		if indexControl(s) >= 0 {
+			t.Errorf("composed URN contains a raw control character: %q", s)
+		}
+		u, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.ID != "a"+c.raw+"b" || u.Attributes()["text"] != c.raw {
+			t.Errorf("unexpected decode for %q: %+v", c.raw, u)
+		}
+	}
+}
+
+func TestParseStrictControlChars(t *testing.T) {
+	if _, err := ParseStrict("urn:notes:a\nb"); err == nil {
+		t.Error("expected error for raw newline")
+	}
+	if _, err := ParseStrict("urn:notes:a%0Ab:text:%09"); err != nil {
+		t.Errorf("escaped control characters should be accepted by default: %v", err)
+	}
+	for _, s := range []string{
+		"urn:notes:a%0Ab",
+		"urn:notes:1:text:%09",
+		"urn:notes:1:%00:x",
+		"urn:notes:1:text:%7F",
+	} {
+		if _, err := ParseStrict(s, RejectControlChars()); err == nil {
+			t.Errorf("expected error for %s", s)
+		}
+	}
+	if _, err := ParseStrict("urn:notes:1:text:plain", RejectControlChars()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEscapeSegmentRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		b := make([]byte, rng.Intn(20))
+		for j := range b {
+			b[j] = byte(rng.Intn(256))
+		}
+		s := string(b)
+		esc := EscapeSegment(s)
+		if strings.Contains(esc, ":") {
+			t.Fatalf("EscapeSegment(%q) = %q contains a separator", s, esc)
+		}
+		got, err := UnescapeSegment(esc)
+		if err != nil || got != s {
+			t.Fatalf("UnescapeSegment(EscapeSegment(%q)) = %q, %v", s, got, err)
+		}
+		if s == "" {
+			continue
+		}
+		composed, err := Compose("items", s, map[string]string{"k": s})
+		if err != nil {
+			continue // over the length limit
+		}
+		if want := "urn:items:" + esc + ":k:" + esc; composed != want {
+			t.Fatalf("Compose = %q, want %q", composed, want)
+		}
+	}
+}
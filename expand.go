@@ -0,0 +1,64 @@
+package urn
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// UnresolvedVariablesError lists the variables ExpandVariables could not
+// resolve, in order of first appearance.
+type UnresolvedVariablesError struct {
+	Names []string
+}
+
+func (e *UnresolvedVariablesError) Error() string {
+	return "Cannot expand URN: Unresolved variables " + strings.Join(e.Names, ", ")
+}
+
+// ExpandVariables replaces each ${NAME} in urnStr with the escaped value
+// lookup returns for NAME, then checks that the result parses. Values are
+// escaped like any other segment content, so a value containing ':' stays
+// within its segment instead of adding segments. All unresolved variables
+// are reported together in an *UnresolvedVariablesError.
+func ExpandVariables(urnStr string, lookup func(string) (string, bool)) (string, error) {
+	var b strings.Builder
+	var missing []string
+	rest := urnStr
+	for {
+		i := strings.Index(rest, "${")
+		if i < 0 {
+			b.WriteString(rest)
+			break
+		}
+		b.WriteString(rest[:i])
+		j := strings.IndexByte(rest[i+2:], '}')
+		if j < 0 {
+			return "", fmt.Errorf("Cannot expand URN: Unterminated variable at offset %d", len(urnStr)-len(rest)+i)
+		}
+		name := rest[i+2 : i+2+j]
+		if name == "" {
+			return "", fmt.Errorf("Cannot expand URN: Empty variable name at offset %d", len(urnStr)-len(rest)+i)
+		}
+		if v, ok := lookup(name); ok {
+			writeEscape(&b, v)
+		} else if !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+		rest = rest[i+2+j+1:]
+	}
+	if len(missing) > 0 {
+		return "", &UnresolvedVariablesError{Names: missing}
+	}
+	s := b.String()
+	if _, err := Parse(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// ExpandEnv is ExpandVariables resolving variables with os.LookupEnv.
+func ExpandEnv(urnStr string) (string, error) {
+	return ExpandVariables(urnStr, os.LookupEnv)
+}
@@ -0,0 +1,56 @@
+package urn
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestExpandVariables(t *testing.T) {
+	vars := map[string]string{
+		"REGION": "eu-west-1",
+		"TENANT": "acme:corp/eu",
+		"EVIL":   "x:admin:true",
+	}
+	lookup := func(k string) (string, bool) {
+		v, ok := vars[k]
+		return v, ok
+	}
+	tests := []struct{ in, want string }{
+		{"urn:queue:${REGION}-ingest:tenant:${TENANT}", "urn:queue:eu-west-1-ingest:tenant:acme%3Acorp%2Feu"},
+		{"urn:queue:1:role:${EVIL}", "urn:queue:1:role:x%3Aadmin%3Atrue"},
+		{"urn:queue:plain", "urn:queue:plain"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandVariables(tt.in, lookup)
+		if err != nil || got != tt.want {
+			t.Errorf("ExpandVariables(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+	got, _ := ExpandVariables("urn:queue:1:role:${EVIL}", lookup)
+	if v, _, _ := Value(got, "role"); v != "x:admin:true" {
+		t.Errorf("role = %q", v)
+	}
+	if _, found, _ := Value(got, "admin"); found {
+		t.Error("substituted value injected an attribute")
+	}
+
+	_, err := ExpandVariables("urn:${A}:${REGION}:k:${B}:j:${A}", lookup)
+	var e *UnresolvedVariablesError
+	if !errors.As(err, &e) || !slices.Equal(e.Names, []string{"A", "B"}) {
+		t.Errorf("unresolved: %v", err)
+	}
+	for _, in := range []string{"urn:q:${REGION", "urn:q:${}", "urn:q:${REGION}:k"} {
+		if _, err := ExpandVariables(in, lookup); err == nil {
+			t.Errorf("ExpandVariables(%q) succeeded", in)
+		}
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("URN_TEST_TENANT", "a:b")
+	got, err := ExpandEnv("urn:tenant:${URN_TEST_TENANT}")
+	if err != nil || got != "urn:tenant:a%3Ab" {
+		t.Errorf("ExpandEnv = %q, %v", got, err)
+	}
+}
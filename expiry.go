@@ -0,0 +1,136 @@
+package urn
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultExpiryKey is the attribute holding the expiry time unless
+// WithExpiryKey selects another.
+const DefaultExpiryKey = "exp"
+
+// ExpiryFormat selects how WithExpiry encodes the expiry time.
+type ExpiryFormat int
+
+const (
+	// ExpiryUnix encodes the expiry as decimal Unix seconds. It is the
+	// default.
+	ExpiryUnix ExpiryFormat = iota
+	// ExpiryRFC3339 encodes the expiry as an RFC 3339 timestamp in UTC.
+	ExpiryRFC3339
+)
+
+// WithExpiryKey makes WithExpiry, Expiry and IsExpired use key instead of
+// DefaultExpiryKey.
+func WithExpiryKey(key string) Option {
+	return func(o *options) {
+		o.expiryKey = key
+	}
+}
+
+// WithExpiryFormat selects the encoding WithExpiry writes and Expiry and
+// IsExpired read.
+func WithExpiryFormat(f ExpiryFormat) Option {
+	return func(o *options) {
+		o.expiryFormat = f
+	}
+}
+
+func (o *options) expiryAttr() string {
+	if o.expiryKey != "" {
+		return o.expiryKey
+	}
+	return DefaultExpiryKey
+}
+
+// WithExpiry sets the expiry attribute of urnStr to t, truncated to whole
+// seconds. See (*Processor).WithExpiry.
+func WithExpiry(urnStr string, t time.Time) (string, error) {
+	return defaultProcessor.WithExpiry(urnStr, t)
+}
+
+// Expiry returns the expiry time of urnStr. See (*Processor).Expiry.
+func Expiry(urnStr string) (time.Time, bool, error) {
+	return defaultProcessor.Expiry(urnStr)
+}
+
+// IsExpired reports whether urnStr has expired at now. See
+// (*Processor).IsExpired.
+func IsExpired(urnStr string, now time.Time) (bool, error) {
+	return defaultProcessor.IsExpired(urnStr, now)
+}
+
+// ExpiresIn sets the expiry attribute of urnStr to d from now. See
+// (*Processor).ExpiresIn.
+func ExpiresIn(urnStr string, d time.Duration) (string, error) {
+	return defaultProcessor.ExpiresIn(urnStr, d)
+}
+
+// Expired reports whether urnStr has expired now. See
+// (*Processor).Expired.
+func Expired(urnStr string) (bool, error) {
+	return defaultProcessor.Expired(urnStr)
+}
+
+// WithExpiry sets the expiry attribute of urnStr to t, truncated to whole
+// seconds, replacing any earlier expiry.
+func (p *Processor) WithExpiry(urnStr string, t time.Time) (string, error) {
+	o, _ := p.snapshot()
+	var v string
+	switch o.expiryFormat {
+	case ExpiryRFC3339:
+		v = t.UTC().Format(time.RFC3339)
+	default:
+		v = strconv.FormatInt(t.Unix(), 10)
+	}
+	return p.AddAttribute(urnStr, o.expiryAttr(), v)
+}
+
+// Expiry returns the expiry time recorded in urnStr and whether it has
+// one. An expiry value not in the configured format is an error.
+func (p *Processor) Expiry(urnStr string) (time.Time, bool, error) {
+	o, _ := p.snapshot()
+	key := o.expiryAttr()
+	v, found, err := p.Value(urnStr, key)
+	if err != nil || !found {
+		return time.Time{}, false, err
+	}
+	var t time.Time
+	switch o.expiryFormat {
+	case ExpiryRFC3339:
+		t, err = time.Parse(time.RFC3339, v)
+	default:
+		var sec int64
+		if sec, err = strconv.ParseInt(v, 10, 64); err == nil {
+			t = time.Unix(sec, 0)
+		}
+	}
+	if err != nil {
+		return time.Time{}, true, fmt.Errorf("Invalid expiry attribute %s=%q: %w", key, v, err)
+	}
+	return t, true, nil
+}
+
+// IsExpired reports whether urnStr has expired at now: a URN expires at
+// the instant recorded in its expiry attribute, so now equal to the
+// expiry counts as expired. A URN without an expiry never expires.
+func (p *Processor) IsExpired(urnStr string, now time.Time) (bool, error) {
+	t, found, err := p.Expiry(urnStr)
+	if err != nil || !found {
+		return false, err
+	}
+	return !now.Before(t), nil
+}
+
+// ExpiresIn is WithExpiry with a time d after the current time, as read
+// from the clock installed with SetClock.
+func (p *Processor) ExpiresIn(urnStr string, d time.Duration) (string, error) {
+	return p.WithExpiry(urnStr, currentTime().Add(d))
+}
+
+// Expired is IsExpired at the current time, as read from the clock
+// installed with SetClock.
+func (p *Processor) Expired(urnStr string) (bool, error) {
+	return p.IsExpired(urnStr, currentTime())
+}
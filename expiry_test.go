@@ -0,0 +1,90 @@
+package urn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiryUnix(t *testing.T) {
+	exp := time.Date(2030, 1, 2, 3, 4, 5, 999, time.UTC)
+	s, err := WithExpiry("urn:grant:1:scope:read", exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "urn:grant:1:scope:read:exp:1893553445"; s != want {
+		t.Errorf("WithExpiry = %q, want %q", s, want)
+	}
+	got, ok, err := Expiry(s)
+	if err != nil || !ok || !got.Equal(exp.Truncate(time.Second)) {
+		t.Errorf("Expiry = %v, %v, %v", got, ok, err)
+	}
+
+	// Setting the expiry again replaces it.
+	s, _ = WithExpiry(s, exp.Add(time.Hour))
+	if attrs, _ := GetAllAttributes(s); len(attrs) != 2 || attrs["exp"] != "1893557045" {
+		t.Errorf("attributes after a second WithExpiry: %v", attrs)
+	}
+}
+
+func TestIsExpiredBoundary(t *testing.T) {
+	exp := time.Unix(1_900_000_000, 0)
+	s, _ := WithExpiry("urn:grant:1", exp)
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{exp.Add(-time.Nanosecond), false},
+		{exp, true},
+		{exp.Add(time.Nanosecond), true},
+	}
+	for _, tt := range tests {
+		got, err := IsExpired(s, tt.now)
+		if err != nil || got != tt.want {
+			t.Errorf("IsExpired at %v = %v, %v; want %v", tt.now.Sub(exp), got, err, tt.want)
+		}
+	}
+	if got, err := IsExpired("urn:grant:1", exp); got || err != nil {
+		t.Errorf("URN without expiry: %v, %v", got, err)
+	}
+}
+
+func TestExpiryRFC3339(t *testing.T) {
+	p := NewProcessor(WithExpiryKey("until"), WithExpiryFormat(ExpiryRFC3339))
+	exp := time.Date(2030, 1, 2, 3, 4, 5, 0, time.FixedZone("x", 3600))
+	s, err := p.WithExpiry("urn:grant:1", exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "urn:grant:1:until:2030-01-02T02%3A04%3A05Z"; s != want {
+		t.Errorf("WithExpiry = %q, want %q", s, want)
+	}
+	if expired, err := p.IsExpired(s, exp); !expired || err != nil {
+		t.Errorf("IsExpired at the instant = %v, %v", expired, err)
+	}
+	if expired, _ := p.IsExpired(s, exp.Add(-time.Second)); expired {
+		t.Error("expired a second early")
+	}
+}
+
+func TestExpiryMalformed(t *testing.T) {
+	rfc := NewProcessor(WithExpiryFormat(ExpiryRFC3339))
+	tests := []struct {
+		p  *Processor
+		in string
+	}{
+		{defaultProcessor, "urn:grant:1:exp:soon"},
+		{defaultProcessor, "urn:grant:1:exp:1.5"},
+		{defaultProcessor, "urn:grant:1:exp:99999999999999999999"},
+		{defaultProcessor, "urn:grant:1:exp:2030-01-02T02%3A04%3A05Z"},
+		{rfc, "urn:grant:1:exp:1893553445"},
+		{rfc, "urn:grant:1:exp:2030-13-02T02%3A04%3A05Z"},
+	}
+	for _, tt := range tests {
+		if _, found, err := tt.p.Expiry(tt.in); err == nil || !found {
+			t.Errorf("Expiry(%q) = %v, %v; want an error", tt.in, found, err)
+		}
+		if _, err := tt.p.IsExpired(tt.in, time.Now()); err == nil {
+			t.Errorf("IsExpired(%q) accepted a malformed expiry", tt.in)
+		}
+	}
+}
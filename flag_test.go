@@ -0,0 +1,55 @@
+package urn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrailingKeyAsFlag(t *testing.T) {
+	if _, err := Parse("urn:job:42:urgent"); err == nil {
+		t.Fatal("unpaired key accepted without the option")
+	}
+
+	p := NewProcessor(TrailingKeyAsFlag())
+	u, err := p.Parse("urn:job:42:queue:fast:urgent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Attribute{{"queue", "fast"}, {"urgent", FlagValue}}
+	if !reflect.DeepEqual(u.AttributePairs(), want) || !u.HasTrailingFlag() {
+		t.Errorf("got %v, flag %v", u.AttributePairs(), u.HasTrailingFlag())
+	}
+
+	// The legacy form round-trips through String and Format.
+	if s := u.String(); s != "urn:job:42:queue:fast:urgent" {
+		t.Errorf("String() = %s", s)
+	}
+	if s, _ := p.Format(u); s != "urn:job:42:queue:fast:urgent" {
+		t.Errorf("Format = %s", s)
+	}
+	// Without the option the flag is written as a regular pair.
+	if s, _ := NewProcessor().Format(u); s != "urn:job:42:queue:fast:urgent:true" {
+		t.Errorf("Format without option = %s", s)
+	}
+
+	// Regular URNs are unaffected.
+	u, _ = p.Parse("urn:job:42:urgent:true")
+	if u.HasTrailingFlag() || u.String() != "urn:job:42:urgent:true" {
+		t.Errorf("paired URN changed: %s", u)
+	}
+
+	for _, bad := range []string{"urn:job:42:", "urn:job:42:a::b"} {
+		if _, err := p.Parse(bad); err == nil {
+			t.Errorf("Parse(%q): expected error", bad)
+		}
+	}
+}
+
+func TestTrailingKeyAsFlagStrictUnchanged(t *testing.T) {
+	if _, err := ParseStrict("urn:job:42:urgent", TrailingKeyAsFlag()); err == nil {
+		t.Error("ParseStrict accepted an unpaired key")
+	}
+	if NewProcessor(TrailingKeyAsFlag()).IsValid("urn:job:42:urgent") {
+		t.Error("IsValid accepted an unpaired key")
+	}
+}
@@ -0,0 +1,120 @@
+package urn
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Generator produces the ID for a new URN of the given entity.
+type Generator interface {
+	NewID(entity string) (string, error)
+}
+
+// GeneratorFunc adapts a function to the Generator interface.
+type GeneratorFunc func(entity string) (string, error)
+
+// NewID calls f(entity).
+func (f GeneratorFunc) NewID(entity string) (string, error) {
+	return f(entity)
+}
+
+// UUIDGenerator generates random UUIDs, as CreateUUID does, from the
+// source installed with SetUUIDSource.
+var UUIDGenerator Generator = GeneratorFunc(func(string) (string, error) {
+	return newUUID().String(), nil
+})
+
+// New composes a URN for entity with an ID taken from gen.
+func New(entity string, gen Generator) (string, error) {
+	id, err := gen.NewID(entity)
+	if err != nil {
+		return "", err
+	}
+	return Compose(entity, id)
+}
+
+// SequentialGenerator issues readable, sortable IDs from a counter per
+// entity: "000001", "000002", … It is meant for tests and local
+// development and is safe for concurrent use.
+type SequentialGenerator struct {
+	width int
+
+	mu       sync.Mutex
+	counters map[string]uint64
+}
+
+// NewSequentialGenerator returns a SequentialGenerator whose IDs are
+// zero-padded to width digits. Counters beyond width digits are not
+// truncated.
+func NewSequentialGenerator(width int) *SequentialGenerator {
+	return &SequentialGenerator{width: width, counters: make(map[string]uint64)}
+}
+
+// NewID returns the next ID for entity, starting at 1.
+func (g *SequentialGenerator) NewID(entity string) (string, error) {
+	g.mu.Lock()
+	g.counters[entity]++
+	n := g.counters[entity]
+	g.mu.Unlock()
+	return fmt.Sprintf("%0*d", g.width, n), nil
+}
+
+// Reset restarts the counters of the given entities, or of every entity
+// when called without arguments.
+func (g *SequentialGenerator) Reset(entities ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(entities) == 0 {
+		clear(g.counters)
+		return
+	}
+	for _, e := range entities {
+		delete(g.counters, e)
+	}
+}
+
+// ErrExhaustedAttempts is wrapped by the *ExhaustedAttemptsError NewUnique
+// returns when every generated URN already exists.
+var ErrExhaustedAttempts = errors.New("exhausted attempts")
+
+// ExhaustedAttemptsError reports that NewUnique gave up after Attempts
+// generated URNs all existed. It wraps ErrExhaustedAttempts.
+type ExhaustedAttemptsError struct {
+	Attempts int
+	// Last is the last URN generated.
+	Last string
+}
+
+func (e *ExhaustedAttemptsError) Error() string {
+	return fmt.Sprintf("Cannot create unique URN: %d attempts collided (last %s)", e.Attempts, e.Last)
+}
+
+func (e *ExhaustedAttemptsError) Unwrap() error {
+	return ErrExhaustedAttempts
+}
+
+// NewUnique composes a URN for entity with an ID from gen, as New does,
+// and asks exists whether it is already taken, generating another until
+// one is free or maxAttempts URNs have collided; then it returns an
+// *ExhaustedAttemptsError. Values of maxAttempts below 1 allow a single
+// attempt. An error from gen or exists stops at once; the one from exists
+// is returned wrapped.
+func NewUnique(entity string, gen Generator, exists func(urnStr string) (bool, error), maxAttempts int) (string, error) {
+	maxAttempts = max(maxAttempts, 1)
+	var s string
+	for range maxAttempts {
+		var err error
+		if s, err = New(entity, gen); err != nil {
+			return "", err
+		}
+		taken, err := exists(s)
+		if err != nil {
+			return "", fmt.Errorf("Cannot create unique URN: checking %s: %w", s, err)
+		}
+		if !taken {
+			return s, nil
+		}
+	}
+	return "", &ExhaustedAttemptsError{Attempts: maxAttempts, Last: s}
+}
@@ -0,0 +1,147 @@
+package urn
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSequentialGenerator(t *testing.T) {
+	g := NewSequentialGenerator(6)
+	for _, want := range []string{"urn:orders:000001", "urn:orders:000002"} {
+		if got, err := New("orders", g); err != nil || got != want {
+			t.Errorf("New = %s, %v, want %s", got, err, want)
+		}
+	}
+	if got, _ := New("users", g); got != "urn:users:000001" {
+		t.Errorf("users counter shared: %s", got)
+	}
+
+	g.Reset("orders")
+	if got, _ := New("orders", g); got != "urn:orders:000001" {
+		t.Errorf("after Reset(orders): %s", got)
+	}
+	if got, _ := New("users", g); got != "urn:users:000002" {
+		t.Errorf("Reset(orders) touched users: %s", got)
+	}
+	g.Reset()
+	if got, _ := New("users", g); got != "urn:users:000001" {
+		t.Errorf("after Reset(): %s", got)
+	}
+
+	narrow := NewSequentialGenerator(1)
+	for range 10 {
+		narrow.NewID("x")
+	}
+	if id, _ := narrow.NewID("x"); id != "11" {
+		t.Errorf("overflowing width: %s", id)
+	}
+}
+
+func TestSequentialGeneratorConcurrent(t *testing.T) {
+	g := NewSequentialGenerator(4)
+	entities := []string{"orders", "users", "items"}
+	const perEntity = 200
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for _, e := range entities {
+		for range 4 {
+			wg.Go(func() {
+				for range perEntity / 4 {
+					s, err := New(e, g)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					mu.Lock()
+					if seen[s] {
+						t.Errorf("duplicate %s", s)
+					}
+					seen[s] = true
+					mu.Unlock()
+				}
+			})
+		}
+	}
+	wg.Wait()
+	if len(seen) != len(entities)*perEntity {
+		t.Errorf("generated %d URNs, want %d", len(seen), len(entities)*perEntity)
+	}
+	for _, e := range entities {
+		if id, _ := g.NewID(e); id != "0201" {
+			t.Errorf("%s next ID = %s, want 0201", e, id)
+		}
+	}
+}
+
+func TestNewGeneratorError(t *testing.T) {
+	boom := errors.New("boom")
+	gen := GeneratorFunc(func(string) (string, error) { return "", boom })
+	if _, err := New("orders", gen); !errors.Is(err, boom) {
+		t.Errorf("got %v", err)
+	}
+	s, err := New("orders", UUIDGenerator)
+	if err != nil || !IsValid(s) {
+		t.Errorf("UUIDGenerator: %s, %v", s, err)
+	}
+}
+
+// collidingStore reports the first collisions URNs it is asked about as
+// taken.
+type collidingStore struct {
+	collisions int
+	checked    []string
+}
+
+func (s *collidingStore) exists(urnStr string) (bool, error) {
+	s.checked = append(s.checked, urnStr)
+	return len(s.checked) <= s.collisions, nil
+}
+
+func TestNewUnique(t *testing.T) {
+	for _, collisions := range []int{0, 1, 4} {
+		store := &collidingStore{collisions: collisions}
+		got, err := NewUnique("orders", NewSequentialGenerator(3), store.exists, 5)
+		if err != nil {
+			t.Fatalf("%d collisions: %v", collisions, err)
+		}
+		want := "urn:orders:00" + string(rune('1'+collisions))
+		if got != want || len(store.checked) != collisions+1 {
+			t.Errorf("%d collisions: got %s after %d checks, want %s", collisions, got, len(store.checked), want)
+		}
+	}
+}
+
+func TestNewUniqueExhausted(t *testing.T) {
+	store := &collidingStore{collisions: 10}
+	_, err := NewUnique("orders", NewSequentialGenerator(3), store.exists, 3)
+	var ee *ExhaustedAttemptsError
+	if !errors.As(err, &ee) || ee.Attempts != 3 || ee.Last != "urn:orders:003" {
+		t.Fatalf("err = %v, want ExhaustedAttemptsError after 3 attempts", err)
+	}
+	if !errors.Is(err, ErrExhaustedAttempts) {
+		t.Error("error does not wrap ErrExhaustedAttempts")
+	}
+	if len(store.checked) != 3 {
+		t.Errorf("checked %d URNs, want 3", len(store.checked))
+	}
+}
+
+func TestNewUniqueCallbackError(t *testing.T) {
+	errStore := errors.New("store unavailable")
+	calls := 0
+	_, err := NewUnique("orders", UUIDGenerator, func(string) (bool, error) {
+		calls++
+		return false, errStore
+	}, 5)
+	if !errors.Is(err, errStore) || calls != 1 {
+		t.Errorf("err = %v after %d calls, want errStore after 1", err, calls)
+	}
+
+	errGen := errors.New("no IDs left")
+	gen := GeneratorFunc(func(string) (string, error) { return "", errGen })
+	if _, err := NewUnique("orders", gen, func(string) (bool, error) { return false, nil }, 5); !errors.Is(err, errGen) {
+		t.Errorf("err = %v, want errGen", err)
+	}
+}
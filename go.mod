@@ -1,5 +1,21 @@
 module github.com/layerfly/go-urn
 
-go 1.25
+go 1.25.0
 
-require github.com/google/uuid v1.6.0
+require (
+	github.com/fxamacker/cbor/v2 v2.9.2
+	github.com/go-playground/validator/v10 v10.30.3
+	github.com/google/go-cmp v0.7.0
+	github.com/google/uuid v1.6.0
+)
+
+require (
+	github.com/gabriel-vasile/mimetype v1.4.13 // indirect
+	github.com/go-playground/locales v0.14.1 // indirect
+	github.com/go-playground/universal-translator v0.18.1 // indirect
+	github.com/leodido/go-urn v1.4.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/crypto v0.52.0 // indirect
+	golang.org/x/sys v0.45.0 // indirect
+	golang.org/x/text v0.37.0 // indirect
+)
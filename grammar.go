@@ -0,0 +1,145 @@
+package urn
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Grammar describes the URN syntax a Processor accepts and emits, for
+// generating documentation from the source of truth. It reflects the
+// options and the package-level key policy in effect when it was built.
+type Grammar struct {
+	// Schemes lists the accepted schemes, "urn" first and then any
+	// WithSchemeAliases aliases in sorted order. Schemes match
+	// case-insensitively.
+	Schemes []string
+	// MaxLength is the length limit of the composed "urn:" form.
+	MaxLength int
+	// MaxSegments is the segment limit, scheme included.
+	MaxSegments int
+	// EntityPattern is the regular expression entities must match in
+	// ParseStrict and when composing.
+	EntityPattern string
+	// EntityMaxLength bounds the entity length when EntityPattern does not.
+	EntityMaxLength int
+	// SeparatorRune separates the segments.
+	SeparatorRune rune
+	// EscapedRunes lists the printable ASCII characters that are
+	// percent-encoded. Control characters, spaces and non-ASCII bytes are
+	// always encoded as well.
+	EscapedRunes []rune
+	// ReservedKeys lists, in sorted order, the attribute keys that cannot
+	// be set, as passed to SetReservedKeys.
+	ReservedKeys []string
+	// KeyPattern is the regular expression set with SetKeyFormat, or ""
+	// when keys are unrestricted.
+	KeyPattern string
+	// QuotedValues reports that WithQuoting is in effect.
+	QuotedValues bool
+	// TrailingFlag reports that TrailingKeyAsFlag is in effect.
+	TrailingFlag bool
+}
+
+// DefaultGrammar returns the Grammar of the package-level functions.
+func DefaultGrammar() Grammar {
+	return defaultProcessor.Grammar()
+}
+
+// Grammar returns the Grammar of the Processor under its current options.
+func (p *Processor) Grammar() Grammar {
+	o, c := p.snapshot()
+	g := Grammar{
+		Schemes:       []string{"urn"},
+		MaxLength:     MaxURNLength,
+		MaxSegments:   o.segmentLimit(),
+		EntityPattern: entityRegex.String(),
+		SeparatorRune: ':',
+		QuotedValues:  o.quoting,
+		TrailingFlag:  o.trailingFlag,
+	}
+	if o.allowDots {
+		g.EntityPattern = dottedEntityRegex.String()
+		g.EntityMaxLength = 32
+	}
+	var aliases []string
+	for alias := range o.schemeAliases {
+		aliases = append(aliases, alias)
+	}
+	slices.Sort(aliases)
+	g.Schemes = append(g.Schemes, aliases...)
+	for r := rune(0x21); r < 0x7f; r++ {
+		if shouldEscape(byte(r)) {
+			g.EscapedRunes = append(g.EscapedRunes, r)
+		}
+	}
+	for k := range c.reservedKeys {
+		g.ReservedKeys = append(g.ReservedKeys, k)
+	}
+	slices.Sort(g.ReservedKeys)
+	if c.keyFormat != nil {
+		g.KeyPattern = c.keyFormat.String()
+	}
+	return g
+}
+
+// GenerateABNF renders the grammar in ABNF (RFC 5234). Limits that ABNF
+// cannot express, such as the total length, are added as comments.
+func (g Grammar) GenerateABNF() string {
+	var b strings.Builder
+	rule := func(name, def string) {
+		fmt.Fprintf(&b, "%-11s = %s\n", name, def)
+	}
+	sep := fmt.Sprintf("%q", string(g.SeparatorRune))
+
+	urn := "scheme " + sep + " entity " + sep + " id *( " + sep + " key " + sep + " value )"
+	if g.TrailingFlag {
+		urn += " [ " + sep + " key ]"
+	}
+	rule("urn", urn)
+	quoted := make([]string, len(g.Schemes))
+	for i, s := range g.Schemes {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	rule("scheme", strings.Join(quoted, " / "))
+	if g.EntityMaxLength > 0 {
+		rule("entity", `label *( "." label )`)
+		rule("label", `alnum *( alnum / "-" )`)
+	} else {
+		rule("entity", `alnum 1*31( alnum / "-" )`)
+	}
+	rule("id", "1*char")
+	rule("key", "1*char")
+	if g.QuotedValues {
+		rule("value", "1*char / DQUOTE 1*( char / "+sep+" ) DQUOTE")
+	} else {
+		rule("value", "1*char")
+	}
+	rule("char", "unreserved / pct-encoded")
+
+	var unreserved []string
+	for r := rune(0x21); r < 0x7f; r++ {
+		if !slices.Contains(g.EscapedRunes, r) && !isAlnum(r) {
+			unreserved = append(unreserved, fmt.Sprintf("%q", string(r)))
+		}
+	}
+	rule("unreserved", "ALPHA / DIGIT / "+strings.Join(unreserved, " / "))
+	rule("pct-encoded", `"%" HEXDIG HEXDIG`)
+	rule("alnum", "ALPHA / DIGIT")
+
+	fmt.Fprintf(&b, "; scheme matches case-insensitively\n")
+	if g.EntityMaxLength > 0 {
+		fmt.Fprintf(&b, "; entity is 2-%d characters\n", g.EntityMaxLength)
+	}
+	fmt.Fprintf(&b, "; at most %d characters in the \"urn:\" form\n", g.MaxLength)
+	fmt.Fprintf(&b, "; at most %d segments, scheme included\n", g.MaxSegments)
+	fmt.Fprintf(&b, "; reserved keys: %s\n", strings.Join(g.ReservedKeys, ", "))
+	if g.KeyPattern != "" {
+		fmt.Fprintf(&b, "; keys match %s\n", g.KeyPattern)
+	}
+	return b.String()
+}
+
+func isAlnum(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+}
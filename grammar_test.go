@@ -0,0 +1,78 @@
+package urn
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestGrammarABNF(t *testing.T) {
+	SetReservedKeys("Secret", "token")
+	SetKeyFormat(DefaultKeyFormat)
+	t.Cleanup(func() {
+		SetReservedKeys()
+		SetKeyFormat(nil)
+	})
+	custom := NewProcessor(
+		EntityAllowDots(),
+		WithSchemeAliases(map[string]string{"u": "urn", "x": "other"}),
+		WithMaxSegments(16),
+		WithQuoting(),
+		TrailingKeyAsFlag(),
+	)
+	tests := []struct {
+		golden string
+		g      Grammar
+	}{
+		{"grammar_default.abnf", NewProcessor().Grammar()},
+		{"grammar_custom.abnf", custom.Grammar()},
+	}
+	for _, tt := range tests {
+		got := tt.g.GenerateABNF()
+		golden := filepath.Join("testdata", tt.golden)
+		if *update {
+			if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != string(want) {
+			t.Errorf("ABNF differs from %s:\n%s", golden, got)
+		}
+	}
+}
+
+func TestGrammarFields(t *testing.T) {
+	g := DefaultGrammar()
+	if g.MaxLength != MaxURNLength || g.MaxSegments != DefaultMaxSegments || g.SeparatorRune != ':' {
+		t.Errorf("limits: %+v", g)
+	}
+	if !slices.Equal(g.Schemes, []string{"urn"}) || len(g.ReservedKeys) != 0 || g.KeyPattern != "" {
+		t.Errorf("defaults: %+v", g)
+	}
+	// EntityPattern is the pattern ParseStrict enforces.
+	re := regexp.MustCompile(g.EntityPattern)
+	for _, e := range []string{"orders", "a", "shop.order", "-x"} {
+		_, err := ParseStrict("urn:" + e + ":1")
+		if re.MatchString(e) != (err == nil) {
+			t.Errorf("entity %q: pattern %v, ParseStrict %v", e, re.MatchString(e), err)
+		}
+	}
+	// EscapedRunes agrees with EscapeSegment.
+	for r := rune(0x21); r < 0x7f; r++ {
+		escaped := EscapeSegment(string(r)) != string(r)
+		if slices.Contains(g.EscapedRunes, r) != escaped {
+			t.Errorf("rune %q: listed %v, escaped %v", r, !escaped, escaped)
+		}
+	}
+
+	dotted := NewProcessor(EntityAllowDots()).Grammar()
+	if dotted.EntityPattern == g.EntityPattern || dotted.EntityMaxLength != 32 {
+		t.Errorf("EntityAllowDots not reflected: %+v", dotted)
+	}
+}
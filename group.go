@@ -0,0 +1,72 @@
+package urn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BatchError reports a failure for a single input of a batch operation.
+type BatchError struct {
+	Index int
+	Input string
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("urns[%d] %q: %s", e.Index, e.Input, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// GroupBy buckets URN strings by the key computed from their parsed form.
+// Inputs that fail to parse are skipped and reported as *BatchError values
+// carrying their index; the rest of the batch is still grouped.
+func GroupBy(urns []string, keyFn func(*URN) string) (map[string][]string, []error) {
+	groups := make(map[string][]string)
+	var errs []error
+	for i, s := range urns {
+		u, err := Parse(s)
+		if err != nil {
+			errs = append(errs, &BatchError{Index: i, Input: s, Err: err})
+			continue
+		}
+		k := keyFn(u)
+		groups[k] = append(groups[k], s)
+	}
+	return groups, errs
+}
+
+// GroupByEntity buckets URN strings by entity.
+func GroupByEntity(urns []string) (map[string][]string, []error) {
+	return GroupBy(urns, func(u *URN) string { return u.Entity })
+}
+
+// IndexByID maps each ID to the URN string carrying it. It is meant for
+// results holding a single entity: an ID seen twice is reported as an error,
+// as is any input that fails to parse. All problems are collected and
+// returned together; the index still holds every URN that could be placed.
+func IndexByID(urns []string) (map[string]string, error) {
+	index := make(map[string]string, len(urns))
+	entities := make(map[string]string, len(urns))
+	var errs []error
+	for i, s := range urns {
+		u, err := Parse(s)
+		if err != nil {
+			errs = append(errs, &BatchError{Index: i, Input: s, Err: err})
+			continue
+		}
+		if prev, ok := entities[u.ID]; ok {
+			errs = append(errs, &BatchError{
+				Index: i,
+				Input: s,
+				Err:   fmt.Errorf("duplicate ID %q (entity %s, already indexed for entity %s)", u.ID, u.Entity, prev),
+			})
+			continue
+		}
+		entities[u.ID] = u.Entity
+		index[u.ID] = s
+	}
+	return index, errors.Join(errs...)
+}
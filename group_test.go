@@ -0,0 +1,69 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGroupByEntity(t *testing.T) {
+	groups, errs := GroupByEntity([]string{
+		"urn:orders:1",
+		"urn:customers:7",
+		"bogus",
+		"urn:orders:2:vendor:amazon",
+	})
+	if len(groups["orders"]) != 2 || len(groups["customers"]) != 1 {
+		t.Errorf("unexpected groups: %v", groups)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	var be *BatchError
+	if !errors.As(errs[0], &be) || be.Index != 2 || be.Input != "bogus" {
+		t.Errorf("unexpected error: %v", errs[0])
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	groups, errs := GroupBy([]string{
+		"urn:orders:1:vendor:amazon",
+		"urn:orders:2:vendor:ebay",
+		"urn:orders:3:vendor:amazon",
+		"urn:orders:4",
+	}, func(u *URN) string { return u.Attributes()["vendor"] })
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(groups["amazon"]) != 2 || len(groups["ebay"]) != 1 || len(groups[""]) != 1 {
+		t.Errorf("unexpected groups: %v", groups)
+	}
+}
+
+func TestIndexByID(t *testing.T) {
+	index, err := IndexByID([]string{"urn:orders:1", "urn:orders:2:vendor:amazon"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index["2"] != "urn:orders:2:vendor:amazon" {
+		t.Errorf("unexpected index: %v", index)
+	}
+}
+
+func TestIndexByIDDuplicatesAndInvalid(t *testing.T) {
+	index, err := IndexByID([]string{
+		"urn:orders:1",
+		"urn:orders:1:vendor:amazon",
+		"urn:orders",
+		"urn:orders:2",
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), `duplicate ID "1"`) || !strings.Contains(err.Error(), "urns[2]") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if index["1"] != "urn:orders:1" || index["2"] != "urn:orders:2" {
+		t.Errorf("unexpected index: %v", index)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !urndebug
+
+package urn
+
+// debugGuards enables recovery of panics in the entry points that read
+// untrusted input. It is set by the urndebug build tag; see guard_debug.go.
+const debugGuards = false
+
+func recoverPanic(op string, err *error) {}
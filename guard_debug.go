@@ -0,0 +1,33 @@
+//go:build urndebug
+
+package urn
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// debugGuards enables recovery of panics in the entry points that read
+// untrusted input. Building with -tags urndebug turns a panic there into
+// an error carrying the stack, so a fuzzing or staging run reports the
+// offending input instead of crashing.
+const debugGuards = true
+
+// panicError is the error a recovered panic becomes.
+type panicError struct {
+	op    string
+	value any
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v\n%s", e.op, e.value, e.stack)
+}
+
+// recoverPanic, deferred by op with a pointer to its error result,
+// replaces a panic with a *panicError.
+func recoverPanic(op string, err *error) {
+	if r := recover(); r != nil {
+		*err = &panicError{op: op, value: r, stack: debug.Stack()}
+	}
+}
@@ -0,0 +1,37 @@
+//go:build urndebug
+
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecoverPanic(t *testing.T) {
+	f := func() (err error) {
+		defer recoverPanic("Parse", &err)
+		var s []int
+		_ = s[3]
+		return nil
+	}
+	err := f()
+	var pe *panicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("err = %v, want *panicError", err)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "Parse panicked: runtime error: index out of range") || !strings.Contains(msg, "TestRecoverPanic") {
+		t.Errorf("err = %q, want the panic value and stack", msg)
+	}
+}
+
+func TestRecoverPanicPassesErrors(t *testing.T) {
+	want := errors.New("boom")
+	f := func() (err error) {
+		defer recoverPanic("Parse", &err)
+		return want
+	}
+	if err := f(); err != want {
+		t.Errorf("err = %v, want %v", err, want)
+	}
+}
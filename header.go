@@ -0,0 +1,89 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultMaxHeaderSize is the size limit HeaderValue applies unless
+// WithMaxHeaderSize sets another. It matches the smallest header line
+// limit common among servers and proxies.
+const DefaultMaxHeaderSize = 8 << 10
+
+// ErrHeaderTooLarge is wrapped by the *BatchError HeaderValue returns for
+// the first URN that does not fit the size limit.
+var ErrHeaderTooLarge = errors.New("header value too large")
+
+// HeaderOption configures HeaderValue.
+type HeaderOption func(*headerConfig)
+
+type headerConfig struct {
+	maxSize int
+}
+
+// WithMaxHeaderSize sets the maximum length in bytes of the header value.
+func WithMaxHeaderSize(n int) HeaderOption {
+	return func(c *headerConfig) {
+		c.maxSize = n
+	}
+}
+
+// HeaderValue joins urns into an HTTP header value: a list separated by
+// a comma and a space, as RFC 7230 defines for list-valued fields. Each
+// URN is checked with ParseStrict and emitted in composed form, which
+// percent-encodes commas and whitespace, so items cannot run into each
+// other. Failures are reported as a *BatchError for the first offending
+// URN; one that pushes the value over the size limit wraps
+// ErrHeaderTooLarge. An empty list yields "".
+func HeaderValue(urns []string, opts ...HeaderOption) (string, error) {
+	c := headerConfig{maxSize: DefaultMaxHeaderSize}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	var b strings.Builder
+	for i, s := range urns {
+		u, err := ParseStrict(s)
+		if err != nil {
+			return "", &BatchError{Index: i, Input: s, Err: err}
+		}
+		item, err := u.StringE()
+		if err != nil {
+			return "", &BatchError{Index: i, Input: s, Err: err}
+		}
+		n := b.Len() + len(item)
+		if i > 0 {
+			n += len(", ")
+		}
+		if n > c.maxSize {
+			return "", &BatchError{Index: i, Input: s, Err: ErrHeaderTooLarge}
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(item)
+	}
+	return b.String(), nil
+}
+
+// ParseHeaderValue parses a comma-separated list of URNs from an HTTP
+// header value. Optional whitespace around items and empty list elements
+// are ignored, so "", " , " and "a,b" are all accepted. Items that fail to
+// parse are reported as a *BatchError whose Index counts the non-empty
+// items before it.
+func ParseHeaderValue(s string) ([]*URN, error) {
+	var urns []*URN
+	i := 0
+	for item := range strings.SplitSeq(s, ",") {
+		item = strings.Trim(item, " \t")
+		if item == "" {
+			continue
+		}
+		u, err := Parse(item)
+		if err != nil {
+			return nil, &BatchError{Index: i, Input: item, Err: err}
+		}
+		urns = append(urns, u)
+		i++
+	}
+	return urns, nil
+}
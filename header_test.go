@@ -0,0 +1,85 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHeaderValue(t *testing.T) {
+	got, err := HeaderValue([]string{"urn:user:1", "urn:user:a%2Cb:k:x y", "URN:Order:7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "urn:user:1, urn:user:a%2Cb:k:x%20y, urn:Order:7"; got != want {
+		t.Errorf("HeaderValue = %q, want %q", got, want)
+	}
+	urns, err := ParseHeaderValue(got)
+	if err != nil || len(urns) != 3 || urns[1].ID != "a,b" {
+		t.Errorf("round trip: %v, %v", urns, err)
+	}
+
+	if got, err := HeaderValue(nil); got != "" || err != nil {
+		t.Errorf("empty list: %q, %v", got, err)
+	}
+
+	_, err = HeaderValue([]string{"urn:user:1", "bad"})
+	var be *BatchError
+	if !errors.As(err, &be) || be.Index != 1 {
+		t.Errorf("invalid URN: %v", err)
+	}
+}
+
+func TestHeaderValueSizeLimit(t *testing.T) {
+	urns := []string{"urn:user:1", "urn:user:2", "urn:user:3"}
+	// "urn:user:1, urn:user:2" is exactly 22 bytes.
+	_, err := HeaderValue(urns, WithMaxHeaderSize(22))
+	var be *BatchError
+	if !errors.Is(err, ErrHeaderTooLarge) || !errors.As(err, &be) || be.Index != 2 || be.Input != "urn:user:3" {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := HeaderValue(urns, WithMaxHeaderSize(34)); err != nil {
+		t.Errorf("exact fit: %v", err)
+	}
+
+	many := make([]string, 1000)
+	for i := range many {
+		many[i] = "urn:user:" + strings.Repeat("x", 20)
+	}
+	if _, err := HeaderValue(many); !errors.Is(err, ErrHeaderTooLarge) {
+		t.Errorf("default limit not applied: %v", err)
+	}
+}
+
+func TestParseHeaderValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{" , ,", nil},
+		{"urn:user:1", []string{"1"}},
+		{"urn:user:1,urn:user:2", []string{"1", "2"}},
+		{"urn:user:1 ,\turn:user:2 , ", []string{"1", "2"}},
+		{",urn:user:1,,urn:user:2", []string{"1", "2"}},
+	}
+	for _, tt := range tests {
+		urns, err := ParseHeaderValue(tt.in)
+		if err != nil || len(urns) != len(tt.want) {
+			t.Errorf("ParseHeaderValue(%q) = %v, %v", tt.in, urns, err)
+			continue
+		}
+		for i, u := range urns {
+			if u.ID != tt.want[i] {
+				t.Errorf("ParseHeaderValue(%q)[%d].ID = %q", tt.in, i, u.ID)
+			}
+		}
+	}
+
+	_, err := ParseHeaderValue("urn:user:1, , nope")
+	var be *BatchError
+	if !errors.As(err, &be) || be.Index != 1 || be.Input != "nope" {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package urn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// urnContextKey is the context key under which ContextWithURN stores a
+// *URN. It is unexported so only URNFromContext can read the value.
+type urnContextKey struct{}
+
+// URNFromRequest reads the request parameter param with extract and parses
+// it with ParseStrict. A nil extract reads the path wildcard of that name
+// via (*http.Request).PathValue. A missing or empty parameter is a
+// KindEmpty error.
+func URNFromRequest(r *http.Request, param string, extract func(*http.Request, string) string) (*URN, error) {
+	if extract == nil {
+		extract = (*http.Request).PathValue
+	}
+	s := extract(r, param)
+	if s == "" {
+		return nil, &InvalidURNError{
+			Kind:    KindEmpty,
+			Reason:  ReasonMissingParameter,
+			Segment: param,
+		}
+	}
+	return ParseStrict(s)
+}
+
+// ContextWithURN returns a copy of ctx carrying u, as RequireValidURN
+// stores it; read it back with URNFromContext.
+func ContextWithURN(ctx context.Context, u *URN) context.Context {
+	return context.WithValue(ctx, urnContextKey{}, u)
+}
+
+// URNFromContext returns the URN stored by RequireValidURN or
+// ContextWithURN.
+func URNFromContext(ctx context.Context) (*URN, bool) {
+	u, ok := ctx.Value(urnContextKey{}).(*URN)
+	return u, ok
+}
+
+// httpError is the JSON body RequireValidURN writes on failure.
+type httpError struct {
+	Error   string `json:"error"`
+	Kind    string `json:"kind"`
+	Offset  int    `json:"offset"`
+	Segment string `json:"segment,omitempty"`
+}
+
+// RequireValidURN parses the path wildcard param with URNFromRequest and
+// calls next with the URN in the request context (see URNFromContext). On
+// failure it responds 400 Bad Request with a JSON body carrying the error
+// message, kind, offset and segment.
+func RequireValidURN(param string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, err := URNFromRequest(r, param, nil)
+		if err != nil {
+			body := httpError{Error: err.Error(), Kind: KindUnknown.String()}
+			var e *InvalidURNError
+			if errors.As(err, &e) {
+				body.Kind, body.Offset, body.Segment = e.Kind.String(), e.Offset, e.Segment
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(body)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ContextWithURN(r.Context(), u)))
+	})
+}
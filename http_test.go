@@ -0,0 +1,83 @@
+package urn
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireValidURN(t *testing.T) {
+	mux := http.NewServeMux()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, found := URNFromContext(r.Context())
+		if !found {
+			t.Error("URN missing from context")
+			return
+		}
+		w.Write([]byte(u.Entity + "/" + u.ID))
+	})
+	mux.Handle("/items/{urn}", RequireValidURN("urn", ok))
+	mux.Handle("/other/{id}", RequireValidURN("urn", ok))
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+		kind   string
+	}{
+		{"/items/urn:orders:42:vendor:amazon", http.StatusOK, "orders/42", ""},
+		{"/items/urn:orders", http.StatusBadRequest, "", "missing component"},
+		{"/items/nope", http.StatusBadRequest, "", "scheme"},
+		{"/other/urn:orders:1", http.StatusBadRequest, "", "empty"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.path, rec.Code, tt.status)
+			continue
+		}
+		if tt.status == http.StatusOK {
+			if rec.Body.String() != tt.body {
+				t.Errorf("%s: body %q", tt.path, rec.Body)
+			}
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s: Content-Type %q", tt.path, ct)
+		}
+		var body struct {
+			Error  string `json:"error"`
+			Kind   string `json:"kind"`
+			Offset int    `json:"offset"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Kind != tt.kind || body.Error == "" {
+			t.Errorf("%s: body %s (%v)", tt.path, rec.Body, err)
+		}
+	}
+}
+
+func TestURNFromRequestExtract(t *testing.T) {
+	query := func(r *http.Request, name string) string { return r.URL.Query().Get(name) }
+	r := httptest.NewRequest("GET", "/?subject=urn:orders:7", nil)
+	u, err := URNFromRequest(r, "subject", query)
+	if err != nil || u.ID != "7" {
+		t.Errorf("got %v, %v", u, err)
+	}
+	if _, err := URNFromRequest(r, "object", query); err == nil {
+		t.Error("missing parameter accepted")
+	}
+}
+
+func TestContextWithURN(t *testing.T) {
+	if _, ok := URNFromContext(context.Background()); ok {
+		t.Error("found a URN in an empty context")
+	}
+	u, _ := Parse("urn:orders:1")
+	got, ok := URNFromContext(ContextWithURN(context.Background(), u))
+	if !ok || got != u {
+		t.Errorf("URNFromContext = %v, %v", got, ok)
+	}
+}
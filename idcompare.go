@@ -0,0 +1,57 @@
+package urn
+
+import (
+	"maps"
+	"strings"
+)
+
+// IDComparator reports whether two IDs of the same entity name the same
+// resource.
+type IDComparator func(a, b string) bool
+
+// RegisterIDComparator makes Equal, Equivalent, SameResource, Set and
+// DuplicateDetector compare the IDs of entity (matched ASCII
+// case-insensitively) with cmp instead of exactly. The URNs themselves keep
+// their original IDs. Registering nil restores exact comparison. Identity,
+// and so ShardFor and ConsistentSharder, key on the ID itself and ignore
+// the comparator.
+//
+// Set and DuplicateDetector compare an ID against every recorded ID of the
+// same entity, so lookups for such entities are linear in their number.
+func RegisterIDComparator(entity string, cmp IDComparator) {
+	key := asciiToLower(entity)
+	updateConfig(func(c *config) {
+		m := maps.Clone(c.idComparators)
+		if m == nil {
+			m = make(map[string]IDComparator)
+		}
+		if cmp == nil {
+			delete(m, key)
+		} else {
+			m[key] = cmp
+		}
+		c.idComparators = m
+	})
+}
+
+// FoldEmailDomain is an IDComparator for e-mail addresses: the local part
+// compares exactly and the domain ASCII case-insensitively.
+func FoldEmailDomain(a, b string) bool {
+	i, j := strings.LastIndexByte(a, '@'), strings.LastIndexByte(b, '@')
+	if i < 0 || j < 0 {
+		return a == b
+	}
+	return a[:i] == b[:j] && asciiEqualFold(a[i:], b[j:])
+}
+
+func (c *config) idComparator(entity string) IDComparator {
+	return c.idComparators[asciiToLower(entity)]
+}
+
+// sameID compares two IDs of entity under the registered comparator.
+func (c *config) sameID(entity, a, b string) bool {
+	if cmp := c.idComparator(entity); cmp != nil {
+		return cmp(a, b)
+	}
+	return a == b
+}
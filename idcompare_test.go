@@ -0,0 +1,88 @@
+package urn
+
+import "testing"
+
+func TestIDComparator(t *testing.T) {
+	RegisterIDComparator("email", FoldEmailDomain)
+	t.Cleanup(func() { RegisterIDComparator("email", nil) })
+
+	parse := func(s string) *URN {
+		u, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+	a := parse("urn:email:User@EXAMPLE.com")
+	b := parse("urn:email:User@example.com")
+	c := parse("urn:email:user@example.com")
+
+	if !a.Equal(b) || a.Equal(c) {
+		t.Error("Equal")
+	}
+	if !a.Equivalent(parse("urn:EMAIL:User@example.COM")) || a.Equivalent(c) {
+		t.Error("Equivalent")
+	}
+	if a.Equivalent(parse("urn:email:User@example.com:k:v")) {
+		t.Error("Equivalent ignored attributes")
+	}
+	if same, _ := SameResource("urn:email:User@EXAMPLE.com:k:v", "urn:Email:User@example.com"); !same {
+		t.Error("SameResource")
+	}
+
+	var s Set
+	for _, u := range []*URN{a, b, c} {
+		s.Add(u)
+	}
+	if s.Len() != 2 || !s.Contains(parse("urn:email:User@Example.Com")) {
+		t.Errorf("Set = %v", s.Canonical())
+	}
+	if got := s.Canonical()[0]; got != "urn:email:User@EXAMPLE.com" {
+		t.Errorf("first member's casing not preserved: %s", got)
+	}
+	if !s.Remove(b) || s.Len() != 1 || s.Contains(a) {
+		t.Errorf("Remove via equivalent ID: %v", s.Canonical())
+	}
+
+	for _, d := range []*DuplicateDetector{NewDuplicateDetector(), NewHashedDuplicateDetector()} {
+		for i, in := range []string{"urn:email:User@EXAMPLE.com", "urn:email:User@example.com", "urn:email:user@example.com"} {
+			dup, err := d.Seen(in)
+			if err != nil || dup != (i == 1) {
+				t.Errorf("Seen(%s) = %v, %v", in, dup, err)
+			}
+		}
+		if d.Count() != 2 {
+			t.Errorf("Count = %d", d.Count())
+		}
+	}
+
+	// Identity and sharding key on the ID itself and ignore comparators.
+	ia, _ := Identity("urn:email:User@EXAMPLE.com")
+	ib, _ := Identity("urn:email:User@example.com")
+	if ia == ib {
+		t.Errorf("Identity applied the comparator: %s", ia)
+	}
+
+	// Other entities keep exact comparison.
+	if parse("urn:users:Bob@X.com").Equal(parse("urn:users:Bob@x.com")) {
+		t.Error("comparator applied to another entity")
+	}
+}
+
+func TestFoldEmailDomain(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"a@B.com", "a@b.COM", true},
+		{"A@b.com", "a@b.com", false},
+		{"a@b@C.com", "a@b@c.com", true},
+		{"nodomain", "nodomain", true},
+		{"nodomain", "NODOMAIN", false},
+	}
+	for _, tt := range tests {
+		if got := FoldEmailDomain(tt.a, tt.b); got != tt.want {
+			t.Errorf("FoldEmailDomain(%q, %q) = %v", tt.a, tt.b, got)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package urn
+
+// SameResourceAs reports whether both URNs identify the same resource:
+// entities compare ASCII case-insensitively, IDs exactly (after decoding)
+// or with the registered IDComparator, and attributes are ignored.
+func (u *URN) SameResourceAs(other *URN) bool {
+	return asciiEqualFold(u.Entity, other.Entity) && loadConfig().sameID(u.Entity, u.ID, other.ID)
+}
+
+// SameResource reports whether two URN strings identify the same resource.
+func SameResource(a, b string) (bool, error) {
+	ua, err := Parse(a)
+	if err != nil {
+		return false, err
+	}
+	ub, err := Parse(b)
+	if err != nil {
+		return false, err
+	}
+	return ua.SameResourceAs(ub), nil
+}
+
+// Identity returns the canonical "urn:entity:id" form of a URN, with the
+// entity lowercased and attributes dropped, for use as a dedup key. It
+// keeps the ID exactly as decoded and ignores any IDComparator, which can
+// only compare IDs, not map them to a key: under a comparator, URNs that
+// SameResource reports equal may have different identities. Use
+// DuplicateDetector or Set, which apply comparators, to deduplicate them.
+func Identity(urnStr string) (string, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return "", err
+	}
+	return compose(asciiToLower(u.Entity), u.ID, nil)
+}
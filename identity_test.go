@@ -0,0 +1,69 @@
+package urn
+
+import "testing"
+
+func TestSameResource(t *testing.T) {
+	cases := []struct {
+		a, b string
+		same bool
+	}{
+		{"urn:orders:1:status:pending", "urn:orders:1:status:shipped", true},
+		{"urn:orders:1:a:1:b:2", "urn:orders:1:b:2:a:1", true},
+		{"urn:orders:a%2Fb", "urn:orders:a%2fb", true},
+		{"URN:Orders:1", "urn:orders:1", true},
+		{"urn:orders:abc", "urn:orders:ABC", false},
+		{"urn:orders:1", "urn:customers:1", false},
+	}
+	for _, c := range cases {
+		same, err := SameResource(c.a, c.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if same != c.same {
+			t.Errorf("SameResource(%q, %q) = %v, want %v", c.a, c.b, same, c.same)
+		}
+	}
+	if _, err := SameResource("urn:orders:1", "bogus"); err == nil {
+		t.Error("expected error for invalid URN")
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	id, err := Identity("URN:Orders:a%2fb:status:pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "urn:orders:a%2Fb" {
+		t.Errorf("unexpected identity: %s", id)
+	}
+}
+
+func TestSameResourceAgreesWithIdentity(t *testing.T) {
+	// U+212A KELVIN SIGN folds to "k" under Unicode rules but not ASCII ones.
+	pairs := [][2]string{
+		{"urn:\u212Aab:1", "urn:kab:1"},
+		{"urn:KAB:1", "urn:kab:1"},
+		{"urn:\u017Fab:1", "urn:sab:1"},
+	}
+	for _, p := range pairs {
+		same, err := SameResource(p[0], p[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		ia, _ := Identity(p[0])
+		ib, _ := Identity(p[1])
+		if same != (ia == ib) {
+			t.Errorf("SameResource(%q, %q) = %v but identities %q and %q", p[0], p[1], same, ia, ib)
+		}
+	}
+	if !EntityIs("KAB")(&URN{Entity: "kab"}) || EntityIs("\u212Aab")(&URN{Entity: "kab"}) {
+		t.Error("EntityIs does not fold ASCII only")
+	}
+	p := NewProcessor(CaseInsensitiveKeys())
+	if _, found, _ := p.Value("urn:ab:1:\u212A:v", "k"); found {
+		t.Error("CaseInsensitiveKeys folded a non-ASCII key")
+	}
+	if v, _, _ := p.Value("urn:ab:1:K:v", "k"); v != "v" {
+		t.Error("CaseInsensitiveKeys did not fold an ASCII key")
+	}
+}
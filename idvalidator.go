@@ -0,0 +1,119 @@
+package urn
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// IDValidator checks the ID of a URN for a particular entity.
+type IDValidator func(id string) error
+
+var objectIDRegex = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+
+// RegisterIDValidator makes ParseStrict, ValidateString and IsValid run v
+// against the ID of every URN whose entity matches, compared ASCII
+// case-insensitively. Registering nil removes the validator. URNs of
+// unregistered entities accept any ID.
+func RegisterIDValidator(entity string, v IDValidator) {
+	setIDValidator(entity, v)
+}
+
+// ObjectIDValidator accepts 24-character hexadecimal MongoDB ObjectIDs.
+func ObjectIDValidator(id string) error {
+	if !objectIDRegex.MatchString(id) {
+		return fmt.Errorf("not an ObjectID")
+	}
+	return nil
+}
+
+// UUIDValidator accepts IDs in the canonical 36-character UUID form.
+func UUIDValidator(id string) error {
+	if len(id) != 36 {
+		return fmt.Errorf("not a UUID")
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("not a UUID")
+	}
+	return nil
+}
+
+// UUIDConstraints restricts the UUIDs UUIDValidatorWith accepts beyond
+// the canonical 36-character form. The zero value adds no constraints.
+type UUIDConstraints struct {
+	// AllowedVersions lists the accepted UUID versions; empty allows any.
+	AllowedVersions []int
+	// RequireLowercase rejects uppercase hex digits.
+	RequireLowercase bool
+	// RejectNil rejects the all-zero UUID.
+	RejectNil bool
+}
+
+// Errors wrapped by the errors of UUIDValidatorWith, naming the failed
+// constraint.
+var (
+	ErrUUIDVersion   = errors.New("UUID version not allowed")
+	ErrUUIDUppercase = errors.New("UUID contains uppercase hex digits")
+	ErrUUIDNil       = errors.New("nil UUID")
+)
+
+// UUIDValidatorWith returns an IDValidator accepting canonical UUIDs that
+// satisfy c, for use with RegisterIDValidator.
+func UUIDValidatorWith(c UUIDConstraints) IDValidator {
+	versions := slices.Clone(c.AllowedVersions)
+	return func(id string) error {
+		if err := UUIDValidator(id); err != nil {
+			return err
+		}
+		u := uuid.MustParse(id)
+		if c.RejectNil && u == uuid.Nil {
+			return ErrUUIDNil
+		}
+		if c.RequireLowercase && strings.ToLower(id) != id {
+			return ErrUUIDUppercase
+		}
+		if len(versions) > 0 && !slices.Contains(versions, int(u.Version())) {
+			return fmt.Errorf("%w: version %d, allowed %v", ErrUUIDVersion, u.Version(), versions)
+		}
+		return nil
+	}
+}
+
+// ValidateUUIDID checks that the ID of urnStr is a UUID satisfying c. A
+// failed constraint is reported as a KindInvalidID error wrapping
+// ErrUUIDVersion, ErrUUIDUppercase or ErrUUIDNil.
+func ValidateUUIDID(urnStr string, c UUIDConstraints) error {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return err
+	}
+	if err := UUIDValidatorWith(c)(u.ID); err != nil {
+		return invalidIDError(urnStr, u, err)
+	}
+	return nil
+}
+
+// NumericValidator accepts IDs consisting only of ASCII digits.
+func NumericValidator(id string) error {
+	for i := 0; i < len(id); i++ {
+		if id[i] < '0' || id[i] > '9' {
+			return fmt.Errorf("not numeric")
+		}
+	}
+	return nil
+}
+
+// RegexpValidator accepts IDs matching re. Anchor the expression to match
+// the whole ID.
+func RegexpValidator(re *regexp.Regexp) IDValidator {
+	return func(id string) error {
+		if !re.MatchString(id) {
+			return fmt.Errorf("does not match %s", re)
+		}
+		return nil
+	}
+}
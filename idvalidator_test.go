@@ -0,0 +1,108 @@
+package urn
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestRegisterIDValidator(t *testing.T) {
+	RegisterIDValidator("product", ObjectIDValidator)
+	t.Cleanup(func() { RegisterIDValidator("product", nil) })
+
+	if _, err := ParseStrict("urn:product:<nil>"); err == nil {
+		t.Error("expected error for bad product ID")
+	}
+	if IsValid("urn:Product:<nil>") {
+		t.Error("expected entity match to be case-insensitive")
+	}
+	if _, err := ParseStrict("urn:product:65b2713b1267994147953b27"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := Parse("urn:product:<nil>"); err != nil {
+		t.Errorf("lenient Parse should not run ID validators: %v", err)
+	}
+}
+
+func TestUnregisteredEntityAcceptsAnyID(t *testing.T) {
+	if _, err := ParseStrict("urn:widget:<nil>"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBuiltinIDValidators(t *testing.T) {
+	cases := []struct {
+		name string
+		v    IDValidator
+		good string
+		bad  string
+	}{
+		{"objectid", ObjectIDValidator, "65b2713b1267994147953b27", "65b2713b12679941479"},
+		{"uuid", UUIDValidator, "6e8bc430-9c3a-11d9-9669-0800200c9a66", "6e8bc4309c3a11d996690800200c9a66"},
+		{"numeric", NumericValidator, "12345", "12a45"},
+		{"regexp", RegexpValidator(regexp.MustCompile(`^SKU-\d+$`)), "SKU-42", "sku-42"},
+	}
+	for _, c := range cases {
+		if err := c.v(c.good); err != nil {
+			t.Errorf("%s: unexpected error for %q: %v", c.name, c.good, err)
+		}
+		if err := c.v(c.bad); err == nil {
+			t.Errorf("%s: expected error for %q", c.name, c.bad)
+		}
+	}
+}
+
+func TestValidateUUIDID(t *testing.T) {
+	const (
+		v4    = "urn:orders:6f1c8e4a-2b3d-4c5e-9f60-718293a4b5c6"
+		v7    = "urn:orders:01890a5d-ac96-774b-bcce-b302099a8057"
+		v1    = "urn:orders:6e8bc430-9c3a-11d9-9669-0800200c9a66"
+		upper = "urn:orders:6F1C8E4A-2B3D-4C5E-9F60-718293A4B5C6"
+		nilID = "urn:orders:00000000-0000-0000-0000-000000000000"
+	)
+	v4or7 := UUIDConstraints{AllowedVersions: []int{4, 7}}
+	tests := []struct {
+		in   string
+		c    UUIDConstraints
+		want error
+	}{
+		{v4, v4or7, nil},
+		{v7, v4or7, nil},
+		{v1, v4or7, ErrUUIDVersion},
+		{upper, UUIDConstraints{}, nil},
+		{upper, UUIDConstraints{RequireLowercase: true}, ErrUUIDUppercase},
+		{nilID, UUIDConstraints{}, nil},
+		{nilID, UUIDConstraints{RejectNil: true}, ErrUUIDNil},
+	}
+	for _, tt := range tests {
+		err := ValidateUUIDID(tt.in, tt.c)
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("ValidateUUIDID(%s, %+v) = %v", tt.in, tt.c, err)
+			}
+			continue
+		}
+		var e *InvalidURNError
+		if !errors.Is(err, tt.want) || !errors.As(err, &e) || e.Kind != KindInvalidID {
+			t.Errorf("ValidateUUIDID(%s, %+v) = %v, want %v", tt.in, tt.c, err, tt.want)
+		}
+	}
+	if err := ValidateUUIDID("urn:orders:123", UUIDConstraints{}); err == nil {
+		t.Error("non-UUID accepted")
+	}
+	if err := ValidateUUIDID("urn:orders", UUIDConstraints{}); err == nil {
+		t.Error("parse error not reported")
+	}
+}
+
+func TestUUIDValidatorWithRegistry(t *testing.T) {
+	RegisterIDValidator("sessions", UUIDValidatorWith(UUIDConstraints{AllowedVersions: []int{4}, RejectNil: true}))
+	t.Cleanup(func() { RegisterIDValidator("sessions", nil) })
+	if !IsValid("urn:sessions:6f1c8e4a-2b3d-4c5e-9f60-718293a4b5c6") {
+		t.Error("v4 rejected")
+	}
+	_, err := ParseStrict("urn:sessions:00000000-0000-0000-0000-000000000000")
+	if !errors.Is(err, ErrUUIDNil) {
+		t.Errorf("nil UUID: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package urn
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Interner parses URNs and deduplicates their entity and attribute key
+// strings against canonical instances shared by every URN it has
+// interned. IDs and attribute values can take unboundedly many distinct
+// values and would grow the table forever, so they are copied rather than
+// interned. An Interner is safe for concurrent use.
+type Interner struct {
+	mu      sync.RWMutex
+	strings map[string]string
+	saved   atomic.Int64
+}
+
+// InternerStats describes the effect of an Interner.
+type InternerStats struct {
+	// UniqueStrings is the number of canonical strings held.
+	UniqueStrings int
+	// SavedBytes estimates the string bytes that were not retained because
+	// a canonical instance was reused.
+	SavedBytes int64
+}
+
+// NewInterner creates an empty Interner.
+func NewInterner() *Interner {
+	return &Interner{strings: make(map[string]string)}
+}
+
+// Intern parses urnStr and returns a URN whose shared components point at
+// canonical string instances. The returned URN does not retain the input.
+func (in *Interner) Intern(urnStr string) (*URN, error) {
+	u, err := parse(urnStr, &options{discardRaw: true})
+	if err != nil {
+		return nil, err
+	}
+	u.Entity = in.intern(u.Entity)
+	// The ID and values are cloned so the URN never pins the caller's
+	// input buffer.
+	u.ID = string([]byte(u.ID))
+	for i := range u.attributes {
+		u.attributes[i].Key = in.intern(u.attributes[i].Key)
+		u.attributes[i].Value = string([]byte(u.attributes[i].Value))
+	}
+	return u, nil
+}
+
+func (in *Interner) intern(s string) string {
+	in.mu.RLock()
+	c, ok := in.strings[s]
+	in.mu.RUnlock()
+	if ok {
+		in.saved.Add(int64(len(s)))
+		return c
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if c, ok := in.strings[s]; ok {
+		in.saved.Add(int64(len(s)))
+		return c
+	}
+	// Copy so the canonical instance does not alias the parsed input.
+	c = string([]byte(s))
+	in.strings[c] = c
+	return c
+}
+
+// Stats returns the current interning statistics.
+func (in *Interner) Stats() InternerStats {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return InternerStats{UniqueStrings: len(in.strings), SavedBytes: in.saved.Load()}
+}
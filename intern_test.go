@@ -0,0 +1,104 @@
+package urn
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestInternerSharesStrings(t *testing.T) {
+	in := NewInterner()
+	a, err := in.Intern(fmt.Sprintf("urn:%s:1:%s:%s", "orders", "vendor", "amazon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := in.Intern(fmt.Sprintf("urn:%s:2:%s:%s", "orders", "vendor", "amazon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unsafe.StringData(a.Entity) != unsafe.StringData(b.Entity) {
+		t.Error("expected entities to share backing data")
+	}
+	if unsafe.StringData(a.attributes[0].Key) != unsafe.StringData(b.attributes[0].Key) {
+		t.Error("expected keys to share backing data")
+	}
+	if unsafe.StringData(a.attributes[0].Value) == unsafe.StringData(b.attributes[0].Value) {
+		t.Error("values should be copied, not interned")
+	}
+	if a.ID != "1" || b.ID != "2" || a.Raw() != "" {
+		t.Errorf("unexpected URNs: %+v %+v", a, b)
+	}
+
+	stats := in.Stats()
+	if stats.UniqueStrings != 2 {
+		t.Errorf("expected 2 unique strings, got %d", stats.UniqueStrings)
+	}
+	if stats.SavedBytes != int64(len("orders")+len("vendor")) {
+		t.Errorf("unexpected saved bytes: %d", stats.SavedBytes)
+	}
+}
+
+func TestInternerConcurrent(t *testing.T) {
+	in := NewInterner()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if _, err := in.Intern(fmt.Sprintf("urn:orders:%d:vendor:v%d", i, i%4)); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if got := in.Stats().UniqueStrings; got != 2 {
+		t.Errorf("expected 2 unique strings, got %d", got)
+	}
+}
+
+func TestInternerInvalid(t *testing.T) {
+	if _, err := NewInterner().Intern("bogus"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func BenchmarkInternerRetained(b *testing.B) {
+	inputs := make([]string, 1000)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("urn:orders:%d:vendor:amazon:status:shipped", i)
+	}
+	in := NewInterner()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		in.Intern(inputs[i%len(inputs)])
+	}
+}
+
+func BenchmarkParseRetained(b *testing.B) {
+	inputs := make([]string, 1000)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("urn:orders:%d:vendor:amazon:status:shipped", i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Parse(inputs[i%len(inputs)])
+	}
+}
+
+func TestInternerTableBoundedByKeys(t *testing.T) {
+	in := NewInterner()
+	for i := 0; i < 1000; i++ {
+		if _, err := in.Intern(fmt.Sprintf("urn:orders:%d:trace:%d", i, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := in.Stats().UniqueStrings; got != 2 {
+		t.Errorf("distinct values grew the table to %d strings", got)
+	}
+}
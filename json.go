@@ -0,0 +1,165 @@
+package urn
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// orderedJSON is the document form used by MarshalOrderedJSON.
+type orderedJSON struct {
+	Entity     string     `json:"entity"`
+	ID         string     `json:"id"`
+	Attributes [][]string `json:"attributes"`
+}
+
+// MarshalOrderedJSON encodes the URN as
+// {"entity":…,"id":…,"attributes":[["key","value"],…]}, keeping attribute
+// order and duplicate keys. Attributes left unparsed under
+// MaxAttributesParsed are decoded and included. A component that is not
+// valid UTF-8, as accepted under AllowBinaryComponents, is an error
+// wrapping ErrInvalidUTF8.
+func (u *URN) MarshalOrderedJSON() ([]byte, error) {
+	if err := u.checkJSONText(); err != nil {
+		return nil, err
+	}
+	pairs, err := u.AllAttributePairs()
+	if err != nil {
+		return nil, err
+	}
+	doc := orderedJSON{Entity: u.Entity, ID: u.ID, Attributes: make([][]string, len(pairs))}
+	for i, p := range pairs {
+		doc.Attributes[i] = []string{p.Key, p.Value}
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalOrderedJSON decodes a document produced by MarshalOrderedJSON.
+// Unknown fields, attribute entries that are not two-element arrays and
+// components Compose would reject are errors.
+func UnmarshalOrderedJSON(data []byte) (*URN, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var doc orderedJSON
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("Invalid ordered JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("Invalid ordered JSON: trailing data")
+	}
+	attrs := make([]Attribute, 0, len(doc.Attributes))
+	for i, pair := range doc.Attributes {
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("Invalid ordered JSON: attribute %d has %d elements, want 2", i, len(pair))
+		}
+		attrs = append(attrs, Attribute{Key: pair[0], Value: pair[1]})
+	}
+	if err := validateComponents(doc.Entity, doc.ID, attrs); err != nil {
+		return nil, err
+	}
+	u := &URN{Entity: doc.Entity, ID: doc.ID}
+	if len(attrs) > 0 {
+		u.attributes = attrs
+	}
+	return u, nil
+}
+
+// objectJSON is the document form used by MarshalJSONOrderedObject.
+type objectJSON struct {
+	Entity     string          `json:"entity"`
+	ID         string          `json:"id"`
+	Attributes json.RawMessage `json:"attributes"`
+}
+
+// MarshalJSONOrderedObject encodes the URN as
+// {"entity":…,"id":…,"attributes":{"key":"value",…}} with the attribute
+// keys in URN order, including attributes left unparsed under
+// MaxAttributesParsed. URNs with duplicate keys cannot be expressed this
+// way and are an error, as are components that are not valid UTF-8.
+func (u *URN) MarshalJSONOrderedObject() ([]byte, error) {
+	if err := u.checkJSONText(); err != nil {
+		return nil, err
+	}
+	pairs, err := u.AllAttributePairs()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	seen := make(map[string]bool, len(pairs))
+	for i, p := range pairs {
+		if seen[p.Key] {
+			return nil, fmt.Errorf("Cannot marshal URN: duplicate attribute %q", p.Key)
+		}
+		seen[p.Key] = true
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(p.Key)
+		v, _ := json.Marshal(p.Value)
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return json.Marshal(objectJSON{Entity: u.Entity, ID: u.ID, Attributes: buf.Bytes()})
+}
+
+// UnmarshalJSONOrderedObject decodes a document produced by
+// MarshalJSONOrderedObject, keeping the attributes in the order their keys
+// appear. Unknown fields, duplicate or non-string attributes and
+// components Compose would reject are errors.
+func UnmarshalJSONOrderedObject(data []byte) (*URN, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var doc objectJSON
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("Invalid ordered JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("Invalid ordered JSON: trailing data")
+	}
+	attrs, err := decodeOrderedObject(doc.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateComponents(doc.Entity, doc.ID, attrs); err != nil {
+		return nil, err
+	}
+	u := &URN{Entity: doc.Entity, ID: doc.ID}
+	if len(attrs) > 0 {
+		u.attributes = attrs
+	}
+	return u, nil
+}
+
+// decodeOrderedObject reads a JSON object of string values in encounter
+// order. A missing or null object yields no attributes.
+func decodeOrderedObject(raw json.RawMessage) ([]Attribute, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("Invalid ordered JSON: attributes must be an object")
+	}
+	var attrs []Attribute
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ordered JSON: %w", err)
+		}
+		key := tok.(string)
+		if seen[key] {
+			return nil, fmt.Errorf("Invalid ordered JSON: duplicate attribute %q", key)
+		}
+		seen[key] = true
+		var value string
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("Invalid ordered JSON: attribute %q: %w", key, err)
+		}
+		attrs = append(attrs, Attribute{Key: key, Value: value})
+	}
+	return attrs, nil
+}
@@ -0,0 +1,107 @@
+package urn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrderedJSONRoundTrip(t *testing.T) {
+	u, err := ComposeAttrs("order", "1", []Attribute{
+		{Key: "tag", Value: `red"quoted"`},
+		{Key: "tag", Value: `a\b{}[],:`},
+		{Key: "status", Value: "shipped"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := Parse(u)
+	data, err := parsed.MarshalOrderedJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"entity":"order","id":"1","attributes":[["tag","red\"quoted\""],["tag","a\\b{}[],:"],["status","shipped"]]}`
+	if string(data) != want {
+		t.Errorf("unexpected JSON:\n got %s\nwant %s", data, want)
+	}
+	back, err := UnmarshalOrderedJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.String() != u {
+		t.Errorf("round trip mismatch: %s vs %s", back.String(), u)
+	}
+}
+
+func TestOrderedJSONNoAttributes(t *testing.T) {
+	u, _ := Parse("urn:order:1")
+	data, _ := u.MarshalOrderedJSON()
+	if string(data) != `{"entity":"order","id":"1","attributes":[]}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestUnmarshalOrderedJSONStrict(t *testing.T) {
+	cases := map[string]string{
+		"unknown field": `{"entity":"order","id":"1","attributes":[],"extra":1}`,
+		"short pair":    `{"entity":"order","id":"1","attributes":[["tag"]]}`,
+		"long pair":     `{"entity":"order","id":"1","attributes":[["a","b","c"]]}`,
+		"empty key":     `{"entity":"order","id":"1","attributes":[["","b"]]}`,
+		"missing id":    `{"entity":"order","attributes":[]}`,
+		"trailing data": `{"entity":"order","id":"1","attributes":[]} {}`,
+		"not an object": `["order","1"]`,
+	}
+	for name, data := range cases {
+		if _, err := UnmarshalOrderedJSON([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	_, err := UnmarshalOrderedJSON([]byte(`{"entity":"order","id":"1","attributes":[["a","b","c"]]}`))
+	if !strings.Contains(err.Error(), "attribute 0 has 3 elements") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMarshalJSONOrderedObject(t *testing.T) {
+	u, err := Parse("urn:order:1:vendor:amazon:status:shipped:a:%22q%22")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := u.MarshalJSONOrderedObject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"entity":"order","id":"1","attributes":{"vendor":"amazon","status":"shipped","a":"\"q\""}}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+
+	back, err := UnmarshalJSONOrderedObject(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.String() != u.String() {
+		t.Errorf("round trip: got %s, want %s", back, u)
+	}
+
+	empty, _ := Parse("urn:order:1")
+	if data, _ := empty.MarshalJSONOrderedObject(); string(data) != `{"entity":"order","id":"1","attributes":{}}` {
+		t.Errorf("got %s", data)
+	}
+}
+
+func TestJSONOrderedObjectDuplicates(t *testing.T) {
+	u, _ := Parse("urn:order:1:tag:red:tag:blue")
+	if _, err := u.MarshalJSONOrderedObject(); err == nil {
+		t.Error("expected error for duplicate keys")
+	}
+	bad := []string{
+		`{"entity":"order","id":"1","attributes":{"tag":"red","tag":"blue"}}`,
+		`{"entity":"order","id":"1","attributes":{"n":1}}`,
+		`{"entity":"order","id":"1","attributes":[]}`,
+	}
+	for _, in := range bad {
+		if _, err := UnmarshalJSONOrderedObject([]byte(in)); err == nil {
+			t.Errorf("UnmarshalJSONOrderedObject(%s): expected error", in)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package urn
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// EncodeJSONArray writes urns to w as a JSON array of strings, one element
+// at a time, so the collection is never buffered as a whole. After each
+// element w is flushed if it has a Flush method (such as *bufio.Writer or
+// http.Flusher). Encoding stops at the first error, which names the index
+// of the failing element.
+func EncodeJSONArray(w io.Writer, urns iter.Seq[*URN]) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	var buf []byte
+	i := 0
+	for u := range urns {
+		if u == nil {
+			return fmt.Errorf("Cannot encode URN %d: nil URN", i)
+		}
+		text, err := u.MarshalText()
+		if err != nil {
+			return fmt.Errorf("Cannot encode URN %d: %w", i, err)
+		}
+		buf = buf[:0]
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		quoted, _ := json.Marshal(string(text))
+		buf = append(buf, quoted...)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		if err := flush(w); err != nil {
+			return err
+		}
+		i++
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// flush flushes w when it supports it.
+func flush(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
+
+// DecodeJSONArray reads a JSON array of URN strings from r, calling fn for
+// each element as it is decoded. Decoding stops at the first error from
+// the input, from parsing or from fn; errors name the element index.
+func DecodeJSONArray(r io.Reader, fn func(*URN) error) error {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		if err == nil {
+			err = fmt.Errorf("want array, got %v", tok)
+		}
+		return fmt.Errorf("Invalid JSON array: %w", err)
+	}
+	for i := 0; dec.More(); i++ {
+		var u URN
+		if err := dec.Decode(&u); err != nil {
+			return fmt.Errorf("Invalid JSON array element %d: %w", i, err)
+		}
+		if err := fn(&u); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("Invalid JSON array: %w", err)
+	}
+	return nil
+}
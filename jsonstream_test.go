@@ -0,0 +1,112 @@
+package urn
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"testing"
+)
+
+// generate yields n URNs without materializing them.
+func generate(n int) iter.Seq[*URN] {
+	return func(yield func(*URN) bool) {
+		for i := 0; i < n; i++ {
+			u := &URN{Entity: "orders", ID: fmt.Sprint(i), attributes: []Attribute{{"path", "a/b"}}}
+			if !yield(u) {
+				return
+			}
+		}
+	}
+}
+
+// chunkWriter records the size of each write and how often it was flushed.
+type chunkWriter struct {
+	bytes.Buffer
+	writes, flushes, maxWrite int
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.writes++
+	w.maxWrite = max(w.maxWrite, len(p))
+	return w.Buffer.Write(p)
+}
+
+func (w *chunkWriter) Flush() { w.flushes++ }
+
+func TestEncodeJSONArrayStreams(t *testing.T) {
+	const n = 50000
+	var w chunkWriter
+	if err := EncodeJSONArray(&w, generate(n)); err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(w.Bytes()) {
+		t.Fatal("output is not valid JSON")
+	}
+	if w.flushes != n || w.maxWrite > 64 {
+		t.Errorf("expected per-element writes: %d flushes, largest write %d bytes", w.flushes, w.maxWrite)
+	}
+
+	count := 0
+	err := DecodeJSONArray(bytes.NewReader(w.Bytes()), func(u *URN) error {
+		if u.ID != fmt.Sprint(count) || u.Attributes()["path"] != "a/b" {
+			return fmt.Errorf("unexpected element %d: %v", count, u)
+		}
+		count++
+		return nil
+	})
+	if err != nil || count != n {
+		t.Errorf("decoded %d elements, err %v", count, err)
+	}
+}
+
+func TestEncodeJSONArrayErrors(t *testing.T) {
+	var buf bytes.Buffer
+	urns := func(yield func(*URN) bool) {
+		if yield(&URN{Entity: "orders", ID: "1"}) {
+			yield(&URN{Entity: "orders"})
+		}
+	}
+	err := EncodeJSONArray(&buf, urns)
+	if err == nil || !strings.Contains(err.Error(), "URN 1") {
+		t.Errorf("expected error naming element 1, got %v", err)
+	}
+
+	if err := EncodeJSONArray(&buf, generate(0)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeJSONArrayErrors(t *testing.T) {
+	noop := func(*URN) error { return nil }
+	for _, in := range []string{`{}`, `["urn:orders:1", "nope"]`, `["urn:orders:1"`, `[1]`} {
+		if err := DecodeJSONArray(strings.NewReader(in), noop); err == nil {
+			t.Errorf("DecodeJSONArray(%s): expected error", in)
+		}
+	}
+	err := DecodeJSONArray(strings.NewReader(`["urn:orders:1", "nope"]`), noop)
+	if !strings.Contains(err.Error(), "element 1") {
+		t.Errorf("error does not name the index: %v", err)
+	}
+	stop := errors.New("stop")
+	if err := DecodeJSONArray(strings.NewReader(`["urn:orders:1"]`), func(*URN) error { return stop }); err != stop {
+		t.Errorf("callback error not propagated: %v", err)
+	}
+}
+
+func TestURNTextMarshaling(t *testing.T) {
+	type doc struct {
+		Ref *URN `json:"ref"`
+	}
+	u, _ := Parse("urn:orders:1:path:a%2Fb")
+	data, err := json.Marshal(doc{u})
+	if err != nil || string(data) != `{"ref":"urn:orders:1:path:a%2Fb"}` {
+		t.Fatalf("got %s, %v", data, err)
+	}
+	var back doc
+	if err := json.Unmarshal(data, &back); err != nil || back.Ref.Attributes()["path"] != "a/b" {
+		t.Errorf("got %v, %v", back.Ref, err)
+	}
+}
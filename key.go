@@ -0,0 +1,120 @@
+package urn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxKeyAttributes is the most attributes a Key can hold.
+const MaxKeyAttributes = 7
+
+// ErrTooManyKeyAttributes is wrapped by the error ParseValue returns for
+// a URN with more than MaxKeyAttributes attributes.
+var ErrTooManyKeyAttributes = errors.New("too many attributes for Key")
+
+// Key is a URN held by value: its canonical string plus the offsets of
+// its segments in a fixed array. It has no pointers to check for nil and
+// is comparable, so equivalent URNs give == Keys and a Key works as a map
+// key. The zero Key is empty; its String is "".
+//
+// The offsets array holds the entity, the ID and MaxKeyAttributes
+// attribute pairs. ParseValue rejects URNs with more attributes rather
+// than spilling into a slice, which would make Key incomparable.
+type Key struct {
+	s    string
+	offs [2 + 2*MaxKeyAttributes]uint16 // start of each segment after the scheme
+	n    uint8                          // number of segments
+}
+
+// ParseValue parses s into its value form, a Key holding its canonical
+// form (see Canonical), so inputs that differ only in entity case,
+// attribute order or escaping give equal Keys.
+func ParseValue(s string) (Key, error) {
+	c, err := Canonical(s)
+	if err != nil {
+		return Key{}, err
+	}
+	var v Key
+	v.s = c
+	for i := len("urn:"); i >= 0; v.n++ {
+		if int(v.n) == len(v.offs) {
+			return Key{}, fmt.Errorf("Invalid URN: %w (max %d)", ErrTooManyKeyAttributes, MaxKeyAttributes)
+		}
+		v.offs[v.n] = uint16(i)
+		_, i = nextSegment(c, i)
+	}
+	return v, nil
+}
+
+// String returns the canonical form.
+func (v Key) String() string {
+	return v.s
+}
+
+// IsZero reports whether v is the zero Key.
+func (v Key) IsZero() bool {
+	return v.s == ""
+}
+
+// segment returns the raw segment i, or "" past the last one.
+func (v Key) segment(i int) string {
+	if i >= int(v.n) {
+		return ""
+	}
+	end := len(v.s)
+	if i+1 < int(v.n) {
+		end = int(v.offs[i+1]) - 1
+	}
+	return v.s[v.offs[i]:end]
+}
+
+// decodeCanonical decodes a segment of the canonical form, which is always well
+// escaped.
+func decodeCanonical(seg string) string {
+	s, _ := unescape(seg)
+	return s
+}
+
+// Entity returns the decoded entity, lowercased.
+func (v Key) Entity() string {
+	return decodeCanonical(v.segment(0))
+}
+
+// ID returns the decoded ID.
+func (v Key) ID() string {
+	return decodeCanonical(v.segment(1))
+}
+
+// NumAttributes returns the number of attributes.
+func (v Key) NumAttributes() int {
+	if v.n < 2 {
+		return 0
+	}
+	return int(v.n-2) / 2
+}
+
+// Attribute returns the decoded attribute i, in canonical order.
+func (v Key) Attribute(i int) Attribute {
+	return Attribute{Key: decodeCanonical(v.segment(2 + 2*i)), Value: decodeCanonical(v.segment(3 + 2*i))}
+}
+
+// Value returns the decoded value of the first attribute with the key, in
+// canonical order.
+func (v Key) Value(key string) (string, bool) {
+	ek := escape(key)
+	for i := 2; i+1 < int(v.n); i += 2 {
+		if v.segment(i) == ek {
+			return decodeCanonical(v.segment(i + 1)), true
+		}
+	}
+	return "", false
+}
+
+// URN parses v into a *URN. It returns nil for the zero Key.
+func (v Key) URN() *URN {
+	if v.IsZero() {
+		return nil
+	}
+	u, _ := Parse(v.s)
+	return u
+}
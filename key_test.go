@@ -0,0 +1,80 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseValueEquality(t *testing.T) {
+	a, err := ParseValue("URN:Orders:1:vendor:amazon:note:a%2fb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseValue("urn:orders:1:note:a%2Fb:%76endor:amazon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("%v != %v, want equivalent inputs equal", a, b)
+	}
+	c, _ := ParseValue("urn:orders:2:note:a%2Fb:vendor:amazon")
+	if a == c {
+		t.Error("different IDs compare equal")
+	}
+	m := map[Key]int{a: 1}
+	if m[b] != 1 {
+		t.Error("equivalent Key missed in map")
+	}
+	if a.String() != "urn:orders:1:note:a%2Fb:vendor:amazon" {
+		t.Errorf("String = %q", a.String())
+	}
+	if again, _ := ParseValue(a.String()); again != a {
+		t.Error("String does not round-trip")
+	}
+	var zero Key
+	if !zero.IsZero() || zero.String() != "" || zero.URN() != nil || zero.Entity() != "" || zero.NumAttributes() != 0 {
+		t.Error("zero Key is not empty")
+	}
+}
+
+func TestKeyAccessors(t *testing.T) {
+	k, err := ParseValue("urn:Mail:ada%40example.com:b:2:a:x%3Ay:a:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Entity() != "mail" || k.ID() != "ada@example.com" {
+		t.Errorf("Entity, ID = %q, %q", k.Entity(), k.ID())
+	}
+	if k.NumAttributes() != 3 {
+		t.Errorf("NumAttributes = %d", k.NumAttributes())
+	}
+	if v, ok := k.Value("a"); !ok || v != "1" {
+		t.Errorf("Value(a) = %q, %v, want first in canonical order", v, ok)
+	}
+	if v, ok := k.Value("b"); !ok || v != "2" {
+		t.Errorf("Value(b) = %q, %v", v, ok)
+	}
+	if _, ok := k.Value("mail"); ok {
+		t.Error("Value found the entity as a key")
+	}
+	if got := k.Attribute(1); got != (Attribute{Key: "a", Value: "x:y"}) {
+		t.Errorf("Attribute(1) = %+v", got)
+	}
+	if u := k.URN(); u == nil || u.String() != k.String() {
+		t.Errorf("URN = %v", u)
+	}
+}
+
+func TestParseValueAttributeLimit(t *testing.T) {
+	s := "urn:orders:1" + strings.Repeat(":k:v", MaxKeyAttributes)
+	if k, err := ParseValue(s); err != nil || k.NumAttributes() != MaxKeyAttributes {
+		t.Errorf("ParseValue(%d attributes) = %v, %v", MaxKeyAttributes, k, err)
+	}
+	if _, err := ParseValue(s + ":x:y"); !errors.Is(err, ErrTooManyKeyAttributes) {
+		t.Errorf("ParseValue(%d attributes) error = %v, want ErrTooManyKeyAttributes", MaxKeyAttributes+1, err)
+	}
+	if _, err := ParseValue("urn:orders"); err == nil {
+		t.Error("ParseValue accepted an invalid URN")
+	}
+}
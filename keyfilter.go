@@ -0,0 +1,161 @@
+package urn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyFilter checks which attribute keys a URN string carries, in a single
+// pass over its segments and without parsing it into a URN. Build one with
+// CompileKeyFilter; it is safe for concurrent use.
+type KeyFilter struct {
+	opts options
+	// index maps each key to its position in required followed by
+	// forbidden, and escaped maps the escaped form of keys that need
+	// escaping, so canonically written segments skip decoding. Under
+	// CaseInsensitiveKeys both are lowercased.
+	index    map[string]int
+	escaped  map[string]int
+	required int // number of required keys; positions from required on are forbidden
+	total    int
+}
+
+// RequiredKeys reports whether urnStr has an attribute with every key in
+// required and none with a key in forbidden. See (*KeyFilter).Match.
+func RequiredKeys(urnStr string, required, forbidden []string) (bool, error) {
+	f, err := CompileKeyFilter(required, forbidden)
+	if err != nil {
+		return false, err
+	}
+	return f.Match(urnStr)
+}
+
+// CompileKeyFilter returns a KeyFilter matching URNs that carry every key
+// in required and none in forbidden, under the SetDefaults options. An
+// empty key, or one listed as both required and forbidden, is an error.
+func CompileKeyFilter(required, forbidden []string) (*KeyFilter, error) {
+	return defaultProcessor.CompileKeyFilter(required, forbidden)
+}
+
+// CompileKeyFilter is like the package-level CompileKeyFilter, under the
+// Processor's options.
+func (p *Processor) CompileKeyFilter(required, forbidden []string) (*KeyFilter, error) {
+	o, _ := p.snapshot()
+	f := &KeyFilter{opts: *o, index: make(map[string]int, len(required)+len(forbidden))}
+	add := func(key string, isRequired bool) error {
+		if key == "" {
+			return fmt.Errorf("Invalid key filter: empty key")
+		}
+		k := f.fold(key)
+		if i, ok := f.index[k]; ok {
+			if (i < f.required) != isRequired {
+				return fmt.Errorf("Invalid key filter: key %q is both required and forbidden", key)
+			}
+			return nil
+		}
+		f.index[k] = f.total
+		if esc := f.fold(escape(k)); esc != k {
+			if f.escaped == nil {
+				f.escaped = make(map[string]int)
+			}
+			f.escaped[esc] = f.total
+		}
+		f.total++
+		return nil
+	}
+	for _, k := range required {
+		if err := add(k, true); err != nil {
+			return nil, err
+		}
+	}
+	f.required = f.total
+	for _, k := range forbidden {
+		if err := add(k, false); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (f *KeyFilter) fold(key string) string {
+	if f.opts.foldKeys {
+		return asciiToLower(key)
+	}
+	return key
+}
+
+// Match reports whether urnStr carries every required key and no
+// forbidden one. Only attribute keys count: a key that appears as the
+// entity, the ID or an attribute value does not. Keys compare after
+// decoding, exactly or, under CaseInsensitiveKeys, ASCII case-insensitively.
+// Input is checked for a known scheme, non-empty segments, complete
+// key/value pairs and well-formed escapes in the keys; other segments are
+// not decoded.
+func (f *KeyFilter) Match(urnStr string) (match bool, err error) {
+	if debugGuards {
+		defer recoverPanic("KeyFilter.Match", &err)
+	}
+	start := f.opts.schemeEnd(urnStr)
+	if start < 0 {
+		return false, &InvalidURNError{
+			Kind: KindScheme,
+		}
+	}
+	// Presence bits for the keys; 64 keys need no allocation.
+	var small [1]uint64
+	seen := small[:]
+	if f.total > 64 {
+		seen = make([]uint64, (f.total+63)/64)
+	}
+	segs := 0
+	for i := start; i >= 0; segs++ {
+		seg, next := nextSegment(urnStr, i)
+		if seg == "" {
+			if segs < 2 {
+				return false, &InvalidURNError{
+					Kind:   KindEmptyComponent,
+					Offset: i,
+				}
+			}
+			return false, &InvalidURNError{
+				Kind:   KindEmptyAttribute,
+				Offset: i,
+			}
+		}
+		if segs >= 2 && segs%2 == 0 {
+			var k int
+			var ok bool
+			if strings.IndexByte(seg, '%') < 0 {
+				k, ok = f.index[f.fold(seg)]
+			} else if k, ok = f.escaped[f.fold(seg)]; !ok {
+				key, err := unescapeAt(seg, i)
+				if err != nil {
+					return false, err
+				}
+				k, ok = f.index[f.fold(key)]
+			}
+			if ok {
+				seen[k/64] |= 1 << (k % 64)
+			}
+		}
+		i = next
+	}
+	if segs < 2 {
+		return false, &InvalidURNError{
+			Kind:   KindMissingComponent,
+			Offset: len(urnStr),
+		}
+	}
+	if segs%2 != 0 {
+		return false, &InvalidURNError{
+			Kind:   KindUnpairedKey,
+			Offset: len(urnStr),
+		}
+	}
+	for k := 0; k < f.total; k++ {
+		if present := seen[k/64]&(1<<(k%64)) != 0; present != (k < f.required) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
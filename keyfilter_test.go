@@ -0,0 +1,131 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRequiredKeys(t *testing.T) {
+	required, forbidden := []string{"vendor", "sku"}, []string{"archived"}
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"urn:item:1:vendor:acme:sku:x1", true},
+		{"urn:item:1:sku:x1:vendor:acme:color:red", true},
+		{"urn:item:1:vendor:acme", false},
+		{"urn:item:1:vendor:acme:sku:x1:archived:yes", false},
+		// Keys appearing only as the entity, the ID or a value do not count.
+		{"urn:vendor:sku:note:vendor:tag:sku", false},
+		{"urn:item:1:vendor:sku", false},
+		{"urn:item:1:vendor:acme:sku:x1:note:archived", true},
+		// Keys compare after decoding.
+		{"urn:item:1:%76endor:acme:sk%75:x1", true},
+		{"urn:item:1:vendor:acme:sku:x1:%61rchived:yes", false},
+	}
+	for _, tt := range tests {
+		got, err := RequiredKeys(tt.in, required, forbidden)
+		if err != nil || got != tt.want {
+			t.Errorf("RequiredKeys(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestKeyFilterEscapedKeys(t *testing.T) {
+	f, err := CompileKeyFilter([]string{"a/b"}, []string{"x y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for in, want := range map[string]bool{
+		"urn:item:1:a%2Fb:1":           true,
+		"urn:item:1:a%2fb:1":           true,
+		"urn:item:1:a%2Fb:1:x%20y:2":   false,
+		"urn:item:1:a%2Fb:1:x%2520y:2": true,
+	} {
+		if got, err := f.Match(in); err != nil || got != want {
+			t.Errorf("Match(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+}
+
+func TestKeyFilterCaseInsensitive(t *testing.T) {
+	f, err := NewProcessor(CaseInsensitiveKeys()).CompileKeyFilter([]string{"Vendor"}, []string{"ARCHIVED"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := f.Match("urn:item:1:VENDOR:acme"); !ok {
+		t.Error("Match ignored CaseInsensitiveKeys for a required key")
+	}
+	if ok, _ := f.Match("urn:item:1:vendor:acme:Archived:1"); ok {
+		t.Error("Match ignored CaseInsensitiveKeys for a forbidden key")
+	}
+	if ok, _ := RequiredKeys("urn:item:1:VENDOR:acme", []string{"vendor"}, nil); ok {
+		t.Error("RequiredKeys folded case without CaseInsensitiveKeys")
+	}
+}
+
+func TestKeyFilterManyKeys(t *testing.T) {
+	var keys []string
+	var b strings.Builder
+	b.WriteString("urn:item:1")
+	for i := range 70 {
+		k := "k" + string(rune('a'+i/26)) + string(rune('a'+i%26))
+		keys = append(keys, k)
+		b.WriteString(":" + k + ":v")
+	}
+	if ok, err := RequiredKeys(b.String(), keys, nil); !ok || err != nil {
+		t.Errorf("RequiredKeys(70 keys) = %v, %v", ok, err)
+	}
+	if ok, _ := RequiredKeys("urn:item:1:kaa:v", keys, nil); ok {
+		t.Error("RequiredKeys matched with keys missing")
+	}
+}
+
+func TestKeyFilterErrors(t *testing.T) {
+	if _, err := CompileKeyFilter([]string{"a"}, []string{"a"}); err == nil {
+		t.Error("CompileKeyFilter accepted a key both required and forbidden")
+	}
+	if _, err := CompileKeyFilter([]string{""}, nil); err == nil {
+		t.Error("CompileKeyFilter accepted an empty key")
+	}
+	for in, kind := range map[string]ErrorKind{
+		"isbn:1":            KindScheme,
+		"urn:item":          KindMissingComponent,
+		"urn:item:1:vendor": KindUnpairedKey,
+		"urn:item:1::x":     KindEmptyAttribute,
+		"urn:item:1:%zz:x":  KindMalformedEscape,
+	} {
+		_, err := RequiredKeys(in, []string{"vendor"}, nil)
+		var ue *InvalidURNError
+		if !errors.As(err, &ue) || ue.Kind != kind {
+			t.Errorf("RequiredKeys(%q) = %v, want %v", in, err, kind)
+		}
+	}
+}
+
+func TestKeyFilterAllocs(t *testing.T) {
+	f, _ := CompileKeyFilter([]string{"vendor", "sku"}, []string{"archived"})
+	in := "urn:item:1:vendor:acme:sku:x1:color:red"
+	if n := testing.AllocsPerRun(100, func() { f.Match(in) }); n != 0 {
+		t.Errorf("Match allocates %v times, want 0", n)
+	}
+}
+
+func BenchmarkKeyFilter(b *testing.B) {
+	in := "urn:item:12345:vendor:acme:region:eu:sku:x1:color:red"
+	b.Run("KeyFilter", func(b *testing.B) {
+		f, _ := CompileKeyFilter([]string{"vendor", "sku"}, []string{"archived"})
+		for b.Loop() {
+			f.Match(in)
+		}
+	})
+	b.Run("Value", func(b *testing.B) {
+		for b.Loop() {
+			_, v, _ := Value(in, "vendor")
+			_, s, _ := Value(in, "sku")
+			_, a, _ := Value(in, "archived")
+			_ = v && s && !a
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package urn
+
+import (
+	"strings"
+)
+
+// NamespaceSeparator joins a namespace and a key in a namespaced attribute
+// key, e.g. "billing.status". Namespaces nest: "a.b.key" is key "key" in
+// namespace "a.b". Because any key containing the separator is read as
+// namespaced, Compose, AddAttribute and Builder reject keys with an empty
+// part, such as ".key" or "a..key".
+const NamespaceSeparator = "."
+
+// NamespacedKey returns key qualified by ns. It does not validate its
+// arguments; AddNamespacedAttribute does.
+func NamespacedKey(ns, key string) string {
+	return ns + NamespaceSeparator + key
+}
+
+// AddNamespacedAttribute appends or updates the attribute key in namespace
+// ns. The key itself must not contain NamespaceSeparator; ns may, to name a
+// nested namespace.
+func AddNamespacedAttribute(urnStr, ns, key, value string) (string, error) {
+	if strings.Contains(key, NamespaceSeparator) {
+		return "", &InvalidURNError{
+			Kind:    KindInvalidKey,
+			Reason:  ReasonNamespaceSeparator,
+			Op:      OpAttribute,
+			Segment: key,
+			Key:     key,
+			Value:   NamespaceSeparator,
+		}
+	}
+	return AddAttribute(urnStr, NamespacedKey(ns, key), value)
+}
+
+// ValuesInNamespace returns the attributes in namespace ns, keyed by the
+// remainder of their key after the namespace prefix. Keys in nested
+// namespaces keep their inner prefix: with ns "a", "a.b.key" is returned
+// as "b.key". For duplicate keys the first value wins.
+func ValuesInNamespace(urnStr, ns string) (map[string]string, error) {
+	o := &loadConfig().opts
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for _, a := range u.attributes {
+		rest, ok := o.inNamespace(a.Key, ns)
+		if !ok {
+			continue
+		}
+		if _, dup := values[rest]; !dup {
+			values[rest] = a.Value
+		}
+	}
+	return values, nil
+}
+
+// StripNamespace removes every attribute in namespace ns, including nested
+// namespaces, from the URN.
+func StripNamespace(urnStr, ns string) (string, error) {
+	o := &loadConfig().opts
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return "", err
+	}
+	kept := make([]Attribute, 0, len(u.attributes))
+	for _, a := range u.attributes {
+		if _, ok := o.inNamespace(a.Key, ns); !ok {
+			kept = append(kept, a)
+		}
+	}
+	return composeRest(o.scheme(), u.Entity, u.ID, kept, u.rest)
+}
+
+// inNamespace reports whether key lies in namespace ns and returns the
+// rest of the key after the separator.
+func (o *options) inNamespace(key, ns string) (string, bool) {
+	n := len(ns) + len(NamespaceSeparator)
+	if ns == "" || len(key) <= n || key[len(ns):n] != NamespaceSeparator {
+		return "", false
+	}
+	if !o.keyEqual(key[:len(ns)], ns) {
+		return "", false
+	}
+	return key[n:], true
+}
+
+// checkKeyParts rejects namespaced keys with an empty part and applies
+// the key format to each part.
+func (c *config) checkKeyParts(key string) error {
+	for part := range strings.SplitSeq(key, NamespaceSeparator) {
+		if part == "" {
+			return &InvalidURNError{
+				Kind:    KindInvalidKey,
+				Reason:  ReasonEmptyNamespacePart,
+				Op:      OpAttribute,
+				Segment: key,
+				Key:     key,
+			}
+		}
+		if c.keyFormat != nil && !c.keyFormat.MatchString(part) {
+			return &InvalidURNError{
+				Kind:    KindInvalidKey,
+				Op:      OpAttribute,
+				Segment: key,
+				Key:     key,
+				Want:    c.keyFormat.String(),
+			}
+		}
+	}
+	return nil
+}
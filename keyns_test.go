@@ -0,0 +1,69 @@
+package urn
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestNamespacedAttributes(t *testing.T) {
+	s := "urn:orders:1:status:new"
+	var err error
+	for _, kv := range [][3]string{
+		{"billing", "status", "paid"},
+		{"billing.tax", "region", "eu"},
+		{"shipping", "status", "pending"},
+	} {
+		if s, err = AddNamespacedAttribute(s, kv[0], kv[1], kv[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const want = "urn:orders:1:status:new:billing.status:paid:billing.tax.region:eu:shipping.status:pending"
+	if s != want {
+		t.Fatalf("got %s, want %s", s, want)
+	}
+
+	got, err := ValuesInNamespace(s, "billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"status": "paid", "tax.region": "eu"}; !maps.Equal(got, want) {
+		t.Errorf("ValuesInNamespace(billing) = %v, want %v", got, want)
+	}
+	got, _ = ValuesInNamespace(s, "billing.tax")
+	if want := map[string]string{"region": "eu"}; !maps.Equal(got, want) {
+		t.Errorf("ValuesInNamespace(billing.tax) = %v, want %v", got, want)
+	}
+	got, _ = ValuesInNamespace(s, "bill")
+	if len(got) != 0 {
+		t.Errorf("prefix of a namespace matched: %v", got)
+	}
+
+	stripped, err := StripNamespace(s, "billing")
+	if err != nil || stripped != "urn:orders:1:status:new:shipping.status:pending" {
+		t.Errorf("StripNamespace = %s, %v", stripped, err)
+	}
+}
+
+func TestNamespacedKeyRejected(t *testing.T) {
+	var e *InvalidURNError
+	if _, err := AddNamespacedAttribute("urn:orders:1", "billing", "tax.region", "eu"); !errors.As(err, &e) || e.Kind != KindInvalidKey {
+		t.Errorf("key with separator: got %v", err)
+	}
+	for _, key := range []string{".status", "status.", "a..status"} {
+		if _, err := Compose("orders", "1", map[string]string{key: "x"}); !errors.As(err, &e) || e.Kind != KindInvalidKey {
+			t.Errorf("Compose with key %q: got %v", key, err)
+		}
+	}
+}
+
+func TestNamespacedKeyFormat(t *testing.T) {
+	SetKeyFormat(DefaultKeyFormat)
+	t.Cleanup(func() { SetKeyFormat(nil) })
+	if _, err := AddNamespacedAttribute("urn:orders:1", "a.b", "key", "v"); err != nil {
+		t.Errorf("valid parts rejected: %v", err)
+	}
+	if _, err := AddNamespacedAttribute("urn:orders:1", "a.1b", "key", "v"); err == nil {
+		t.Error("part violating the key format accepted")
+	}
+}
@@ -0,0 +1,62 @@
+package urn
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultKeyFormat applies the entity character set to attribute keys and
+// additionally requires a leading letter. Enable it with SetKeyFormat.
+var DefaultKeyFormat = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]{0,31}$`)
+
+// SetReservedKeys replaces the set of attribute keys that Compose,
+// ComposeAttrs, AddAttribute and Builder reject, and that Check flags.
+// Keys match ASCII case-insensitively. Calling it with no keys clears the
+// set. Parse stays permissive.
+func SetReservedKeys(keys ...string) {
+	var reserved map[string]bool
+	if len(keys) > 0 {
+		reserved = make(map[string]bool, len(keys))
+		for _, k := range keys {
+			reserved[asciiToLower(k)] = true
+		}
+	}
+	updateConfig(func(c *config) {
+		c.reservedKeys = reserved
+	})
+}
+
+// SetKeyFormat makes Compose, ComposeAttrs, AddAttribute and Builder reject
+// attribute keys that do not match re, and Check flag them. Pass
+// DefaultKeyFormat for the standard rules, or nil to accept any key.
+func SetKeyFormat(re *regexp.Regexp) {
+	updateConfig(func(c *config) {
+		c.keyFormat = re
+	})
+}
+
+// checkKey applies the key policy to a decoded attribute key.
+func (c *config) checkKey(key string) error {
+	format := c.keyFormat
+	if c.reservedKeys[asciiToLower(key)] {
+		return &InvalidURNError{
+			Kind:    KindReservedKey,
+			Op:      OpAttribute,
+			Segment: key,
+			Key:     key,
+		}
+	}
+	if strings.Contains(key, NamespaceSeparator) {
+		return c.checkKeyParts(key)
+	}
+	if format != nil && !format.MatchString(key) {
+		return &InvalidURNError{
+			Kind:    KindInvalidKey,
+			Op:      OpAttribute,
+			Segment: key,
+			Key:     key,
+			Want:    format.String(),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package urn
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func kindOf(err error) ErrorKind {
+	var ue *InvalidURNError
+	if errors.As(err, &ue) {
+		return ue.Kind
+	}
+	return KindUnknown
+}
+
+func TestReservedKeys(t *testing.T) {
+	SetReservedKeys("urn", "id")
+	t.Cleanup(func() { SetReservedKeys() })
+
+	if _, err := Compose("orders", "1", map[string]string{"ID": "2"}); kindOf(err) != KindReservedKey {
+		t.Errorf("expected reserved key error from Compose, got %v", err)
+	}
+	if _, err := AddAttribute("urn:orders:1", "urn", "x"); kindOf(err) != KindReservedKey {
+		t.Errorf("expected reserved key error from AddAttribute, got %v", err)
+	}
+	if _, err := NewBuilder("orders", "1").Attr("id", "2").Build(); kindOf(err) != KindReservedKey {
+		t.Errorf("expected reserved key error from Builder, got %v", err)
+	}
+	if err := ValidateComponents("orders", "1", map[string]string{"id": "2"}); kindOf(err) != KindReservedKey {
+		t.Errorf("expected ValidateComponents to agree, got %v", err)
+	}
+
+	if _, err := Parse("urn:orders:1:id:2"); err != nil {
+		t.Errorf("Parse must stay permissive: %v", err)
+	}
+	err := Check("urn:orders:1:a:1:id:2")
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Kind != KindReservedKey || ue.Offset != 17 || ue.Segment != "id" {
+		t.Errorf("expected Check to flag the reserved key, got %#v", err)
+	}
+}
+
+func TestDefaultKeyFormat(t *testing.T) {
+	SetKeyFormat(DefaultKeyFormat)
+	t.Cleanup(func() { SetKeyFormat(nil) })
+
+	if _, err := Compose("orders", "1", map[string]string{"9lives": "x"}); kindOf(err) != KindInvalidKey {
+		t.Errorf("expected digit-leading key to be rejected, got %v", err)
+	}
+	if IsValid("urn:orders:1:9lives:x") {
+		t.Error("expected Check to flag digit-leading key")
+	}
+	if _, err := Compose("orders", "1", map[string]string{"vendor-code": "x"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestKeyFormatOverride(t *testing.T) {
+	SetKeyFormat(regexp.MustCompile(`^[a-z_]+$`))
+	t.Cleanup(func() { SetKeyFormat(nil) })
+
+	if _, err := Compose("orders", "1", map[string]string{"vendor_code": "x"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := Compose("orders", "1", map[string]string{"Vendor": "x"}); kindOf(err) != KindInvalidKey {
+		t.Errorf("expected invalid key error, got %v", err)
+	}
+}
+
+func TestKeyPolicyDisabledByDefault(t *testing.T) {
+	if _, err := Compose("orders", "1", map[string]string{"9lives": "x", "id": "2"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package urn
+
+// LazyURN parses a URN string on demand. Entity and ID only parse the
+// scheme, entity and ID; attributes are parsed the first time they are
+// requested. Results and errors are cached. A LazyURN is not safe for
+// concurrent use.
+//
+// Errors match those returned by Parse, but surface from whichever
+// accessor first touches the malformed part: a URN with a malformed
+// attribute section still yields its Entity and ID.
+type LazyURN struct {
+	s string
+	o *options
+
+	headDone bool
+	entity   string
+	id       string
+	tailOff  int
+	headErr  error
+
+	tailDone bool
+	attrs    []Attribute
+	flagged  bool
+	rest     string
+	tailErr  error
+}
+
+// Lazy wraps urnStr for on-demand parsing.
+func Lazy(urnStr string) LazyURN {
+	return LazyURN{s: urnStr}
+}
+
+func (l *LazyURN) head() error {
+	if !l.headDone {
+		l.o = &loadConfig().opts
+		l.entity, l.id, l.tailOff, l.headErr = parseHead(l.s, l.o)
+		l.headDone = true
+	}
+	return l.headErr
+}
+
+func (l *LazyURN) tail() error {
+	if err := l.head(); err != nil {
+		return err
+	}
+	if !l.tailDone {
+		if l.tailOff >= 0 {
+			l.attrs, l.flagged, l.rest, l.tailErr = parseTail(l.s, l.tailOff, l.o)
+		}
+		l.tailDone = true
+	}
+	return l.tailErr
+}
+
+// String returns the wrapped input.
+func (l *LazyURN) String() string {
+	return l.s
+}
+
+// Entity returns the decoded entity.
+func (l *LazyURN) Entity() (string, error) {
+	if err := l.head(); err != nil {
+		return "", err
+	}
+	return l.entity, nil
+}
+
+// ID returns the decoded ID.
+func (l *LazyURN) ID() (string, error) {
+	if err := l.head(); err != nil {
+		return "", err
+	}
+	return l.id, nil
+}
+
+// Value retrieves the value for a specific attribute key.
+func (l *LazyURN) Value(key string) (string, bool, error) {
+	if err := l.tail(); err != nil {
+		return "", false, err
+	}
+	for _, p := range l.attrs {
+		if p.Key == key {
+			return p.Value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// AttributePairs returns a copy of the attributes in their original order.
+func (l *LazyURN) AttributePairs() ([]Attribute, error) {
+	if err := l.tail(); err != nil {
+		return nil, err
+	}
+	if len(l.attrs) == 0 {
+		return nil, nil
+	}
+	pairs := make([]Attribute, len(l.attrs))
+	copy(pairs, l.attrs)
+	return pairs, nil
+}
+
+// URN fully parses the input and returns it as a URN.
+func (l *LazyURN) URN() (*URN, error) {
+	if err := l.tail(); err != nil {
+		return nil, err
+	}
+	u := &URN{Entity: l.entity, ID: l.id, raw: l.s, trailingFlag: l.flagged, rest: l.rest, parsedEntity: true}
+	if len(l.attrs) > 0 {
+		u.attributes = make([]Attribute, len(l.attrs))
+		copy(u.attributes, l.attrs)
+	}
+	return u, nil
+}
@@ -0,0 +1,86 @@
+package urn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	l := Lazy("urn:orders:a%2Fb:vendor:amazon")
+	entity, err := l.Entity()
+	if err != nil || entity != "orders" {
+		t.Fatalf("unexpected entity %q: %v", entity, err)
+	}
+	if l.tailDone {
+		t.Error("attributes were parsed eagerly")
+	}
+	id, _ := l.ID()
+	if id != "a/b" {
+		t.Errorf("unexpected ID: %s", id)
+	}
+	val, found, err := l.Value("vendor")
+	if err != nil || !found || val != "amazon" {
+		t.Errorf("unexpected value %q (found=%v): %v", val, found, err)
+	}
+	u, err := l.URN()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "urn:orders:a%2Fb:vendor:amazon" || u.Raw() != l.String() {
+		t.Errorf("unexpected URN: %s", u.String())
+	}
+}
+
+func TestLazyErrorParity(t *testing.T) {
+	inputs := []string{
+		"invalid",
+		"urn:orders",
+		"urn::1",
+		"urn:or%zzders:1",
+		"urn:orders:1:status",
+		"urn:orders:1:status:",
+		"urn:orders:1:vendor:a%zz",
+		"urn:orders:1:",
+	}
+	for _, s := range inputs {
+		_, parseErr := Parse(s)
+		if parseErr == nil {
+			t.Fatalf("expected Parse error for %q", s)
+		}
+		l := Lazy(s)
+		_, lazyErr := l.AttributePairs()
+		if !reflect.DeepEqual(parseErr, lazyErr) {
+			t.Errorf("%q: Parse error %v, Lazy error %v", s, parseErr, lazyErr)
+		}
+	}
+}
+
+func TestLazyTailErrorOnlyOnAttributes(t *testing.T) {
+	l := Lazy("urn:orders:1:status")
+	if entity, err := l.Entity(); err != nil || entity != "orders" {
+		t.Errorf("Entity should succeed for malformed tail: %q, %v", entity, err)
+	}
+	if id, err := l.ID(); err != nil || id != "1" {
+		t.Errorf("ID should succeed for malformed tail: %q, %v", id, err)
+	}
+	if _, _, err := l.Value("status"); err == nil {
+		t.Error("expected error from Value")
+	}
+	if _, err := l.URN(); err == nil {
+		t.Error("expected error from URN")
+	}
+}
+
+func BenchmarkLazyEntity(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		l := Lazy("urn:orders:1234:vendor:amazon:status:shipped:sku:999")
+		l.Entity()
+	}
+}
+
+func BenchmarkParseEntity(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		u, _ := Parse("urn:orders:1234:vendor:amazon:status:shipped:sku:999")
+		_ = u.Entity
+	}
+}
@@ -0,0 +1,113 @@
+package urn
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ListSeparator separates the items of a list attribute. Inside an item,
+// a comma is written as `\,` and a backslash as `\\`; colons and other
+// reserved characters are percent-encoded with the rest of the value.
+const ListSeparator = ","
+
+// EncodeList returns the attribute value holding items. Items must not be
+// empty.
+func EncodeList(items []string) (string, error) {
+	var b strings.Builder
+	for i, item := range items {
+		if item == "" {
+			return "", fmt.Errorf("Invalid list attribute: item %d is empty", i)
+		}
+		if i > 0 {
+			b.WriteString(ListSeparator)
+		}
+		for j := 0; j < len(item); j++ {
+			if c := item[j]; c == ',' || c == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(item[j])
+		}
+	}
+	return b.String(), nil
+}
+
+// DecodeList splits a value written by EncodeList into its items. A
+// backslash not followed by a comma or backslash is an error.
+func DecodeList(value string) ([]string, error) {
+	var items []string
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\':
+			if i+1 == len(value) || (value[i+1] != ',' && value[i+1] != '\\') {
+				return nil, fmt.Errorf("Invalid list attribute: stray backslash at position %d", i)
+			}
+			i++
+			b.WriteByte(value[i])
+		case ',':
+			items = append(items, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return append(items, b.String()), nil
+}
+
+// SetListAttribute stores values as a list attribute under key, replacing
+// any earlier value. An empty list removes the attribute. Value returns
+// the encoded form; read the items back with ListValue.
+func SetListAttribute(urnStr, key string, values []string) (string, error) {
+	if len(values) == 0 {
+		return RemoveAttribute(urnStr, key)
+	}
+	v, err := EncodeList(values)
+	if err != nil {
+		return "", err
+	}
+	return AddAttribute(urnStr, key, v)
+}
+
+// ListValue returns the items of the list attribute key. Returns the
+// items, whether the attribute was found, and any error.
+func ListValue(urnStr, key string) ([]string, bool, error) {
+	v, found, err := Value(urnStr, key)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	items, err := DecodeList(v)
+	if err != nil {
+		return nil, true, err
+	}
+	return items, true, nil
+}
+
+// AppendListItem adds item to the end of the list attribute key, creating
+// the attribute if it is missing.
+func AppendListItem(urnStr, key, item string) (string, error) {
+	items, _, err := ListValue(urnStr, key)
+	if err != nil {
+		return "", err
+	}
+	return SetListAttribute(urnStr, key, append(items, item))
+}
+
+// RemoveListItem removes every occurrence of item from the list attribute
+// key and reports whether there was one. Removing the last item removes
+// the attribute.
+func RemoveListItem(urnStr, key, item string) (string, bool, error) {
+	items, _, err := ListValue(urnStr, key)
+	if err != nil {
+		return "", false, err
+	}
+	kept := slices.DeleteFunc(slices.Clone(items), func(s string) bool { return s == item })
+	if len(kept) == len(items) {
+		return urnStr, false, nil
+	}
+	s, err := SetListAttribute(urnStr, key, kept)
+	if err != nil {
+		return "", false, err
+	}
+	return s, true, nil
+}
@@ -0,0 +1,93 @@
+package urn
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestListAttributeRoundTrip(t *testing.T) {
+	tests := [][]string{
+		{"red"},
+		{"red", "blue"},
+		{"a,b", `c\d`, "e:f", `\`, ",", "x\\,y"},
+		{"日本", "%41"},
+	}
+	for _, items := range tests {
+		s, err := SetListAttribute("urn:thing:1:k:v", "tags", items)
+		if err != nil {
+			t.Fatalf("SetListAttribute(%q): %v", items, err)
+		}
+		got, found, err := ListValue(s, "tags")
+		if err != nil || !found || !slices.Equal(got, items) {
+			t.Errorf("%q → %s → %q, %v, %v", items, s, got, found, err)
+		}
+		if v, _, _ := Value(s, "k"); v != "v" {
+			t.Errorf("other attribute changed: %q", v)
+		}
+	}
+}
+
+func TestListAttributeEncoding(t *testing.T) {
+	s, err := SetListAttribute("urn:thing:1", "tags", []string{"red", "a,b", "c:d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `urn:thing:1:tags:red%2Ca%5C%2Cb%2Cc%3Ad`; s != want {
+		t.Errorf("SetListAttribute = %q, want %q", s, want)
+	}
+	// Value returns the encoded form unchanged.
+	if v, _, _ := Value(s, "tags"); v != `red,a\,b,c:d` {
+		t.Errorf("Value = %q", v)
+	}
+}
+
+func TestListAttributeEmpty(t *testing.T) {
+	s, err := SetListAttribute("urn:thing:1:tags:red", "tags", nil)
+	if err != nil || s != "urn:thing:1" {
+		t.Errorf("empty list: %q, %v", s, err)
+	}
+	if items, found, err := ListValue(s, "tags"); items != nil || found || err != nil {
+		t.Errorf("ListValue of missing key = %q, %v, %v", items, found, err)
+	}
+	if _, err := SetListAttribute("urn:thing:1", "tags", []string{"a", ""}); err == nil {
+		t.Error("accepted an empty item")
+	}
+}
+
+func TestListItems(t *testing.T) {
+	s, err := AppendListItem("urn:thing:1", "tags", "a,b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ = AppendListItem(s, "tags", "c")
+	s, _ = AppendListItem(s, "tags", "a,b")
+	if items, _, _ := ListValue(s, "tags"); !slices.Equal(items, []string{"a,b", "c", "a,b"}) {
+		t.Errorf("after appends: %q", items)
+	}
+
+	s, removed, err := RemoveListItem(s, "tags", "a,b")
+	if err != nil || !removed {
+		t.Fatalf("RemoveListItem = %v, %v", removed, err)
+	}
+	if items, _, _ := ListValue(s, "tags"); !slices.Equal(items, []string{"c"}) {
+		t.Errorf("after remove: %q", items)
+	}
+	if _, removed, _ := RemoveListItem(s, "tags", "zzz"); removed {
+		t.Error("removed a missing item")
+	}
+	s, _, _ = RemoveListItem(s, "tags", "c")
+	if s != "urn:thing:1" {
+		t.Errorf("removing the last item left %q", s)
+	}
+}
+
+func TestDecodeListMalformed(t *testing.T) {
+	for _, v := range []string{`a\`, `a\b`} {
+		if _, err := DecodeList(v); err == nil {
+			t.Errorf("DecodeList(%q) succeeded", v)
+		}
+	}
+	if _, _, err := ListValue(`urn:thing:1:tags:a%5C`, "tags"); err == nil {
+		t.Error("ListValue accepted a stray backslash")
+	}
+}
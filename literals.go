@@ -0,0 +1,181 @@
+package urn
+
+import (
+	"fmt"
+	"maps"
+)
+
+// LiteralCheck identifies a check run by CheckLiterals.
+type LiteralCheck int
+
+const (
+	// LiteralInvalid reports a literal ParseStrict rejects. The other
+	// checks are skipped for such literals.
+	LiteralInvalid LiteralCheck = iota
+	// LiteralNonCanonical reports a literal that differs from its
+	// canonical form, e.g. by entity case or attribute order.
+	LiteralNonCanonical
+	// LiteralUnknownEntity reports an entity that is neither passed to
+	// WithKnownEntities nor has an IDValidator registered. It only runs
+	// when WithKnownEntities is given.
+	LiteralUnknownEntity
+	// LiteralDeprecatedEntity reports an entity registered as an alias
+	// with SetEntityAliases.
+	LiteralDeprecatedEntity
+)
+
+var literalCheckNames = [...]string{
+	LiteralInvalid:          "invalid",
+	LiteralNonCanonical:     "non-canonical",
+	LiteralUnknownEntity:    "unknown entity",
+	LiteralDeprecatedEntity: "deprecated entity",
+}
+
+func (c LiteralCheck) String() string {
+	if c >= 0 && int(c) < len(literalCheckNames) {
+		return literalCheckNames[c]
+	}
+	return "unknown"
+}
+
+// Severity ranks a LiteralIssue.
+type Severity int
+
+const (
+	// SeverityInfo marks a stylistic note.
+	SeverityInfo Severity = iota
+	// SeverityWarning marks a literal that works but should be changed.
+	SeverityWarning
+	// SeverityError marks a literal that is wrong.
+	SeverityError
+)
+
+var severityNames = [...]string{
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+func (s Severity) String() string {
+	if s >= 0 && int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return "unknown"
+}
+
+// defaultSeverity is the severity of each check unless
+// WithLiteralSeverity changes it.
+var defaultSeverity = [...]Severity{
+	LiteralInvalid:          SeverityError,
+	LiteralNonCanonical:     SeverityWarning,
+	LiteralUnknownEntity:    SeverityError,
+	LiteralDeprecatedEntity: SeverityWarning,
+}
+
+// LiteralIssue is a problem CheckLiterals found in literals[Index].
+type LiteralIssue struct {
+	Index    int
+	Literal  string
+	Check    LiteralCheck
+	Severity Severity
+	Message  string
+	// Err is the ParseStrict error for LiteralInvalid issues.
+	Err error
+}
+
+// WithLiteralChecks limits CheckLiterals to the given checks.
+func WithLiteralChecks(checks ...LiteralCheck) Option {
+	return func(o *options) {
+		o.literalChecks = make(map[LiteralCheck]bool, len(checks))
+		for _, c := range checks {
+			o.literalChecks[c] = true
+		}
+	}
+}
+
+// WithLiteralSeverity makes CheckLiterals report issues of check with
+// severity s.
+func WithLiteralSeverity(check LiteralCheck, s Severity) Option {
+	return func(o *options) {
+		m := maps.Clone(o.literalSeverity)
+		if m == nil {
+			m = make(map[LiteralCheck]Severity)
+		}
+		m[check] = s
+		o.literalSeverity = m
+	}
+}
+
+// WithMinSeverity makes CheckLiterals drop issues below s.
+func WithMinSeverity(s Severity) Option {
+	return func(o *options) {
+		o.minSeverity = s
+	}
+}
+
+// WithKnownEntities enables the LiteralUnknownEntity check of
+// CheckLiterals, accepting the given entities case-insensitively.
+func WithKnownEntities(entities ...string) Option {
+	return func(o *options) {
+		m := maps.Clone(o.knownEntities)
+		if m == nil {
+			m = make(map[string]bool, len(entities))
+		}
+		for _, e := range entities {
+			m[asciiToLower(e)] = true
+		}
+		o.knownEntities = m
+	}
+}
+
+// CheckLiterals checks URN literals, e.g. collected from source code or
+// configuration, and returns their issues in input order. Options are
+// applied on top of those passed to SetDefaults; besides the usual parse
+// options, WithLiteralChecks, WithLiteralSeverity, WithMinSeverity and
+// WithKnownEntities configure the checks. A clean corpus yields nil.
+func CheckLiterals(literals []string, opts ...Option) []LiteralIssue {
+	c := loadConfig()
+	o := c.opts
+	if len(opts) > 0 {
+		o = newOptions(append(append([]Option(nil), c.defaults...), opts...))
+	}
+	var issues []LiteralIssue
+	report := func(i int, check LiteralCheck, err error, format string, args ...any) {
+		if o.literalChecks != nil && !o.literalChecks[check] {
+			return
+		}
+		sev, ok := o.literalSeverity[check]
+		if !ok {
+			sev = defaultSeverity[check]
+		}
+		if sev < o.minSeverity {
+			return
+		}
+		issues = append(issues, LiteralIssue{
+			Index:    i,
+			Literal:  literals[i],
+			Check:    check,
+			Severity: sev,
+			Message:  fmt.Sprintf(format, args...),
+			Err:      err,
+		})
+	}
+	for i, s := range literals {
+		u, err := parseStrict(s, &o, c)
+		if err != nil {
+			report(i, LiteralInvalid, err, "%s", err)
+			continue
+		}
+		if canon := u.Canonical(); canon != s {
+			report(i, LiteralNonCanonical, nil, "not canonical; use %q", canon)
+		}
+		entity := asciiToLower(u.Entity)
+		if o.knownEntities != nil && !o.knownEntities[entity] && c.idValidator(entity) == nil {
+			report(i, LiteralUnknownEntity, nil, "unknown entity %q", u.Entity)
+		}
+		if target, ok := c.entityAliases[entity]; ok {
+			report(i, LiteralDeprecatedEntity, nil, "entity %q is a deprecated alias of %q", u.Entity, target)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,101 @@
+package urn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckLiterals(t *testing.T) {
+	if err := SetEntityAliases(map[string]string{"customer": "customers"}); err != nil {
+		t.Fatal(err)
+	}
+	RegisterIDValidator("order", NumericValidator)
+	t.Cleanup(func() {
+		SetEntityAliases(nil)
+		RegisterIDValidator("order", nil)
+	})
+
+	corpus := []string{
+		"urn:user:1",         // clean
+		"urn:user",           // invalid
+		"urn:User:1:b:2:a:1", // non-canonical
+		"urn:customer:7",     // deprecated and unknown
+		"urn:order:42",       // known through its IDValidator
+		"urn:order:x",        // rejected by the IDValidator
+		"urn:widget:1:k:v",   // unknown
+	}
+	type issue struct {
+		index int
+		check LiteralCheck
+		sev   Severity
+	}
+	want := []issue{
+		{1, LiteralInvalid, SeverityError},
+		{2, LiteralNonCanonical, SeverityWarning},
+		{3, LiteralUnknownEntity, SeverityError},
+		{3, LiteralDeprecatedEntity, SeverityWarning},
+		{5, LiteralInvalid, SeverityError},
+		{6, LiteralUnknownEntity, SeverityError},
+	}
+	got := CheckLiterals(corpus, WithKnownEntities("USER"))
+	if len(got) != len(want) {
+		t.Fatalf("got %d issues: %+v", len(got), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Index != w.index || g.Check != w.check || g.Severity != w.sev || g.Literal != corpus[w.index] || g.Message == "" {
+			t.Errorf("issue %d = %+v, want %+v", i, g, w)
+		}
+	}
+	var ue *InvalidURNError
+	if !errors.As(got[0].Err, &ue) || ue.Kind != KindMissingComponent {
+		t.Errorf("invalid literal error = %v", got[0].Err)
+	}
+	if got[1].Message != `not canonical; use "urn:user:1:a:1:b:2"` {
+		t.Errorf("non-canonical message = %q", got[1].Message)
+	}
+}
+
+func TestCheckLiteralsConfiguration(t *testing.T) {
+	corpus := []string{"urn:user", "urn:User:1", "urn:widget:1"}
+
+	// Without WithKnownEntities the unknown-entity check does not run.
+	if got := CheckLiterals(corpus); len(got) != 2 {
+		t.Errorf("default checks: %+v", got)
+	}
+
+	got := CheckLiterals(corpus, WithLiteralChecks(LiteralNonCanonical))
+	if len(got) != 1 || got[0].Check != LiteralNonCanonical {
+		t.Errorf("only non-canonical: %+v", got)
+	}
+
+	got = CheckLiterals(corpus, WithMinSeverity(SeverityError))
+	if len(got) != 1 || got[0].Check != LiteralInvalid {
+		t.Errorf("errors only: %+v", got)
+	}
+
+	got = CheckLiterals(corpus,
+		WithLiteralSeverity(LiteralNonCanonical, SeverityError),
+		WithMinSeverity(SeverityError))
+	if len(got) != 2 || got[1].Check != LiteralNonCanonical || got[1].Severity != SeverityError {
+		t.Errorf("raised severity: %+v", got)
+	}
+
+	got = CheckLiterals(corpus, WithKnownEntities("user"), WithKnownEntities("widget"))
+	if len(got) != 2 {
+		t.Errorf("known entities accumulate: %+v", got)
+	}
+
+	if got := CheckLiterals([]string{"urn:user:1"}); got != nil {
+		t.Errorf("clean corpus: %+v", got)
+	}
+}
+
+func TestLiteralNames(t *testing.T) {
+	if LiteralDeprecatedEntity.String() != "deprecated entity" || SeverityWarning.String() != "warning" {
+		t.Error("names")
+	}
+	if LiteralCheck(99).String() != "unknown" || Severity(-1).String() != "unknown" {
+		t.Error("out-of-range names")
+	}
+}
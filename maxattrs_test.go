@@ -0,0 +1,165 @@
+package urn
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMaxAttributesParsed(t *testing.T) {
+	p := NewProcessor(MaxAttributesParsed(2))
+	in := "urn:job:1:a:1:b:2:diag%2fx:%2e%2e:c:3:d:%41"
+	u, err := p.Parse(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.AttributePairs(); len(got) != 2 || got[1] != (Attribute{Key: "b", Value: "2"}) {
+		t.Errorf("parsed attributes = %v", got)
+	}
+	// The tail is re-emitted byte for byte, lowercase escapes included.
+	if s := u.String(); s != in {
+		t.Errorf("String = %q, want %q", s, in)
+	}
+	if s, err := p.Format(u); err != nil || s != in {
+		t.Errorf("Format = %q, %v", s, err)
+	}
+	if b, err := u.MarshalText(); err != nil || string(b) != in {
+		t.Errorf("MarshalText = %q, %v", b, err)
+	}
+
+	// Keys beyond the window are reported not found.
+	if _, found, err := p.Value(in, "c"); found || err != nil {
+		t.Errorf("Value(c) found = %v, %v", found, err)
+	}
+	if v, found, _ := p.Value(in, "a"); !found || v != "1" {
+		t.Errorf("Value(a) = %q, %v", v, found)
+	}
+
+	// Edits keep the tail.
+	s, err := p.AddAttribute(in, "a", "9")
+	if err != nil || s != "urn:job:1:a:9:b:2:diag%2fx:%2e%2e:c:3:d:%41" {
+		t.Errorf("AddAttribute = %q, %v", s, err)
+	}
+	s, err = p.RemoveAttribute(in, "b")
+	if err != nil || s != "urn:job:1:a:1:diag%2fx:%2e%2e:c:3:d:%41" {
+		t.Errorf("RemoveAttribute = %q, %v", s, err)
+	}
+
+	// Without the option every attribute is parsed and re-encoded.
+	full, _ := Parse(in)
+	if len(full.AttributePairs()) != 5 || full.String() == in {
+		t.Errorf("full parse: %v → %q", full.AttributePairs(), full.String())
+	}
+	if full.Equal(u) {
+		t.Error("truncated and full parses compare Equal")
+	}
+}
+
+func TestMaxAttributesParsedStructure(t *testing.T) {
+	p := NewProcessor(MaxAttributesParsed(1))
+	// The tail is still counted and paired.
+	if _, err := p.Parse("urn:job:1:a:1:b"); err == nil {
+		t.Error("accepted an unpaired key in the tail")
+	}
+	if _, err := p.Parse("urn:job:1" + strings.Repeat(":k:v", 40)); err == nil {
+		t.Error("accepted a tail over the segment limit")
+	}
+	// A window at least as large as the attribute count leaves no tail.
+	u, err := NewProcessor(MaxAttributesParsed(5)).Parse("urn:job:1:a:%2f")
+	if err != nil || u.String() != "urn:job:1:a:%2F" {
+		t.Errorf("no tail: %q, %v", u.String(), err)
+	}
+}
+
+func TestMaxAttributesParsedTrailingFlag(t *testing.T) {
+	p := NewProcessor(MaxAttributesParsed(1), TrailingKeyAsFlag())
+	in := "urn:job:1:a:1:b:2:urgent"
+	u, err := p.Parse(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.HasTrailingFlag() || u.String() != in {
+		t.Errorf("flag in the tail: %v, %q", u.HasTrailingFlag(), u.String())
+	}
+}
+
+func TestMaxAttributesParsedLength(t *testing.T) {
+	p := NewProcessor(MaxAttributesParsed(1))
+	in := "urn:job:1:a:1:b:" + strings.Repeat("x", MaxURNLength-len("urn:job:1:a:1:b:"))
+	if _, err := p.AddAttribute(in, "a", "22"); err == nil {
+		t.Error("AddAttribute exceeded the length limit through the tail")
+	}
+}
+
+func BenchmarkParseMaxAttributes(b *testing.B) {
+	in := "urn:job:1:id:7" + strings.Repeat(":diag:x%3Ay", 25)
+	p := NewProcessor(MaxAttributesParsed(1))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := p.Parse(in); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMaxAttributesParsedTailIsSigned(t *testing.T) {
+	p := NewProcessor(MaxAttributesParsed(1))
+	admin, err := p.Parse("urn:doc:1:k:v:role:admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	guest, err := p.Parse("urn:doc:1:k:v:role:guest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := admin.SigningBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := guest.SigningBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) == string(g) {
+		t.Error("signing bytes do not cover the unparsed tail")
+	}
+	full, _ := Parse("urn:doc:1:k:v:role:admin")
+	if want, _ := full.SigningBytes(); string(a) != string(want) {
+		t.Errorf("signing bytes depend on MaxAttributesParsed: %x, want %x", a, want)
+	}
+
+	bad, err := p.Parse("urn:doc:1:k:v:role:%zz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bad.SigningBytes(); err == nil {
+		t.Error("expected an error for a tail that does not decode")
+	}
+}
+
+func TestMaxAttributesParsedTailIsEncoded(t *testing.T) {
+	p := NewProcessor(MaxAttributesParsed(1))
+	u, err := p.Parse("urn:doc:1:k:v:role:ad%3Amin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Attribute{{Key: "k", Value: "v"}, {Key: "role", Value: "ad:min"}}
+	if got, err := u.AllAttributePairs(); err != nil || !slices.Equal(got, want) {
+		t.Errorf("AllAttributePairs = %v, %v", got, err)
+	}
+	if b, err := u.MarshalOrderedJSON(); err != nil || !strings.Contains(string(b), `["role","ad:min"]`) {
+		t.Errorf("MarshalOrderedJSON = %s, %v", b, err)
+	}
+	if b, err := u.MarshalJSONOrderedObject(); err != nil || !strings.Contains(string(b), `"role":"ad:min"`) {
+		t.Errorf("MarshalJSONOrderedObject = %s, %v", b, err)
+	}
+
+	other, _ := Parse("urn:doc:1:k:v:size:L")
+	m, err := u.Merge(other, PreferA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := m.String(); s != "urn:doc:1:k:v:role:ad%3Amin:size:L" {
+		t.Errorf("Merge = %s", s)
+	}
+}
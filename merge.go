@@ -0,0 +1,96 @@
+package urn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MergePolicy resolves a merge conflict: key has value aVal in the first
+// URN and a different value bVal in the second. It returns the value to
+// keep, or an error to abort the merge.
+type MergePolicy func(key, aVal, bVal string) (string, error)
+
+// ErrMergeConflict is wrapped by the error ErrorOnConflict returns.
+var ErrMergeConflict = errors.New("conflicting attribute values")
+
+var (
+	// PreferA keeps the first URN's value.
+	PreferA MergePolicy = func(_, aVal, _ string) (string, error) { return aVal, nil }
+	// PreferB keeps the second URN's value.
+	PreferB MergePolicy = func(_, _, bVal string) (string, error) { return bVal, nil }
+	// ErrorOnConflict fails the merge with an error wrapping
+	// ErrMergeConflict.
+	ErrorOnConflict MergePolicy = func(key, aVal, bVal string) (string, error) {
+		return "", fmt.Errorf("%w: %s is %q and %q", ErrMergeConflict, key, aVal, bVal)
+	}
+)
+
+// Merge unions the attributes of two URN strings for the same resource.
+// See (*URN).Merge.
+func Merge(a, b string, policy MergePolicy) (string, error) {
+	ua, err := Parse(a)
+	if err != nil {
+		return "", err
+	}
+	ub, err := Parse(b)
+	if err != nil {
+		return "", err
+	}
+	m, err := ua.Merge(ub, policy)
+	if err != nil {
+		return "", err
+	}
+	return compose(m.Entity, m.ID, m.attributes)
+}
+
+// Merge returns a URN with the attributes of u and other, which must
+// identify the same resource (see SameResourceAs). The result keeps u's
+// entity, ID and attributes in their order, with the keys only other has
+// appended in its order. A key whose values differ is resolved by policy;
+// equal values are not a conflict. Attributes left unparsed under
+// MaxAttributesParsed are decoded and merged like the others. Valid
+// checksums are dropped, since they no longer match the merged attributes.
+func (u *URN) Merge(other *URN, policy MergePolicy) (*URN, error) {
+	if !u.SameResourceAs(other) {
+		return nil, fmt.Errorf("Cannot merge URNs: %s:%s and %s:%s are different resources", u.Entity, u.ID, other.Entity, other.ID)
+	}
+	a, err := u.allUserAttributes()
+	if err != nil {
+		return nil, err
+	}
+	b, err := other.allUserAttributes()
+	if err != nil {
+		return nil, err
+	}
+	inA := make(map[string]bool, len(a))
+	for _, p := range a {
+		inA[p.Key] = true
+	}
+	inB := make(map[string]string, len(b))
+	for _, p := range b {
+		if _, ok := inB[p.Key]; !ok {
+			inB[p.Key] = p.Value
+		}
+	}
+
+	merged := make([]Attribute, 0, len(a)+len(b))
+	for _, p := range a {
+		if bVal, ok := inB[p.Key]; ok && bVal != p.Value {
+			v, err := policy(p.Key, p.Value, bVal)
+			if err != nil {
+				return nil, err
+			}
+			p.Value = v
+		}
+		merged = append(merged, p)
+	}
+	for _, p := range b {
+		if !inA[p.Key] {
+			merged = append(merged, p)
+		}
+	}
+	if err := validatePairs(nil, merged); err != nil {
+		return nil, err
+	}
+	return &URN{Entity: u.Entity, ID: u.ID, attributes: merged, parsedEntity: u.parsedEntity}, nil
+}
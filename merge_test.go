@@ -0,0 +1,84 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMergePolicies(t *testing.T) {
+	a := "urn:user:1:name:ann:role:admin"
+	b := "urn:USER:1:team:x:role:viewer:name:ann"
+	tests := []struct {
+		name   string
+		policy MergePolicy
+		want   string
+	}{
+		{"PreferA", PreferA, "urn:user:1:name:ann:role:admin:team:x"},
+		{"PreferB", PreferB, "urn:user:1:name:ann:role:viewer:team:x"},
+		{"callback", func(key, aVal, bVal string) (string, error) {
+			return aVal + "+" + bVal, nil
+		}, "urn:user:1:name:ann:role:admin+viewer:team:x"},
+	}
+	for _, tt := range tests {
+		got, err := Merge(a, b, tt.policy)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: Merge = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestMergeErrorOnConflict(t *testing.T) {
+	_, err := Merge("urn:user:1:role:admin", "urn:user:1:role:viewer", ErrorOnConflict)
+	if !errors.Is(err, ErrMergeConflict) || !strings.Contains(err.Error(), "role") {
+		t.Errorf("err = %v", err)
+	}
+	// Equal values are not a conflict.
+	got, err := Merge("urn:user:1:role:admin", "urn:user:1:role:admin:k:v", ErrorOnConflict)
+	if err != nil || got != "urn:user:1:role:admin:k:v" {
+		t.Errorf("Merge = %q, %v", got, err)
+	}
+}
+
+func TestMergeCallbackError(t *testing.T) {
+	boom := errors.New("boom")
+	_, err := Merge("urn:user:1:k:a", "urn:user:1:k:b", func(string, string, string) (string, error) {
+		return "", boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("err = %v", err)
+	}
+	_, err = Merge("urn:user:1:k:a", "urn:user:1:k:b", func(string, string, string) (string, error) {
+		return "", nil
+	})
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Kind != KindEmptyAttribute {
+		t.Errorf("empty resolved value: err = %v", err)
+	}
+}
+
+func TestMergeMismatch(t *testing.T) {
+	for _, b := range []string{"urn:user:2:k:v", "urn:order:1:k:v"} {
+		if _, err := Merge("urn:user:1", b, PreferA); err == nil {
+			t.Errorf("Merge with %s succeeded", b)
+		}
+	}
+	if _, err := Merge("bad", "urn:user:1", PreferA); err == nil {
+		t.Error("Merge accepted an invalid URN")
+	}
+}
+
+func TestURNMerge(t *testing.T) {
+	a, _ := Parse("urn:user:1:k:a")
+	b, _ := Parse("urn:user:1:j:b")
+	m, err := a.Merge(b, PreferA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.String(); got != "urn:user:1:k:a:j:b" {
+		t.Errorf("Merge = %q", got)
+	}
+	if a.String() != "urn:user:1:k:a" {
+		t.Error("Merge modified its receiver")
+	}
+}
@@ -0,0 +1,59 @@
+package urn
+
+import (
+	"regexp"
+	"strings"
+)
+
+var entityPartRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+var namespacedProcessor = &Processor{opts: options{allowDots: true}}
+
+// EntityParts returns the dot-separated parts of the URN's entity, e.g.
+// ["shop", "order"] for "urn:shop.order:123". An entity without dots
+// yields a single part; empty parts are an error.
+func EntityParts(urnStr string) ([]string, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(u.Entity, ".")
+	for _, p := range parts {
+		if p == "" {
+			seg, off := segmentAt(urnStr, 0)
+			return nil, &InvalidURNError{
+				Kind:    KindInvalidEntity,
+				Reason:  ReasonEmptyEntityPart,
+				Offset:  off,
+				Segment: seg,
+				Entity:  u.Entity,
+			}
+		}
+	}
+	return parts, nil
+}
+
+// ComposeNamespaced composes a URN whose entity is parts joined with dots,
+// validating each part individually. The result is accepted by a
+// Processor configured with EntityAllowDots.
+func ComposeNamespaced(parts []string, id string, attrs ...map[string]string) (string, error) {
+	if len(parts) == 0 {
+		return "", &InvalidURNError{
+			Kind: KindEmptyComponent,
+			Op:   OpCompose,
+		}
+	}
+	for i, p := range parts {
+		if !entityPartRegex.MatchString(p) {
+			return "", &InvalidURNError{
+				Kind:    KindInvalidEntity,
+				Reason:  ReasonEntityPart,
+				Op:      OpCompose,
+				Segment: p,
+				Value:   p,
+				Index:   i,
+			}
+		}
+	}
+	return namespacedProcessor.Compose(strings.Join(parts, "."), id, attrs...)
+}
@@ -0,0 +1,63 @@
+package urn
+
+import "testing"
+
+func TestEntityAllowDots(t *testing.T) {
+	p := NewProcessor(EntityAllowDots())
+	tests := []struct {
+		in    string
+		valid bool
+	}{
+		{"urn:a.b.c:1", true},
+		{"urn:shop.order:123", true},
+		{"urn:orders:1", true},
+		{"urn:.a:1", false},
+		{"urn:a.:1", false},
+		{"urn:a..b:1", false},
+		{"urn:a.-b:1", false},
+	}
+	for _, tt := range tests {
+		if got := p.IsValid(tt.in); got != tt.valid {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.in, got, tt.valid)
+		}
+	}
+	if IsValid("urn:shop.order:123") {
+		t.Error("dots should be rejected without EntityAllowDots")
+	}
+	if _, err := p.Compose("shop.order", "1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEntityParts(t *testing.T) {
+	parts, err := EntityParts("urn:a.b.c:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 3 || parts[0] != "a" || parts[1] != "b" || parts[2] != "c" {
+		t.Errorf("got %v", parts)
+	}
+	if parts, _ := EntityParts("urn:orders:1"); len(parts) != 1 || parts[0] != "orders" {
+		t.Errorf("got %v", parts)
+	}
+	for _, in := range []string{"urn:.a:1", "urn:a.:1", "urn:a..b:1"} {
+		if _, err := EntityParts(in); err == nil {
+			t.Errorf("EntityParts(%q): expected error", in)
+		}
+	}
+}
+
+func TestComposeNamespaced(t *testing.T) {
+	s, err := ComposeNamespaced([]string{"shop", "order"}, "123", map[string]string{"k": "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "urn:shop.order:123:k:v" {
+		t.Errorf("got %s", s)
+	}
+	for _, parts := range [][]string{nil, {"shop", ""}, {"a.b"}, {"-x"}} {
+		if _, err := ComposeNamespaced(parts, "1"); err == nil {
+			t.Errorf("ComposeNamespaced(%q): expected error", parts)
+		}
+	}
+}
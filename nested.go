@@ -0,0 +1,33 @@
+package urn
+
+// SetURNAttribute stores a nested URN as the value of an attribute. The
+// nested URN is escaped as a single segment, so its colons survive the
+// round trip instead of being read as additional attribute pairs.
+func SetURNAttribute(urnStr, key string, nested *URN) (string, error) {
+	if nested == nil {
+		return "", &InvalidURNError{
+			Kind:   KindMissingComponent,
+			Reason: ReasonNilNested,
+			Op:     OpSetAttribute,
+		}
+	}
+	value, err := composeURN("urn", nested)
+	if err != nil {
+		return "", err
+	}
+	return AddAttribute(urnStr, key, value)
+}
+
+// URNValue retrieves an attribute value and parses it as a nested URN.
+// Returns the nested URN, whether the attribute was found, and any error.
+func URNValue(urnStr, key string) (*URN, bool, error) {
+	v, found, err := Value(urnStr, key)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	nested, err := Parse(v)
+	if err != nil {
+		return nil, true, err
+	}
+	return nested, true, nil
+}
@@ -0,0 +1,88 @@
+package urn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetURNAttribute(t *testing.T) {
+	folder, err := Parse("urn:folder:3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := SetURNAttribute("urn:doc:9", "parent", folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc != "urn:doc:9:parent:urn%3Afolder%3A3" {
+		t.Errorf("unexpected nested URN: %s", doc)
+	}
+	parent, found, err := URNValue(doc, "parent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || parent.String() != "urn:folder:3" {
+		t.Errorf("expected urn:folder:3, got %v (found=%v)", parent, found)
+	}
+}
+
+func TestSetURNAttributeTwoLevels(t *testing.T) {
+	drive, _ := Parse("urn:drive:1:owner:jane doe")
+	folder, err := SetURNAttribute("urn:folder:3", "drive", drive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	folderURN, _ := Parse(folder)
+	doc, err := SetURNAttribute("urn:doc:9:status:draft", "parent", folderURN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(doc, ":") != 6 {
+		t.Errorf("nested colons leaked into outer URN: %s", doc)
+	}
+
+	parent, found, err := URNValue(doc, "parent")
+	if err != nil || !found {
+		t.Fatalf("expected parent, found=%v err=%v", found, err)
+	}
+	if parent.String() != folder {
+		t.Errorf("expected %s, got %s", folder, parent.String())
+	}
+	inner, found, err := URNValue(parent.String(), "drive")
+	if err != nil || !found {
+		t.Fatalf("expected drive, found=%v err=%v", found, err)
+	}
+	if inner.Entity != "drive" || inner.ID != "1" || inner.Attributes()["owner"] != "jane doe" {
+		t.Errorf("unexpected inner URN: %+v", inner)
+	}
+	status, _, _ := Value(doc, "status")
+	if status != "draft" {
+		t.Errorf("expected draft, got %s", status)
+	}
+}
+
+func TestURNValueMissing(t *testing.T) {
+	nested, found, err := URNValue("urn:doc:9", "parent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found || nested != nil {
+		t.Error("expected not found")
+	}
+}
+
+func TestURNValueNotAURN(t *testing.T) {
+	_, found, err := URNValue("urn:doc:9:parent:folder", "parent")
+	if err == nil {
+		t.Fatal("expected error for non-URN value")
+	}
+	if !found {
+		t.Error("expected found to be true")
+	}
+}
+
+func TestSetURNAttributeNil(t *testing.T) {
+	if _, err := SetURNAttribute("urn:doc:9", "parent", nil); err == nil {
+		t.Fatal("expected error for nil nested URN")
+	}
+}
@@ -0,0 +1,73 @@
+package urn
+
+import (
+	"strings"
+	"testing"
+)
+
+// untrustedEntryPoints runs every function that reads raw input on s. The
+// results are ignored: only panics matter.
+func untrustedEntryPoints(s string) {
+	filter, _ := CompileKeyFilter([]string{"k", "a/b"}, []string{"x"})
+	_, _ = Parse(s)
+	_, _ = ParseStrict(s, RejectControlChars())
+	_, _, _ = ParseAudit(s)
+	_, _ = ParseAny(s)
+	_, _ = NewProcessor(WithQuoting()).Parse(s)
+	_, _ = NewProcessor(TrailingKeyAsFlag(), MaxAttributesParsed(1)).Parse(s)
+	_, _ = Normalize(s)
+	_, _ = Canonical(s)
+	_, _ = filter.Match(s)
+	_, _ = AppendAttributeRaw(s, "k", "v")
+	for n := range 4 {
+		segmentAt(s, n)
+	}
+	for i := -1; i <= len(s)+1; i++ {
+		nextSegment(s, i)
+	}
+	sc := NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+	}
+}
+
+func TestEveryPrefixOfValidURN(t *testing.T) {
+	for _, full := range []string{
+		"urn:orders:12%3A34:vendor:amazon:a%2Fb:x%20y:flag",
+		`urn:job:1:at:"2024-01-02T03:04:05Z"`,
+		"URN:Orders.Eu:%F0%9F%98%80",
+	} {
+		for i := 0; i <= len(full); i++ {
+			untrustedEntryPoints(full[:i])
+		}
+	}
+}
+
+func TestShortInputsExhaustive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("enumerates every short input")
+	}
+	const alphabet = "urn:%a1"
+	buf := make([]byte, 0, 6)
+	var walk func()
+	walk = func() {
+		untrustedEntryPoints(string(buf))
+		if len(buf) == cap(buf) {
+			return
+		}
+		for i := range len(alphabet) {
+			buf = append(buf, alphabet[i])
+			walk()
+			buf = buf[:len(buf)-1]
+		}
+	}
+	walk()
+}
+
+func FuzzNoPanic(f *testing.F) {
+	for _, seed := range []string{"", "u", "ur", "urn", "urn:", "urn:a", "urn:a:", "urn:%", "urn::", ":::::", "urn:a:%", "%%%%%%"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		untrustedEntryPoints(s)
+	})
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package urn
+
+const raceEnabled = false
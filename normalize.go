@@ -0,0 +1,92 @@
+package urn
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Normalizer is a custom normalization step. It changes u in place, for
+// example by assigning u.ID or replacing *u with the result of
+// WithAttribute, or returns an error to abort normalization.
+type Normalizer func(u *URN) error
+
+// RegisterNormalizer adds n to the steps Normalize and Canonical run after
+// the built-in ones. Normalizers run in the order they were registered;
+// the first error aborts normalization. Use it with NewProcessor, With or
+// SetDefaults; a Writer runs the normalizers of its options too.
+func RegisterNormalizer(n Normalizer) Option {
+	return func(o *options) {
+		o.normalizers = append(slices.Clip(o.normalizers), n)
+	}
+}
+
+// LowercaseAttrValue returns a Normalizer that lowercases the values of
+// every attribute with the key.
+func LowercaseAttrValue(key string) Normalizer {
+	return func(u *URN) error {
+		for i, p := range u.attributes {
+			if p.Key == key {
+				if lower := strings.ToLower(p.Value); lower != p.Value {
+					u.ownAttributes(0)
+					u.attributes[i].Value = lower
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// LowercaseIDForEntity returns a Normalizer that lowercases the ID of URNs
+// whose entity matches entity, compared ASCII case-insensitively.
+func LowercaseIDForEntity(entity string) Normalizer {
+	return func(u *URN) error {
+		if asciiEqualFold(u.Entity, entity) {
+			u.ID = strings.ToLower(u.ID)
+		}
+		return nil
+	}
+}
+
+// normalize lowercases the entity of u and runs the registered
+// normalizers.
+func (o *options) normalize(u *URN) error {
+	u.Entity = asciiToLower(u.Entity)
+	for _, n := range o.normalizers {
+		if err := n(u); err != nil {
+			return fmt.Errorf("Cannot normalize URN: %w", err)
+		}
+	}
+	return nil
+}
+
+// Normalize is like the package-level Normalize, under the Processor's
+// options, running its registered normalizers.
+func (p *Processor) Normalize(urnStr string) (s string, err error) {
+	if debugGuards {
+		defer recoverPanic("Normalize", &err)
+	}
+	o, _ := p.snapshot()
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return "", err
+	}
+	if err := o.normalize(u); err != nil {
+		return "", err
+	}
+	return composeURN("urn", u)
+}
+
+// Canonical is like the package-level Canonical, under the Processor's
+// options, running its registered normalizers before sorting.
+func (p *Processor) Canonical(urnStr string) (string, error) {
+	o, _ := p.snapshot()
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return "", err
+	}
+	if err := o.normalize(u); err != nil {
+		return "", err
+	}
+	return u.Canonical(), nil
+}
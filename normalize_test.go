@@ -0,0 +1,92 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizersChain(t *testing.T) {
+	p := NewProcessor(
+		RegisterNormalizer(LowercaseAttrValue("region")),
+		RegisterNormalizer(LowercaseIDForEntity("email")),
+	)
+	got, err := p.Normalize("urn:Email:Ada@Example.COM:region:EU-West:tier:Gold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "urn:email:ada@example.com:region:eu-west:tier:Gold"; got != want {
+		t.Errorf("Normalize = %q, want %q", got, want)
+	}
+	got, err = p.Canonical("urn:Email:X:tier:Gold:region:EU")
+	if err != nil || got != "urn:email:x:region:eu:tier:Gold" {
+		t.Errorf("Canonical = %q, %v", got, err)
+	}
+	if got, _ := p.Normalize("urn:user:Ada:region:EU"); got != "urn:user:Ada:region:eu" {
+		t.Errorf("Normalize(other entity) = %q, want ID kept", got)
+	}
+	if got, _ := Normalize("urn:email:Ada:region:EU"); got != "urn:email:Ada:region:EU" {
+		t.Errorf("package Normalize = %q, want no normalizers", got)
+	}
+}
+
+func TestNormalizersRunInOrder(t *testing.T) {
+	var order []string
+	step := func(name string) Normalizer {
+		return func(u *URN) error {
+			order = append(order, name)
+			u.ID += name
+			return nil
+		}
+	}
+	p := NewProcessor(RegisterNormalizer(step("a"))).With(RegisterNormalizer(step("b")))
+	if got, _ := p.Normalize("urn:orders:1"); got != "urn:orders:1ab" {
+		t.Errorf("Normalize = %q", got)
+	}
+	if strings.Join(order, "") != "ab" {
+		t.Errorf("order = %v", order)
+	}
+}
+
+func TestNormalizerError(t *testing.T) {
+	errBad := errors.New("region not allowed")
+	reject := func(u *URN) error {
+		if u.GetOr("region", "") == "xx" {
+			return errBad
+		}
+		return nil
+	}
+	ran := false
+	after := func(*URN) error { ran = true; return nil }
+	p := NewProcessor(RegisterNormalizer(reject), RegisterNormalizer(after))
+	if _, err := p.Normalize("urn:orders:1:region:xx"); !errors.Is(err, errBad) {
+		t.Errorf("Normalize = %v, want errBad", err)
+	}
+	if _, err := p.Canonical("urn:orders:1:region:xx"); !errors.Is(err, errBad) {
+		t.Errorf("Canonical = %v, want errBad", err)
+	}
+	if ran {
+		t.Error("normalizer after the failing one ran")
+	}
+}
+
+func TestLowercaseAttrValueLeavesSharedAttributes(t *testing.T) {
+	u, _ := Parse("urn:orders:1:region:EU")
+	c := u.Clone()
+	if err := LowercaseAttrValue("region")(c); err != nil {
+		t.Fatal(err)
+	}
+	if u.GetOr("region", "") != "EU" || c.GetOr("region", "") != "eu" {
+		t.Errorf("original %q, clone %q", u.GetOr("region", ""), c.GetOr("region", ""))
+	}
+}
+
+func TestWriterRunsNormalizers(t *testing.T) {
+	var buf strings.Builder
+	w := NewWriter(&buf, RegisterNormalizer(LowercaseAttrValue("region")))
+	w.Write("urn:orders:1:region:EU")
+	stats, _ := w.Close()
+	if buf.String() != "urn:orders:1:region:eu\n" || stats.Repaired != 1 {
+		t.Errorf("output %q, stats %+v", buf.String(), stats)
+	}
+}
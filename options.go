@@ -0,0 +1,211 @@
+package urn
+
+import "strings"
+
+// Option configures optional parsing behavior, either per call or on a
+// Processor.
+type Option func(*options)
+
+// Option functions must replace rather than mutate the maps in options:
+// (*Processor).With copies options shallowly.
+type options struct {
+	rejectControlChars bool
+	discardRaw         bool
+	foldKeys           bool
+	schemeAliases      map[string]bool
+	outputScheme       string
+	maxSegments        int
+	allowDots          bool
+	trailingFlag       bool
+	allowDeprecated    bool
+	quoting            bool
+	expiryKey          string
+	expiryFormat       ExpiryFormat
+	literalChecks      map[LiteralCheck]bool
+	literalSeverity    map[LiteralCheck]Severity
+	minSeverity        Severity
+	knownEntities      map[string]bool
+	maxAttrs           int
+	binaryComponents   bool
+	copyRegistries     bool
+	rejectNonCanonical bool
+	normalizers        []Normalizer
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// keyEqual reports whether two attribute keys match under the options.
+func (o *options) keyEqual(a, b string) bool {
+	if o.foldKeys {
+		return asciiEqualFold(a, b)
+	}
+	return a == b
+}
+
+// schemeEnd returns the offset just past the scheme and its ':' separator,
+// or -1 when urnStr does not start with "urn:" or an accepted alias.
+func (o *options) schemeEnd(urnStr string) int {
+	if len(urnStr) >= 4 && asciiEqualFold(urnStr[:4], "urn:") {
+		return 4
+	}
+	if o.schemeAliases != nil {
+		if i := strings.IndexByte(urnStr, ':'); i > 0 && o.schemeAliases[asciiToLower(urnStr[:i])] {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// validEntity reports whether entity is well-formed under the options.
+func (o *options) validEntity(entity string) bool {
+	if o.allowDots {
+		return len(entity) >= 2 && len(entity) <= 32 && dottedEntityRegex.MatchString(entity)
+	}
+	return entityRegex.MatchString(entity)
+}
+
+// segmentLimit returns the maximum number of segments parsing accepts.
+func (o *options) segmentLimit() int {
+	if o.maxSegments > 0 {
+		return o.maxSegments
+	}
+	return DefaultMaxSegments
+}
+
+// scheme returns the scheme to emit when composing.
+func (o *options) scheme() string {
+	if o.outputScheme != "" {
+		return o.outputScheme
+	}
+	return "urn"
+}
+
+// RejectControlChars makes ParseStrict reject URNs whose decoded components
+// contain control characters (0x00–0x1F, 0x7F), even when they were
+// correctly percent-encoded.
+func RejectControlChars() Option {
+	return func(o *options) {
+		o.rejectControlChars = true
+	}
+}
+
+// AllowBinaryComponents makes ParseStrict accept IDs, attribute keys and
+// values that decode to invalid UTF-8, such as binary IDs written as
+// percent-escapes. The decoded components hold the raw bytes; the JSON
+// document forms cannot carry them and report ErrInvalidUTF8, while
+// String and MarshalText re-escape them losslessly.
+func AllowBinaryComponents() Option {
+	return func(o *options) {
+		o.binaryComponents = true
+	}
+}
+
+// CopyRegistries gives a Processor created by NewProcessor or With its own
+// copy of the package registries (see (*Processor).With), so later calls
+// such as RegisterIDValidator or SetReservedKeys do not affect it.
+func CopyRegistries() Option {
+	return func(o *options) {
+		o.copyRegistries = true
+	}
+}
+
+// ShareRegistries undoes CopyRegistries: the Processor reads the live
+// package registries. It is the default.
+func ShareRegistries() Option {
+	return func(o *options) {
+		o.copyRegistries = false
+	}
+}
+
+// DiscardRaw stops parsed URNs from retaining their input string, so Raw
+// returns "". Useful for bulk jobs that keep many URNs in memory.
+func DiscardRaw() Option {
+	return func(o *options) {
+		o.discardRaw = true
+	}
+}
+
+// CaseInsensitiveKeys makes attribute lookups, overwrites and removals match
+// keys ASCII case-insensitively. Overwrites keep the casing that was seen
+// first.
+func CaseInsensitiveKeys() Option {
+	return func(o *options) {
+		o.foldKeys = true
+	}
+}
+
+// WithSchemeAliases makes parsing accept additional schemes as shorthand for
+// "urn", e.g. map[string]string{"u": "urn"} accepts "u:orders:1". Only
+// aliases targeting "urn" are honored; schemes match case-insensitively.
+// Parsed URNs are normalized to the "urn" scheme.
+func WithSchemeAliases(aliases map[string]string) Option {
+	return func(o *options) {
+		o.schemeAliases = make(map[string]bool, len(aliases))
+		for alias, target := range aliases {
+			if asciiEqualFold(target, "urn") && alias != "" {
+				o.schemeAliases[asciiToLower(alias)] = true
+			}
+		}
+	}
+}
+
+// WithOutputScheme makes a Processor emit scheme instead of "urn" from its
+// composing methods. Length limits still apply to the "urn:" form.
+func WithOutputScheme(scheme string) Option {
+	return func(o *options) {
+		o.outputScheme = scheme
+	}
+}
+
+// WithMaxSegments caps the number of colon-separated segments, scheme
+// included, that parsing accepts before failing with ErrTooManySegments.
+// Values below 1 select DefaultMaxSegments.
+func WithMaxSegments(n int) Option {
+	return func(o *options) {
+		o.maxSegments = n
+	}
+}
+
+// EntityAllowDots makes ParseStrict and composing accept dotted entities
+// such as "shop.order". Each dot-separated part must be non-empty and
+// follow the usual entity characters; the whole entity is still limited to
+// 2–32 characters.
+func EntityAllowDots() Option {
+	return func(o *options) {
+		o.allowDots = true
+	}
+}
+
+// FlagValue is the value recorded for a bare trailing key accepted under
+// TrailingKeyAsFlag.
+const FlagValue = "true"
+
+// TrailingKeyAsFlag makes Parse accept a single trailing key without a
+// value, as in "urn:job:42:urgent", recording it as an attribute with
+// value FlagValue; (*URN).HasTrailingFlag reports that this happened, and
+// String re-emits the bare key. ParseStrict ignores this option.
+func TrailingKeyAsFlag() Option {
+	return func(o *options) {
+		o.trailingFlag = true
+	}
+}
+
+// MaxAttributesParsed makes parsing stop after n attribute pairs. The rest
+// of the attribute section is kept as an opaque tail: it is neither
+// decoded nor checked beyond the segment count and pairing, and String and
+// the composing methods re-emit it byte for byte. Attributes in the tail
+// are invisible to lookups and edits: Value and Get report them not
+// found, AddAttribute appends a key even if the tail already has it, and
+// RemoveAttribute leaves the tail alone. Values of n below 1 parse every
+// attribute. WithQuoting ignores this option.
+func MaxAttributesParsed(n int) Option {
+	return func(o *options) {
+		o.maxAttrs = n
+	}
+}
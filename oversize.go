@@ -0,0 +1,133 @@
+package urn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PartKey is the attribute key SplitOversizeValue uses to number shards.
+const PartKey = "part"
+
+// SplitOversizeValue shards a value too large for a single URN across
+// several URNs of at most maxLen characters each (MaxURNLength when maxLen
+// is 0). Each shard carries a PartKey attribute of the form "i/n" followed
+// by the key with its slice of the value. Values are split on rune
+// boundaries.
+func SplitOversizeValue(entity, id, key, value string, maxLen int) ([]string, error) {
+	if maxLen <= 0 || maxLen > MaxURNLength {
+		maxLen = MaxURNLength
+	}
+	if key == PartKey {
+		return nil, fmt.Errorf("Cannot split value: key %q is reserved", PartKey)
+	}
+	if err := validateComponents(entity, id, []Attribute{{Key: PartKey, Value: "1/1"}, {Key: key, Value: "x"}}); err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return nil, fmt.Errorf("Cannot split value: value is empty")
+	}
+
+	for digits := 1; ; digits++ {
+		n := strings.Repeat("9", digits)
+		overhead := composedLen(entity, id, []Attribute{{Key: PartKey, Value: n + "/" + n}, {Key: key, Value: ""}})
+		chunks, err := chunkEscaped(value, maxLen-overhead)
+		if err != nil {
+			return nil, err
+		}
+		if len(strconv.Itoa(len(chunks))) > digits {
+			continue
+		}
+		total := strconv.Itoa(len(chunks))
+		urns := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			urns[i], err = compose(entity, id, []Attribute{
+				{Key: PartKey, Value: strconv.Itoa(i+1) + "/" + total},
+				{Key: key, Value: chunk},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return urns, nil
+	}
+}
+
+// chunkEscaped splits s on rune boundaries so that each chunk's escaped
+// form is at most budget bytes.
+func chunkEscaped(s string, budget int) ([]string, error) {
+	var chunks []string
+	start, size := 0, 0
+	for i := 0; i < len(s); {
+		_, w := utf8.DecodeRuneInString(s[i:])
+		n := escapedLen(s[i : i+w])
+		if n > budget {
+			return nil, fmt.Errorf("Cannot split value: maximum length leaves no room for the value")
+		}
+		if size+n > budget {
+			chunks = append(chunks, s[start:i])
+			start, size = i, 0
+		}
+		size += n
+		i += w
+	}
+	return append(chunks, s[start:]), nil
+}
+
+// JoinOversizeValue reassembles a value split by SplitOversizeValue. The
+// URNs may be given in any order but must all share entity, ID and key, and
+// every part must be present exactly once; a part count larger than the
+// number of URNs given is rejected before anything is allocated for it.
+func JoinOversizeValue(urns []string) (string, error) {
+	if len(urns) == 0 {
+		return "", fmt.Errorf("Cannot join value: no parts")
+	}
+	var entity, id, key string
+	var chunks []string
+	for i, s := range urns {
+		u, err := Parse(s)
+		if err != nil {
+			return "", &BatchError{Index: i, Input: s, Err: err}
+		}
+		if len(u.attributes) != 2 || u.attributes[0].Key != PartKey {
+			return "", &BatchError{Index: i, Input: s, Err: fmt.Errorf("not a value part")}
+		}
+		num, total, ok := parsePart(u.attributes[0].Value)
+		if !ok {
+			return "", &BatchError{Index: i, Input: s, Err: fmt.Errorf("malformed part %q", u.attributes[0].Value)}
+		}
+		if i == 0 {
+			if total > len(urns) {
+				return "", fmt.Errorf("Cannot join value: missing parts, %d of %d given", len(urns), total)
+			}
+			entity, id, key = u.Entity, u.ID, u.attributes[1].Key
+			chunks = make([]string, total)
+		} else if u.Entity != entity || u.ID != id || u.attributes[1].Key != key {
+			return "", &BatchError{Index: i, Input: s, Err: fmt.Errorf("part belongs to a different value")}
+		}
+		if total != len(chunks) {
+			return "", &BatchError{Index: i, Input: s, Err: fmt.Errorf("part count %d disagrees with %d", total, len(chunks))}
+		}
+		if chunks[num-1] != "" {
+			return "", &BatchError{Index: i, Input: s, Err: fmt.Errorf("duplicate part %d/%d", num, total)}
+		}
+		chunks[num-1] = u.attributes[1].Value
+	}
+	// With no more parts than URNs and no duplicates, every part is present.
+	return strings.Join(chunks, ""), nil
+}
+
+// parsePart parses an "i/n" part value with 1 <= i <= n.
+func parsePart(s string) (num, total int, ok bool) {
+	a, b, found := strings.Cut(s, "/")
+	if !found {
+		return 0, 0, false
+	}
+	num, err1 := strconv.Atoi(a)
+	total, err2 := strconv.Atoi(b)
+	if err1 != nil || err2 != nil || num < 1 || num > total {
+		return 0, 0, false
+	}
+	return num, total, true
+}
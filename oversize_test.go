@@ -0,0 +1,107 @@
+package urn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitJoinOversizeValue(t *testing.T) {
+	value := strings.Repeat("payload:with spaces/é ", 40)
+	urns, err := SplitOversizeValue("blob", "42", "data", value, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(urns) < 2 {
+		t.Fatalf("expected several parts, got %d", len(urns))
+	}
+	for i, s := range urns {
+		if len(s) > MaxURNLength {
+			t.Errorf("part %d too long: %d", i, len(s))
+		}
+		if !IsValid(s) {
+			t.Errorf("part %d invalid: %s", i, s)
+		}
+	}
+	if !strings.HasPrefix(urns[0], "urn:blob:42:part:1%2F") {
+		t.Errorf("unexpected first part: %s", urns[0])
+	}
+	joined, err := JoinOversizeValue(urns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if joined != value {
+		t.Error("joined value differs from original")
+	}
+}
+
+func TestSplitOversizeValueSmallMaxLen(t *testing.T) {
+	urns, err := SplitOversizeValue("blob", "1", "d", strings.Repeat("x", 300), 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range urns {
+		if len(s) > 40 {
+			t.Errorf("part too long: %s", s)
+		}
+	}
+	if _, err := SplitOversizeValue("blob", "1", "d", "x", 20); err == nil {
+		t.Error("expected error when maxLen leaves no room")
+	}
+}
+
+func TestJoinOversizeValueOutOfOrder(t *testing.T) {
+	urns, _ := SplitOversizeValue("blob", "1", "d", strings.Repeat("abc", 200), 0)
+	reversed := make([]string, len(urns))
+	for i, s := range urns {
+		reversed[len(urns)-1-i] = s
+	}
+	joined, err := JoinOversizeValue(reversed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if joined != strings.Repeat("abc", 200) {
+		t.Error("out-of-order join produced wrong value")
+	}
+}
+
+func TestJoinOversizeValueMissingPart(t *testing.T) {
+	urns, _ := SplitOversizeValue("blob", "1", "d", strings.Repeat("abc", 200), 0)
+	_, err := JoinOversizeValue(append(urns[:1:1], urns[2:]...))
+	if err == nil || !strings.Contains(err.Error(), "missing parts") {
+		t.Errorf("expected missing part error, got %v", err)
+	}
+}
+
+func TestJoinOversizeValueHugeTotal(t *testing.T) {
+	for _, s := range []string{
+		"urn:a:1:part:1/9999999999999999:k:v",
+		"urn:a:1:part:1/100000000:k:v",
+		"urn:a:1:part:1/2:k:v",
+	} {
+		if _, err := JoinOversizeValue([]string{s}); err == nil || !strings.Contains(err.Error(), "missing parts") {
+			t.Errorf("JoinOversizeValue(%q) = %v, want missing parts error", s, err)
+		}
+	}
+	if _, err := JoinOversizeValue([]string{"urn:ab:1:part:1/99999999999999999999:k:v"}); err == nil {
+		t.Error("expected error for part count out of range")
+	}
+}
+
+func TestJoinOversizeValueDuplicatePart(t *testing.T) {
+	urns, _ := SplitOversizeValue("blob", "1", "d", strings.Repeat("abc", 200), 0)
+	_, err := JoinOversizeValue(append(urns, urns[0]))
+	if err == nil || !strings.Contains(err.Error(), "duplicate part 1/") {
+		t.Errorf("expected duplicate part error, got %v", err)
+	}
+}
+
+func TestJoinOversizeValueMismatch(t *testing.T) {
+	a, _ := SplitOversizeValue("blob", "1", "d", strings.Repeat("abc", 200), 0)
+	b, _ := SplitOversizeValue("blob", "2", "d", strings.Repeat("abc", 200), 0)
+	if _, err := JoinOversizeValue([]string{a[0], b[1]}); err == nil {
+		t.Error("expected error for parts of different values")
+	}
+	if _, err := JoinOversizeValue(nil); err == nil {
+		t.Error("expected error for no parts")
+	}
+}
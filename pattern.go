@@ -0,0 +1,138 @@
+package urn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pattern matches URNs by shape. A pattern is written like a URN,
+// "urn:entity:id[:key:value...]", where each segment after the scheme is
+// one of:
+//
+//	literal  matches the segment exactly, after decoding; the entity
+//	         matches case-insensitively
+//	*        matches any single segment
+//	{name}   matches any single segment and captures it as name
+//	**       matches zero or more remaining segments; last segment only
+//
+// Attributes are matched by position, so "urn:orders:*:vendor:{v}"
+// matches "urn:orders:1:vendor:amazon" but not
+// "urn:orders:1:region:eu:vendor:amazon".
+type Pattern struct {
+	text string
+	segs []patternSeg
+}
+
+type segKind int
+
+const (
+	segLiteral segKind = iota
+	segWild
+	segMulti
+)
+
+type patternSeg struct {
+	kind  segKind
+	value string // literal value, or the capture name of a wildcard
+}
+
+// CompilePattern parses a pattern.
+func CompilePattern(pattern string) (*Pattern, error) {
+	rest, ok := strings.CutPrefix(pattern, "urn:")
+	if !ok {
+		return nil, fmt.Errorf("Invalid pattern %q: Must start with the 'urn:' scheme", pattern)
+	}
+	parts := strings.Split(rest, ":")
+	p := &Pattern{text: pattern, segs: make([]patternSeg, len(parts))}
+	names := make(map[string]bool)
+	for i, s := range parts {
+		switch {
+		case s == "":
+			return nil, fmt.Errorf("Invalid pattern %q: Empty segment %d", pattern, i)
+		case s == "*":
+			p.segs[i] = patternSeg{kind: segWild}
+		case s == "**":
+			if i != len(parts)-1 {
+				return nil, fmt.Errorf("Invalid pattern %q: ** must be the last segment", pattern)
+			}
+			p.segs[i] = patternSeg{kind: segMulti}
+		case strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}"):
+			name := s[1 : len(s)-1]
+			if name == "" || names[name] {
+				return nil, fmt.Errorf("Invalid pattern %q: Empty or repeated capture %q", pattern, s)
+			}
+			names[name] = true
+			p.segs[i] = patternSeg{kind: segWild, value: name}
+		default:
+			v, err := unescape(s)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid pattern %q: %w", pattern, err)
+			}
+			if i == 0 {
+				v = asciiToLower(v)
+			}
+			p.segs[i] = patternSeg{kind: segLiteral, value: v}
+		}
+	}
+	return p, nil
+}
+
+// MustCompilePattern is like CompilePattern but panics on error.
+func MustCompilePattern(pattern string) *Pattern {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the pattern text.
+func (p *Pattern) String() string {
+	return p.text
+}
+
+// Match reports whether urnStr parses and matches the pattern, returning
+// the captured segments, decoded.
+func (p *Pattern) Match(urnStr string) (map[string]string, bool) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return nil, false
+	}
+	return p.match(u.segments())
+}
+
+func (p *Pattern) match(segs []string) (map[string]string, bool) {
+	var params map[string]string
+	for i, ps := range p.segs {
+		if ps.kind == segMulti {
+			return params, true
+		}
+		if i >= len(segs) {
+			return nil, false
+		}
+		switch {
+		case ps.kind == segLiteral && ps.value != segs[i]:
+			return nil, false
+		case ps.kind == segWild && ps.value != "":
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[ps.value] = segs[i]
+		}
+	}
+	if len(p.segs) != len(segs) {
+		return nil, false
+	}
+	return params, true
+}
+
+// segments returns the decoded segments after the scheme, with the entity
+// lowercased, as patterns see them.
+func (u *URN) segments() []string {
+	segs := make([]string, 0, 2+2*len(u.attributes))
+	segs = append(segs, asciiToLower(u.Entity), u.ID)
+	for _, a := range u.attributes {
+		segs = append(segs, a.Key, a.Value)
+	}
+	return segs
+}
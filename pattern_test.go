@@ -0,0 +1,37 @@
+package urn
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern, in string
+		ok          bool
+		params      map[string]string
+	}{
+		{"urn:orders:*", "urn:Orders:1", true, nil},
+		{"urn:orders:*", "urn:orders:1:k:v", false, nil},
+		{"urn:orders:{id}:vendor:{v}", "urn:orders:1:vendor:a%3Ab", true, map[string]string{"id": "1", "v": "a:b"}},
+		{"urn:orders:{id}:vendor:{v}", "urn:orders:1:region:eu", false, nil},
+		{"urn:orders:**", "urn:orders", false, nil},
+		{"urn:orders:**", "urn:orders:1", true, nil},
+		{"urn:orders:*:**", "urn:orders:1", true, nil},
+		{"urn:*:a%2Fb", "urn:x:a/b", true, nil},
+	}
+	for _, tt := range tests {
+		params, ok := MustCompilePattern(tt.pattern).Match(tt.in)
+		if ok != tt.ok || !maps.Equal(params, tt.params) {
+			t.Errorf("%s.Match(%s) = %v, %v", tt.pattern, tt.in, params, ok)
+		}
+	}
+}
+
+func TestCompilePatternErrors(t *testing.T) {
+	for _, p := range []string{"orders:*", "urn:orders::x", "urn:**:x", "urn:{a}:{a}", "urn:{}:1", "urn:orders:%zz"} {
+		if _, err := CompilePattern(p); err == nil {
+			t.Errorf("CompilePattern(%q) succeeded", p)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package urn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// wideURN has 20 attribute pairs.
+var wideURN = func() string {
+	var b strings.Builder
+	b.WriteString("urn:orders:123")
+	for i := range 20 {
+		fmt.Fprintf(&b, ":k%d:v%d", i, i)
+	}
+	return b.String()
+}()
+
+func TestParsePreallocated(t *testing.T) {
+	u, err := Parse(wideURN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs := u.AttributePairs()
+	if len(pairs) != 20 || pairs[19] != (Attribute{"k19", "v19"}) {
+		t.Fatalf("got %v", pairs)
+	}
+	if got := u.String(); got != wideURN {
+		t.Errorf("round trip = %s", got)
+	}
+	if allocs := testing.AllocsPerRun(50, func() { _, _ = Parse(wideURN) }); allocs > 2 {
+		t.Errorf("Parse made %.0f allocations, want at most 2", allocs)
+	}
+	if allocs := testing.AllocsPerRun(50, func() { _, _ = compose(u.Entity, u.ID, pairs) }); allocs != 1 {
+		t.Errorf("compose made %.0f allocations, want 1", allocs)
+	}
+}
+
+func TestParseTailErrorsUnchanged(t *testing.T) {
+	tests := []struct {
+		in     string
+		kind   ErrorKind
+		offset int
+		seg    string
+	}{
+		{"urn:a:1:k", KindUnpairedKey, 8, "k"},
+		{"urn:a:1:k:v:x", KindUnpairedKey, 12, "x"},
+		{"urn:a:1::v", KindEmptyAttribute, 8, ""},
+		{"urn:a:1:k:", KindEmptyAttribute, 10, "k"},
+		{"urn:a:1:k:v:j::", KindUnpairedKey, 15, ""},
+		{"urn:a:1:k:%zz", KindMalformedEscape, 10, "%zz"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in)
+		var e *InvalidURNError
+		if !errors.As(err, &e) || e.Kind != tt.kind || e.Offset != tt.offset || e.Segment != tt.seg {
+			t.Errorf("Parse(%q) = %#v", tt.in, err)
+		}
+	}
+}
+
+func BenchmarkParseWide(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = Parse(wideURN)
+	}
+}
+
+func BenchmarkComposeWide(b *testing.B) {
+	u, _ := Parse(wideURN)
+	pairs := u.AttributePairs()
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = compose(u.Entity, u.ID, pairs)
+	}
+}
@@ -0,0 +1,100 @@
+package urn
+
+import (
+	"regexp"
+)
+
+// Predicate reports whether a URN matches some condition. Predicates built
+// by this package are pure and safe to share across goroutines.
+type Predicate func(*URN) bool
+
+// And matches when every predicate matches. And() matches everything.
+func And(ps ...Predicate) Predicate {
+	return func(u *URN) bool {
+		for _, p := range ps {
+			if !p(u) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches when any predicate matches. Or() matches nothing.
+func Or(ps ...Predicate) Predicate {
+	return func(u *URN) bool {
+		for _, p := range ps {
+			if p(u) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not inverts a predicate.
+func Not(p Predicate) Predicate {
+	return func(u *URN) bool {
+		return !p(u)
+	}
+}
+
+// EntityIs matches URNs of the given entity, compared ASCII
+// case-insensitively.
+func EntityIs(entity string) Predicate {
+	return func(u *URN) bool {
+		return asciiEqualFold(u.Entity, entity)
+	}
+}
+
+// HasAttr matches URNs carrying an attribute with the key.
+func HasAttr(key string) Predicate {
+	return func(u *URN) bool {
+		_, ok := u.get(key)
+		return ok
+	}
+}
+
+// AttrEquals matches URNs whose first attribute with the key has the value.
+func AttrEquals(key, value string) Predicate {
+	return func(u *URN) bool {
+		v, ok := u.get(key)
+		return ok && v == value
+	}
+}
+
+// IDMatches matches URNs whose decoded ID matches re.
+func IDMatches(re *regexp.Regexp) Predicate {
+	return func(u *URN) bool {
+		return re.MatchString(u.ID)
+	}
+}
+
+// Filter returns the URNs matching p, in order.
+func Filter(urns []*URN, p Predicate) []*URN {
+	var out []*URN
+	for _, u := range urns {
+		if p(u) {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// FilterStrings parses each string and returns those matching p, in order.
+// Inputs that fail to parse are reported as *BatchError values.
+func FilterStrings(urns []string, p Predicate) ([]string, []error) {
+	var out []string
+	var errs []error
+	for i, s := range urns {
+		u, err := Parse(s)
+		if err != nil {
+			errs = append(errs, &BatchError{Index: i, Input: s, Err: err})
+			continue
+		}
+		if p(u) {
+			out = append(out, s)
+		}
+	}
+	return out, errs
+}
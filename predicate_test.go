@@ -0,0 +1,47 @@
+package urn
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestPredicateTree(t *testing.T) {
+	inputs := []string{
+		"urn:orders:1:vendor:amazon:status:shipped",
+		"urn:orders:2:vendor:ebay:status:pending",
+		"urn:orders:x3:status:shipped",
+		"urn:Orders:4:vendor:amazon:archived:true",
+		"urn:customers:5:vendor:amazon",
+		"bogus",
+	}
+	// orders that are (shipped or from amazon) and not archived, with numeric IDs
+	p := And(
+		EntityIs("orders"),
+		Or(AttrEquals("status", "shipped"), And(HasAttr("vendor"), AttrEquals("vendor", "amazon"))),
+		Not(Or(HasAttr("archived"), Not(IDMatches(regexp.MustCompile(`^\d+$`))))),
+	)
+	got, errs := FilterStrings(inputs, p)
+	if len(errs) != 1 {
+		t.Errorf("expected 1 error, got %v", errs)
+	}
+	want := []string{"urn:orders:1:vendor:amazon:status:shipped"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	var urns []*URN
+	for _, s := range []string{"urn:orders:1", "urn:customers:2", "urn:ORDERS:3"} {
+		u, _ := Parse(s)
+		urns = append(urns, u)
+	}
+	got := Filter(urns, EntityIs("orders"))
+	if len(got) != 2 || got[0].ID != "1" || got[1].ID != "3" {
+		t.Errorf("unexpected filter result: %v", got)
+	}
+	if len(Filter(urns, Or())) != 0 || len(Filter(urns, And())) != 3 {
+		t.Error("unexpected empty combinator behavior")
+	}
+}
@@ -0,0 +1,74 @@
+package urn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrefixRange returns bounds for a key-range scan over canonical URN
+// strings: every URN of entity whose ID starts with idPrefix sorts in
+// [low, high). low is the escaped prefix itself and high is low with its
+// last byte incremented. As in Canonical, the entity is lowercased, so
+// keys stored in another entity case fall outside the range. The entity
+// must be well-formed under the package defaults, which may allow dots
+// (see EntityAllowDots). An empty idPrefix covers every ID of the entity.
+func PrefixRange(entity, idPrefix string) (low, high string, err error) {
+	low, err = idPrefixString(entity, idPrefix)
+	if err != nil {
+		return "", "", err
+	}
+	return low, prefixSuccessor(low), nil
+}
+
+// LikePattern returns a SQL LIKE pattern matching every canonical URN of
+// entity whose ID starts with idPrefix. Occurrences of '%', '_' and
+// escapeChar in the escaped prefix are preceded by escapeChar; use the
+// pattern with "LIKE ? ESCAPE '<escapeChar>'".
+func LikePattern(entity, idPrefix string, escapeChar byte) (string, error) {
+	if escapeChar == '%' || escapeChar == '_' {
+		return "", fmt.Errorf("Cannot build LIKE pattern: %q is a LIKE wildcard", escapeChar)
+	}
+	prefix, err := idPrefixString(entity, idPrefix)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.Grow(len(prefix) + 8)
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if c == '%' || c == '_' || c == escapeChar {
+			b.WriteByte(escapeChar)
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte('%')
+	return b.String(), nil
+}
+
+// idPrefixString returns "urn:<entity>:<escaped idPrefix>" with the entity
+// lowercased. Escaping is byte-wise, so it is a prefix of the canonical
+// form of every URN of entity whose ID starts with idPrefix.
+func idPrefixString(entity, idPrefix string) (string, error) {
+	o, _ := defaultProcessor.snapshot()
+	if entity == "" {
+		return "", &InvalidURNError{Kind: KindEmptyComponent, Op: OpCompose}
+	}
+	if err := checkEntity(nil, o, entity, OpCompose); err != nil {
+		return "", err
+	}
+	return "urn:" + escape(asciiToLower(entity)) + ":" + escape(idPrefix), nil
+}
+
+// prefixSuccessor returns the smallest string greater than every string
+// with prefix p, dropping trailing 0xFF bytes before incrementing. It
+// returns "" when p consists only of 0xFF bytes, meaning no upper bound.
+func prefixSuccessor(p string) string {
+	b := []byte(p)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xFF {
+			b[i]++
+			return string(b[:i+1])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package urn
+
+import "testing"
+
+func TestPrefixRange(t *testing.T) {
+	tests := []struct {
+		prefix, low, high string
+	}{
+		{"ab", "urn:orders:ab", "urn:orders:ac"},
+		{"", "urn:orders:", "urn:orders;"},
+		{"50%", "urn:orders:50%25", "urn:orders:50%26"},
+		{"a_b", "urn:orders:a_b", "urn:orders:a_c"},
+		{"\xff", "urn:orders:%FF", "urn:orders:%FG"},
+	}
+	for _, tt := range tests {
+		low, high, err := PrefixRange("orders", tt.prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if low != tt.low || high != tt.high {
+			t.Errorf("PrefixRange(%q) = %q, %q; want %q, %q", tt.prefix, low, high, tt.low, tt.high)
+		}
+	}
+
+	low, high, _ := PrefixRange("orders", "ab")
+	for _, id := range []string{"ab", "ab:c", "abz", "ab\xff"} {
+		s, err := Compose("orders", id, map[string]string{"k": "v"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s < low || s >= high {
+			t.Errorf("%s not in [%s, %s)", s, low, high)
+		}
+	}
+	if s, _ := Compose("orders", "ac"); s >= low && s < high {
+		t.Errorf("%s unexpectedly in range", s)
+	}
+
+	if _, _, err := PrefixRange("bad entity", "x"); err == nil {
+		t.Error("expected error for invalid entity")
+	}
+}
+
+func TestPrefixRangeCanonicalEntity(t *testing.T) {
+	low, high, err := PrefixRange("Orders", "ab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := Parse("urn:ORDERS:abc:k:v")
+	if c := u.Canonical(); c < low || c >= high {
+		t.Errorf("%s not in [%s, %s)", c, low, high)
+	}
+
+	if _, _, err := PrefixRange("shop.orders", "a"); err == nil {
+		t.Error("dotted entity accepted without EntityAllowDots")
+	}
+	SetDefaults(EntityAllowDots())
+	t.Cleanup(func() { SetDefaults() })
+	if low, _, err := PrefixRange("Shop.Orders", "a"); err != nil || low != "urn:shop.orders:a" {
+		t.Errorf("PrefixRange under EntityAllowDots = %q, %v", low, err)
+	}
+}
+
+func TestPrefixSuccessor(t *testing.T) {
+	if got := prefixSuccessor("a\xff\xff"); got != "b" {
+		t.Errorf("got %q, want %q", got, "b")
+	}
+	if got := prefixSuccessor("\xff"); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	tests := []struct {
+		prefix string
+		esc    byte
+		want   string
+	}{
+		{"ab", '\\', `urn:orders:ab%`},
+		{"50%", '\\', `urn:orders:50\%25%`},
+		{"a_b", '\\', `urn:orders:a\_b%`},
+		{"a\\b", '\\', `urn:orders:a\%5Cb%`},
+		{"a!b", '!', `urn:orders:a!%21b%`},
+		{"x\xff", '!', `urn:orders:x!%FF%`},
+	}
+	for _, tt := range tests {
+		got, err := LikePattern("orders", tt.prefix, tt.esc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("LikePattern(%q, %q) = %q, want %q", tt.prefix, tt.esc, got, tt.want)
+		}
+	}
+	if got, _ := LikePattern("my-ent", "p", '-'); got != "urn:my--ent:p%" {
+		t.Errorf("got %q", got)
+	}
+	if _, err := LikePattern("orders", "x", '%'); err == nil {
+		t.Error("expected error for wildcard escape character")
+	}
+}
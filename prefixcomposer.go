@@ -0,0 +1,65 @@
+package urn
+
+// PrefixComposer composes URNs for a single entity, validating and
+// escaping the entity once up front. Its output is identical to
+// ComposeAttrs. A PrefixComposer is immutable and safe for concurrent use.
+type PrefixComposer struct {
+	entity string
+	prefix []byte // "urn:<escaped entity>:"
+}
+
+// NewPrefixComposer returns a PrefixComposer for entity, or the error
+// ComposeAttrs would report for it.
+func NewPrefixComposer(entity string) (*PrefixComposer, error) {
+	if err := validateComponents(entity, "x", nil); err != nil {
+		return nil, err
+	}
+	prefix := append([]byte("urn:"), escape(entity)...)
+	return &PrefixComposer{entity: entity, prefix: append(prefix, ':')}, nil
+}
+
+// Compose returns the URN for id and attrs, emitting attributes in order.
+func (c *PrefixComposer) Compose(id string, attrs ...Attribute) (string, error) {
+	b, err := c.ComposeAppend(make([]byte, 0, composedLen(c.entity, id, attrs)), id, attrs...)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ComposeAppend appends the URN for id and attrs to dst and returns the
+// extended buffer. On error dst is returned unchanged.
+func (c *PrefixComposer) ComposeAppend(dst []byte, id string, attrs ...Attribute) ([]byte, error) {
+	err := c.check(id, attrs)
+	observeCompose(err)
+	if err != nil {
+		return dst, err
+	}
+	dst = append(dst, c.prefix...)
+	dst = appendEscape(dst, id)
+	for _, p := range attrs {
+		dst = append(dst, ':')
+		dst = appendEscape(dst, p.Key)
+		dst = append(dst, ':')
+		dst = appendEscape(dst, p.Value)
+	}
+	return dst, nil
+}
+
+// check applies the ComposeAttrs rules, minus the entity checks already
+// done by NewPrefixComposer.
+func (c *PrefixComposer) check(id string, attrs []Attribute) error {
+	if id == "" {
+		return &InvalidURNError{
+			Kind: KindEmptyComponent,
+			Op:   OpCompose,
+		}
+	}
+	if err := validatePairs(loadConfig(), attrs); err != nil {
+		return err
+	}
+	if composedLen(c.entity, id, attrs) > MaxURNLength {
+		return checkLength(c.entity, id, attrs, MaxURNLength)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package urn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrefixComposerMatchesCompose(t *testing.T) {
+	c, err := NewPrefixComposer("order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		id    string
+		attrs []Attribute
+	}{
+		{"1", nil},
+		{"a:b c", []Attribute{{"vendor", "amazon"}, {"note", "x/y%z"}}},
+		{"1", []Attribute{{"tag", "red"}, {"tag", "blue"}}},
+		{"", nil},
+		{"1", []Attribute{{"k", ""}}},
+		{strings.Repeat("x", 300), nil},
+	}
+	for _, tc := range cases {
+		want, wantErr := ComposeAttrs("order", tc.id, tc.attrs)
+		got, err := c.Compose(tc.id, tc.attrs...)
+		if got != want || (err == nil) != (wantErr == nil) {
+			t.Errorf("Compose(%q, %v) = %q, %v; want %q, %v", tc.id, tc.attrs, got, err, want, wantErr)
+		}
+		if err != nil && err.Error() != wantErr.Error() {
+			t.Errorf("error %q, want %q", err, wantErr)
+		}
+	}
+}
+
+func TestPrefixComposerAppend(t *testing.T) {
+	c, _ := NewPrefixComposer("order")
+	buf := []byte("keys: ")
+	buf, err := c.ComposeAppend(buf, "1", Attribute{"k", "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err = c.ComposeAppend(buf, "", Attribute{"k", "v"})
+	if err == nil {
+		t.Error("expected error for empty ID")
+	}
+	if string(buf) != "keys: urn:order:1:k:v" {
+		t.Errorf("got %q", buf)
+	}
+}
+
+func TestNewPrefixComposerInvalidEntity(t *testing.T) {
+	for _, entity := range []string{"", "a", "bad entity"} {
+		if _, err := NewPrefixComposer(entity); err == nil {
+			t.Errorf("NewPrefixComposer(%q): expected error", entity)
+		}
+	}
+}
+
+func BenchmarkComposeAttrs(b *testing.B) {
+	attrs := []Attribute{{"vendor", "amazon"}, {"status", "shipped"}}
+	for i := 0; i < b.N; i++ {
+		ComposeAttrs("order", "1234", attrs)
+	}
+}
+
+func BenchmarkPrefixComposer(b *testing.B) {
+	c, _ := NewPrefixComposer("order")
+	attrs := []Attribute{{"vendor", "amazon"}, {"status", "shipped"}}
+	for i := 0; i < b.N; i++ {
+		c.Compose("1234", attrs...)
+	}
+}
+
+func BenchmarkPrefixComposerAppend(b *testing.B) {
+	c, _ := NewPrefixComposer("order")
+	attrs := []Attribute{{"vendor", "amazon"}, {"status", "shipped"}}
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		buf, _ = c.ComposeAppend(buf[:0], "1234", attrs...)
+	}
+}
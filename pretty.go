@@ -0,0 +1,60 @@
+package urn
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Pretty renders the URN over several lines for debugging: the
+// "urn:entity:id" head, then one line per attribute, prefixed with indent,
+// of the form "key = value" with the equals signs aligned. Keys and values
+// are shown decoded; a value that was percent-encoded in the input is
+// followed by its encoded form, and one holding control characters is
+// quoted. Attributes left unparsed under MaxAttributesParsed follow on a
+// final line as they appeared in the input.
+func (u *URN) Pretty(indent string) string {
+	var b strings.Builder
+	b.WriteString("urn:")
+	b.WriteString(u.RawEntity())
+	b.WriteByte(':')
+	b.WriteString(u.RawID())
+
+	width := 0
+	for _, p := range u.attributes {
+		width = max(width, len(p.Key))
+	}
+	raw := u.RawAttributePairs()
+	for i, p := range u.attributes {
+		b.WriteByte('\n')
+		b.WriteString(indent)
+		b.WriteString(p.Key)
+		b.WriteString(strings.Repeat(" ", width-len(p.Key)))
+		b.WriteString(" = ")
+		if indexControl(p.Value) >= 0 {
+			b.WriteString(strconv.Quote(p.Value))
+		} else {
+			b.WriteString(p.Value)
+		}
+		if raw[i].Value != p.Value {
+			b.WriteString("  (encoded: ")
+			b.WriteString(raw[i].Value)
+			b.WriteByte(')')
+		}
+	}
+	if u.rest != "" {
+		b.WriteByte('\n')
+		b.WriteString(indent)
+		b.WriteString("(unparsed) ")
+		b.WriteString(u.rest)
+	}
+	return b.String()
+}
+
+// FormatPretty parses urnStr and renders it with Pretty.
+func FormatPretty(urnStr, indent string) (string, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return "", err
+	}
+	return u.Pretty(indent), nil
+}
@@ -0,0 +1,55 @@
+package urn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatPrettyGolden(t *testing.T) {
+	inputs := []string{
+		"urn:orders:1234:vendor:amazon:shipped-at:2024-01-02T03%3A04%3A05Z:n:1:note:line%0Abreak:path:a%2fb",
+		"urn:Orders:a%3Ab",
+	}
+	var b strings.Builder
+	for _, in := range inputs {
+		s, err := FormatPretty(in, "    ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.WriteString(s)
+		b.WriteString("\n\n")
+	}
+	u, err := NewProcessor(MaxAttributesParsed(1)).Parse("urn:job:1:a:1:b:2:c:3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.WriteString(u.Pretty("\t"))
+	b.WriteString("\n")
+	got := b.String()
+
+	golden := filepath.Join("testdata", "pretty.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("pretty output differs from %s:\n%s", golden, got)
+	}
+}
+
+func TestPrettyBuiltURN(t *testing.T) {
+	u, _ := NewURN("orders", "1", Attribute{Key: "path", Value: "a/b"})
+	if got, want := u.Pretty("  "), "urn:orders:1\n  path = a/b  (encoded: a%2Fb)"; got != want {
+		t.Errorf("Pretty = %q, want %q", got, want)
+	}
+	if _, err := FormatPretty("bad", ""); err == nil {
+		t.Error("FormatPretty accepted an invalid URN")
+	}
+}
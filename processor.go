@@ -0,0 +1,230 @@
+package urn
+
+// Processor parses URNs with a fixed set of options. A Processor is
+// immutable after creation and safe for concurrent use.
+type Processor struct {
+	opts options
+	// useDefaults makes the Processor read its options from the package
+	// configuration on every call; it backs the package-level functions.
+	useDefaults bool
+	// registries, when set, is the package configuration captured under
+	// CopyRegistries; its registries are used instead of the live ones.
+	registries *config
+}
+
+var defaultProcessor = &Processor{useDefaults: true}
+
+// NewProcessor creates a Processor configured with opts. Its options are
+// independent of SetDefaults.
+func NewProcessor(opts ...Option) *Processor {
+	p := &Processor{opts: newOptions(opts)}
+	if p.opts.copyRegistries {
+		p.registries = loadConfig()
+	}
+	return p
+}
+
+// With returns a new Processor with the options of p, overridden by opts.
+// p is left unchanged. The child of the package-level Processor starts
+// from the current SetDefaults options and does not follow later calls.
+//
+// Registries (ID validators and comparators, reserved keys, the key
+// format, sub-resources, entity aliases and the error formatter) are
+// shared with the package and seen live by default. Under CopyRegistries
+// the child uses a copy instead: the parent's copy if it has one, or
+// else the registries as of the call to With. ShareRegistries switches a
+// child of such a Processor back to the live registries.
+func (p *Processor) With(opts ...Option) *Processor {
+	parent, _ := p.snapshot()
+	child := &Processor{opts: *parent}
+	for _, opt := range opts {
+		opt(&child.opts)
+	}
+	if child.opts.copyRegistries {
+		child.registries = p.registries
+		if child.registries == nil {
+			child.registries = loadConfig()
+		}
+	}
+	return child
+}
+
+// snapshot returns the options and package configuration for one call.
+func (p *Processor) snapshot() (*options, *config) {
+	if p.registries != nil {
+		return &p.opts, p.registries
+	}
+	c := loadConfig()
+	if p.useDefaults {
+		return &c.opts, c
+	}
+	return &p.opts, c
+}
+
+// Parse deconstructs a URN string into its components.
+func (p *Processor) Parse(urnStr string) (*URN, error) {
+	o, _ := p.snapshot()
+	return parse(urnStr, o)
+}
+
+// ParseStrict parses a URN, enforcing the same rules as the package-level
+// ParseStrict under the Processor's options.
+func (p *Processor) ParseStrict(urnStr string) (*URN, error) {
+	o, c := p.snapshot()
+	return parseStrict(urnStr, o, c)
+}
+
+// IsValid reports whether ParseStrict accepts urnStr under the Processor's
+// options.
+func (p *Processor) IsValid(urnStr string) bool {
+	_, err := p.ParseStrict(urnStr)
+	return err == nil
+}
+
+// Compose constructs a URN string from the given components.
+func (p *Processor) Compose(entity, id string, attrs ...map[string]string) (string, error) {
+	var pairs []Attribute
+	if len(attrs) > 0 && attrs[0] != nil {
+		for k, v := range attrs[0] {
+			pairs = append(pairs, Attribute{Key: k, Value: v})
+		}
+	}
+	return p.ComposeAttrs(entity, id, pairs)
+}
+
+// ComposeAttrs constructs a URN string, emitting attributes exactly in the
+// order of the slice.
+func (p *Processor) ComposeAttrs(entity, id string, attrs []Attribute) (string, error) {
+	s, err := p.composeAttrs(entity, id, attrs)
+	observeCompose(err)
+	return s, err
+}
+
+func (p *Processor) composeAttrs(entity, id string, attrs []Attribute) (string, error) {
+	o, c := p.snapshot()
+	if err := validateComponentsOpts(c, o, entity, id, attrs); err != nil {
+		return "", err
+	}
+	if o.quoting {
+		return composeQuoted(o.scheme(), entity, id, attrs)
+	}
+	return composeScheme(o.scheme(), entity, id, attrs)
+}
+
+// Format returns the string form of u under the Processor's options. With
+// TrailingKeyAsFlag, a trailing flag is emitted as a bare key; with
+// WithQuoting, values containing ':' are quoted.
+func (p *Processor) Format(u *URN) (string, error) {
+	o, _ := p.snapshot()
+	var s string
+	var err error
+	if o.quoting {
+		s, err = composeQuoted(o.scheme(), u.Entity, u.ID, u.attributes)
+	} else {
+		s, err = composeURN(o.scheme(), u)
+	}
+	if err != nil || !o.trailingFlag {
+		return s, err
+	}
+	return u.legacyFlagForm(s), nil
+}
+
+// Value retrieves the value for a specific attribute key.
+// Returns the value, whether it was found, and any parse error.
+func (p *Processor) Value(urnStr, key string) (string, bool, error) {
+	o, _ := p.snapshot()
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return "", false, err
+	}
+	for _, a := range u.attributes {
+		if o.keyEqual(a.Key, key) {
+			return a.Value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// HasAttribute reports whether the URN carries an attribute with the key.
+func (p *Processor) HasAttribute(urnStr, key string) (bool, error) {
+	_, found, err := p.Value(urnStr, key)
+	return found, err
+}
+
+// AddAttribute appends or updates an attribute in the URN.
+func (p *Processor) AddAttribute(urnStr, key, value string) (string, error) {
+	o, c := p.snapshot()
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return "", err
+	}
+	if err := c.checkKey(key); err != nil {
+		return "", err
+	}
+	u.set(o, key, value)
+	return composeURN(o.scheme(), u)
+}
+
+// SetAttributeIf sets key to value when cond, given the current value of
+// key and whether it exists, returns true. It parses and composes once and
+// reports whether the update happened; otherwise urnStr is returned as is.
+func (p *Processor) SetAttributeIf(urnStr, key, value string, cond func(current string, exists bool) bool) (string, bool, error) {
+	o, c := p.snapshot()
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return "", false, err
+	}
+	at := -1
+	for i, a := range u.attributes {
+		if o.keyEqual(a.Key, key) {
+			at = i
+			break
+		}
+	}
+	current := ""
+	if at >= 0 {
+		current = u.attributes[at].Value
+	}
+	if !cond(current, at >= 0) {
+		return urnStr, false, nil
+	}
+	if err := c.checkKey(key); err != nil {
+		return "", false, err
+	}
+	if at >= 0 {
+		u.attributes[at].Value = value
+	} else {
+		u.attributes = append(u.attributes, Attribute{Key: key, Value: value})
+	}
+	s, err := composeURN(o.scheme(), u)
+	if err != nil {
+		return "", false, err
+	}
+	return s, true, nil
+}
+
+// CompareAndSwapAttribute sets key to newValue only if its current value
+// is old. It reports false, returning urnStr unchanged, when the key is
+// missing or holds a different value.
+func (p *Processor) CompareAndSwapAttribute(urnStr, key, old, newValue string) (string, bool, error) {
+	return p.SetAttributeIf(urnStr, key, newValue, func(current string, exists bool) bool {
+		return exists && current == old
+	})
+}
+
+// RemoveAttribute removes an attribute by key from the URN.
+func (p *Processor) RemoveAttribute(urnStr, key string) (string, error) {
+	o, _ := p.snapshot()
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return "", err
+	}
+	filtered := make([]Attribute, 0, len(u.attributes))
+	for _, a := range u.attributes {
+		if !o.keyEqual(a.Key, key) {
+			filtered = append(filtered, a)
+		}
+	}
+	u.attributes = filtered
+	return composeURN(o.scheme(), u)
+}
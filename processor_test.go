@@ -0,0 +1,209 @@
+package urn
+
+import "testing"
+
+func TestRawDiffersFromString(t *testing.T) {
+	input := "URN:orders:1234:note:a%2fb"
+	u, err := Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Raw() != input {
+		t.Errorf("expected Raw %q, got %q", input, u.Raw())
+	}
+	if u.String() != "urn:orders:1234:note:a%2Fb" {
+		t.Errorf("unexpected String(): %s", u.String())
+	}
+	if u.Clone().Raw() != input {
+		t.Error("expected Clone to keep Raw")
+	}
+}
+
+func TestProcessorDiscardRaw(t *testing.T) {
+	p := NewProcessor(DiscardRaw())
+	u, err := p.Parse("URN:orders:1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Raw() != "" {
+		t.Errorf("expected empty Raw, got %q", u.Raw())
+	}
+	u, err = p.ParseStrict("urn:orders:1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Raw() != "" {
+		t.Errorf("expected empty Raw, got %q", u.Raw())
+	}
+}
+
+func TestProcessorParseStrict(t *testing.T) {
+	p := NewProcessor(RejectControlChars())
+	if _, err := p.ParseStrict("urn:notes:a%0Ab"); err == nil {
+		t.Error("expected error for control character")
+	}
+	if _, err := p.Parse("urn:notes:a%0Ab"); err != nil {
+		t.Errorf("lenient Parse should accept escaped control characters: %v", err)
+	}
+}
+
+func TestCaseInsensitiveKeysLookup(t *testing.T) {
+	p := NewProcessor(CaseInsensitiveKeys())
+	val, found, err := p.Value("urn:orders:1:Vendor:amazon", "vendor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || val != "amazon" {
+		t.Errorf("expected amazon, got %s (found=%v)", val, found)
+	}
+	has, _ := p.HasAttribute("urn:orders:1:Vendor:amazon", "VENDOR")
+	if !has {
+		t.Error("expected HasAttribute to fold keys")
+	}
+
+	_, found, _ = Value("urn:orders:1:Vendor:amazon", "vendor")
+	if found {
+		t.Error("default lookups must stay case-sensitive")
+	}
+}
+
+func TestCaseInsensitiveKeysOverwrite(t *testing.T) {
+	p := NewProcessor(CaseInsensitiveKeys())
+	updated, err := p.AddAttribute("urn:orders:1:Vendor:amazon", "vendor", "ebay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != "urn:orders:1:Vendor:ebay" {
+		t.Errorf("expected first-seen casing to be kept, got %s", updated)
+	}
+
+	updated, _ = AddAttribute("urn:orders:1:Vendor:amazon", "vendor", "ebay")
+	if updated != "urn:orders:1:Vendor:amazon:vendor:ebay" {
+		t.Errorf("unexpected default overwrite: %s", updated)
+	}
+}
+
+func TestCaseInsensitiveKeysRemove(t *testing.T) {
+	p := NewProcessor(CaseInsensitiveKeys())
+	updated, err := p.RemoveAttribute("urn:orders:1:Vendor:amazon:status:new", "VENDOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != "urn:orders:1:status:new" {
+		t.Errorf("unexpected result: %s", updated)
+	}
+}
+
+func TestSchemeAliasParsing(t *testing.T) {
+	p := NewProcessor(WithSchemeAliases(map[string]string{"u": "urn"}))
+	u, err := p.Parse("u:orders:1:vendor:amazon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "urn:orders:1:vendor:amazon" || u.Raw() != "u:orders:1:vendor:amazon" {
+		t.Errorf("unexpected URN: %s (raw %s)", u.String(), u.Raw())
+	}
+	if _, err := p.ParseStrict("U:orders:1"); err != nil {
+		t.Errorf("expected alias to match case-insensitively: %v", err)
+	}
+	if _, err := p.Parse("urn:orders:1"); err != nil {
+		t.Errorf("expected urn scheme to stay accepted: %v", err)
+	}
+}
+
+func TestSchemeAliasRejectsUnknown(t *testing.T) {
+	p := NewProcessor(WithSchemeAliases(map[string]string{"u": "urn", "x": "other"}))
+	for _, s := range []string{"x:orders:1", "v:orders:1", ":orders:1"} {
+		if _, err := p.Parse(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+	if _, err := Parse("u:orders:1"); err == nil {
+		t.Error("default parsing must stay strictly urn:")
+	}
+}
+
+func TestSchemeAliasErrorOffsets(t *testing.T) {
+	p := NewProcessor(WithSchemeAliases(map[string]string{"u": "urn"}))
+	_, err := p.Parse("u:orders:1:status")
+	ue, ok := err.(*InvalidURNError)
+	if !ok || ue.Offset != 11 {
+		t.Errorf("unexpected error: %#v", err)
+	}
+}
+
+func TestOutputScheme(t *testing.T) {
+	p := NewProcessor(WithSchemeAliases(map[string]string{"u": "urn"}), WithOutputScheme("u"))
+	s, err := p.ComposeAttrs("orders", "1", []Attribute{{Key: "vendor", Value: "amazon"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "u:orders:1:vendor:amazon" {
+		t.Errorf("unexpected output: %s", s)
+	}
+	s, _ = p.AddAttribute("urn:orders:1", "status", "new")
+	if s != "u:orders:1:status:new" {
+		t.Errorf("unexpected output: %s", s)
+	}
+	u, _ := Parse("urn:orders:1")
+	if f, _ := p.Format(u); f != "u:orders:1" {
+		t.Errorf("unexpected Format output: %s", f)
+	}
+}
+
+func TestProcessorWithLeavesParentUnchanged(t *testing.T) {
+	base := NewProcessor(WithOutputScheme("urx"))
+	child := base.With(RejectControlChars(), WithMaxSegments(4))
+
+	if s, _ := child.Compose("orders", "1"); s != "urx:orders:1" {
+		t.Errorf("child Compose = %q, want inherited scheme", s)
+	}
+	if child.IsValid("urn:orders:1:k:%01") || child.IsValid("urn:orders:1:a:b") {
+		t.Error("child accepted input its overrides reject")
+	}
+	if !base.IsValid("urn:orders:1:k:%01") || !base.IsValid("urn:orders:1:a:b") {
+		t.Error("parent picked up the child's overrides")
+	}
+
+	grandchild := child.With(WithOutputScheme("urn"))
+	if s, _ := grandchild.Compose("orders", "1"); s != "urn:orders:1" {
+		t.Errorf("grandchild Compose = %q", s)
+	}
+	if s, _ := child.Compose("orders", "1"); s != "urx:orders:1" {
+		t.Errorf("child Compose after grandchild = %q", s)
+	}
+}
+
+func TestProcessorWithFromDefaults(t *testing.T) {
+	SetDefaults(RejectControlChars())
+	t.Cleanup(func() { SetDefaults() })
+
+	p := defaultProcessor.With()
+	SetDefaults()
+	if p.IsValid("urn:orders:1:k:%01") {
+		t.Error("child of the default Processor lost the defaults it started from")
+	}
+}
+
+func TestProcessorWithRegistries(t *testing.T) {
+	t.Cleanup(func() { RegisterIDValidator("orders", nil) })
+	shared := NewProcessor().With()
+	copied := NewProcessor().With(CopyRegistries())
+	RegisterIDValidator("orders", ObjectIDValidator)
+
+	if shared.IsValid("urn:orders:1") {
+		t.Error("shared registries did not see the new validator")
+	}
+	if !copied.IsValid("urn:orders:1") {
+		t.Error("copied registries saw a validator registered later")
+	}
+
+	// A child of a Processor with copied registries keeps the parent's copy
+	// unless it asks for the live ones.
+	if !copied.With().IsValid("urn:orders:1") {
+		t.Error("child did not inherit the parent's registry copy")
+	}
+	if copied.With(ShareRegistries()).IsValid("urn:orders:1") {
+		t.Error("ShareRegistries child did not see the live registries")
+	}
+}
@@ -0,0 +1,33 @@
+package urn
+
+// Project returns a copy of the URN holding only the attributes whose keys
+// are listed, in the URN's original order.
+func (u *URN) Project(keys ...string) *URN {
+	keep := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		keep[k] = struct{}{}
+	}
+	c := &URN{Entity: u.Entity, ID: u.ID, parsedEntity: u.parsedEntity}
+	for _, p := range u.attributes {
+		if _, ok := keep[p.Key]; ok {
+			c.attributes = append(c.attributes, p)
+		}
+	}
+	return c
+}
+
+// KeepAttributes returns the URN with only the listed attributes, preserving
+// their original relative order.
+func KeepAttributes(urnStr string, keys ...string) (string, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return "", err
+	}
+	p := u.Project(keys...)
+	return compose(p.Entity, p.ID, p.attributes)
+}
+
+// StripAttributes returns just "urn:entity:id".
+func StripAttributes(urnStr string) (string, error) {
+	return KeepAttributes(urnStr)
+}
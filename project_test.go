@@ -0,0 +1,47 @@
+package urn
+
+import "testing"
+
+func TestKeepAttributes(t *testing.T) {
+	kept, err := KeepAttributes("urn:orders:1:vendor:amazon:debug:1:status:shipped", "status", "vendor", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept != "urn:orders:1:vendor:amazon:status:shipped" {
+		t.Errorf("unexpected result: %s", kept)
+	}
+}
+
+func TestKeepAttributesEmptyAllowlist(t *testing.T) {
+	kept, err := KeepAttributes("urn:orders:1:vendor:amazon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept != "urn:orders:1" {
+		t.Errorf("unexpected result: %s", kept)
+	}
+}
+
+func TestStripAttributes(t *testing.T) {
+	stripped, err := StripAttributes("urn:orders:1:vendor:amazon:status:shipped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stripped != "urn:orders:1" {
+		t.Errorf("unexpected result: %s", stripped)
+	}
+	if _, err := StripAttributes("invalid"); err == nil {
+		t.Error("expected error for invalid URN")
+	}
+}
+
+func TestProject(t *testing.T) {
+	u, _ := Parse("urn:orders:1:a:1:b:2:c:3")
+	p := u.Project("c", "a")
+	if p.String() != "urn:orders:1:a:1:c:3" {
+		t.Errorf("unexpected projection: %s", p.String())
+	}
+	if u.String() != "urn:orders:1:a:1:b:2:c:3" {
+		t.Errorf("original was modified: %s", u.String())
+	}
+}
@@ -0,0 +1,119 @@
+package urn
+
+import "strings"
+
+// WithQuoting enables quoted attribute values, for URNs read by people:
+// a value containing ':' is written between double quotes with its colons
+// left raw, as in `urn:job:1:at:"2024-01-02T03:04:05Z"`, instead of
+// percent-encoding them. Everything else in the value, including any '"',
+// is escaped as usual, so the first raw '"' closes the value.
+//
+// Compose and Format under a Processor with this option emit quoted
+// values, and Parse accepts them; (*URN).HasQuotedValues reports that an
+// input used them. Without the option a quote is an ordinary character.
+// String always emits the standard form.
+func WithQuoting() Option {
+	return func(o *options) {
+		o.quoting = true
+	}
+}
+
+// HasQuotedValues reports whether the URN was parsed under WithQuoting from
+// input with at least one quoted value.
+func (u *URN) HasQuotedValues() bool {
+	return u.quoted
+}
+
+// parseQuoted parses urnStr after rewriting its quoted values to the
+// standard escaped form. Offsets in errors after a quoted value refer to
+// the rewritten form.
+func parseQuoted(urnStr string, o *options) (*URN, error) {
+	plain := *o
+	plain.quoting = false
+	_, _, off, err := parseHead(urnStr, &plain)
+	if err != nil || off < 0 || !strings.Contains(urnStr[off:], `"`) {
+		return parseURN(urnStr, &plain)
+	}
+
+	var b strings.Builder
+	b.WriteString(urnStr[:off])
+	quoted := false
+	for i, n := off, 0; ; n++ {
+		if n%2 == 1 && strings.HasPrefix(urnStr[i:], `"`) {
+			j := strings.IndexByte(urnStr[i+1:], '"')
+			if j < 0 {
+				return nil, &InvalidURNError{
+					Kind:    KindMalformedEscape,
+					Reason:  ReasonUnterminatedQuote,
+					Offset:  i,
+					Segment: urnStr[i:],
+				}
+			}
+			end := i + 1 + j
+			if end+1 < len(urnStr) && urnStr[end+1] != ':' {
+				return nil, &InvalidURNError{
+					Kind:    KindMalformedEscape,
+					Reason:  ReasonDataAfterQuote,
+					Offset:  end + 1,
+					Segment: urnStr[i:],
+				}
+			}
+			b.WriteString(strings.ReplaceAll(urnStr[i+1:end], ":", "%3A"))
+			quoted = true
+			if end+1 == len(urnStr) {
+				break
+			}
+			b.WriteByte(':')
+			i = end + 2
+			continue
+		}
+		seg, next := nextSegment(urnStr, i)
+		b.WriteString(seg)
+		if next < 0 {
+			break
+		}
+		b.WriteByte(':')
+		i = next
+	}
+
+	u, err := parseURN(b.String(), &plain)
+	if err != nil {
+		return nil, err
+	}
+	u.quoted = quoted
+	if u.raw != "" {
+		u.raw = urnStr
+	}
+	return u, nil
+}
+
+// composeQuoted is composeScheme with values containing ':' quoted.
+func composeQuoted(scheme, entity, id string, pairs []Attribute) (string, error) {
+	s, err := composeScheme(scheme, entity, id, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := checkLength(entity, id, pairs, MaxURNLength); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(s)
+	for _, p := range pairs {
+		b.WriteByte(':')
+		writeEscape(&b, p.Key)
+		b.WriteByte(':')
+		if !strings.Contains(p.Value, ":") {
+			writeEscape(&b, p.Value)
+			continue
+		}
+		b.WriteByte('"')
+		for i, part := range strings.Split(p.Value, ":") {
+			if i > 0 {
+				b.WriteByte(':')
+			}
+			writeEscape(&b, part)
+		}
+		b.WriteByte('"')
+	}
+	return b.String(), nil
+}
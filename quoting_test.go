@@ -0,0 +1,85 @@
+package urn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuotingRoundTrip(t *testing.T) {
+	p := NewProcessor(WithQuoting())
+	tests := []struct {
+		value, quoted string
+	}{
+		{"2024-01-02T03:04:05Z", `"2024-01-02T03:04:05Z"`},
+		{`say "hi"`, `say%20%22hi%22`},
+		{`a:"b":c`, `"a:%22b%22:c"`},
+		{":", `":"`},
+		{"plain", "plain"},
+	}
+	for _, tt := range tests {
+		s, err := p.ComposeAttrs("job", "1", []Attribute{{"at", tt.value}, {"n", "2"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "urn:job:1:at:" + tt.quoted + ":n:2"; s != want {
+			t.Errorf("Compose(%q) = %s, want %s", tt.value, s, want)
+		}
+		u, err := p.Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%s): %v", s, err)
+		}
+		if v, _ := u.get("at"); v != tt.value || u.GetOr("n", "") != "2" {
+			t.Errorf("Parse(%s) = %v", s, u.AttributePairs())
+		}
+		if u.HasQuotedValues() != (tt.quoted[0] == '"') {
+			t.Errorf("Parse(%s).HasQuotedValues() = %v", s, u.HasQuotedValues())
+		}
+		if u.Raw() != s {
+			t.Errorf("Raw() = %s", u.Raw())
+		}
+		if f, _ := p.Format(u); f != s {
+			t.Errorf("Format = %s", f)
+		}
+		if got, _ := Parse(u.String()); !got.Equal(u) {
+			t.Errorf("standard form %s does not round-trip", u.String())
+		}
+	}
+}
+
+func TestQuotingOptIn(t *testing.T) {
+	const s = `urn:job:1:at:"2024-01-02T03:04:05Z"`
+	if u, _ := Parse(s); u.GetOr("at", "") != `"2024-01-02T03` || u.HasQuotedValues() {
+		t.Errorf("Parse unwrapped quotes without WithQuoting: %v", u.AttributePairs())
+	}
+	if s, _ := Compose("job", "1", map[string]string{"at": "a:b"}); s != "urn:job:1:at:a%3Ab" {
+		t.Errorf("Compose quoted without WithQuoting: %s", s)
+	}
+	u, _ := Parse(`urn:job:"1":k:"v"`)
+	if u.ID != `"1"` || u.GetOr("k", "") != `"v"` || u.HasQuotedValues() {
+		t.Errorf("quotes not literal without WithQuoting: %v", u)
+	}
+}
+
+func TestQuotingErrors(t *testing.T) {
+	p := NewProcessor(WithQuoting())
+	tests := []struct {
+		in     string
+		kind   ErrorKind
+		offset int
+	}{
+		{`urn:job:1:at:"a:b`, KindMalformedEscape, 13},
+		{`urn:job:1:at:"a"b:k:v`, KindMalformedEscape, 16},
+		{`urn:job:1:at:""`, KindEmptyAttribute, 13},
+	}
+	for _, tt := range tests {
+		_, err := p.Parse(tt.in)
+		var e *InvalidURNError
+		if !errors.As(err, &e) || e.Kind != tt.kind || e.Offset != tt.offset {
+			t.Errorf("Parse(%s) = %#v", tt.in, err)
+		}
+	}
+	// A quote opening a key is an ordinary character.
+	if u, err := p.Parse(`urn:job:1:"k":v`); err != nil || u.GetOr(`"k"`, "") != "v" {
+		t.Errorf("quoted key: %v, %v", u, err)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package urn
+
+// raceEnabled reports whether the race detector is on. It instruments
+// allocations, so tests asserting allocation counts skip under it.
+const raceEnabled = true
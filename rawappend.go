@@ -0,0 +1,86 @@
+package urn
+
+// AppendAttributeRaw appends the attribute key=value to urnStr without
+// decoding or re-encoding what is already there, so existing escapes such
+// as a lowercase %2f reach downstream consumers byte for byte. Only the
+// new pair is escaped, as Compose would, and validated against the key
+// policy. The existing portion gets a structural check: a known scheme,
+// non-empty entity, ID and attribute segments, and complete key/value
+// pairs. The result must stay within MaxURNLength and the segment limit.
+// An existing attribute with the same key is not replaced.
+func AppendAttributeRaw(urnStr, key, value string) (string, error) {
+	o, c := defaultProcessor.snapshot()
+	pair := []Attribute{{Key: key, Value: value}}
+	if err := validatePairs(c, pair); err != nil {
+		return "", err
+	}
+	start, segs, err := checkStructure(urnStr, o)
+	if err != nil {
+		return "", err
+	}
+	if limit := o.segmentLimit(); 1+segs+2 > limit {
+		return "", &InvalidURNError{
+			Kind:   KindTooManySegments,
+			Op:     OpCompose,
+			Offset: len(urnStr),
+			Limit:  limit,
+			Err:    ErrTooManySegments,
+		}
+	}
+	b := make([]byte, 0, len(urnStr)+2+escapedLen(key)+escapedLen(value))
+	b = append(b, urnStr...)
+	b = append(b, ':')
+	b = appendEscape(b, key)
+	b = append(b, ':')
+	b = appendEscape(b, value)
+	if n := len(b) - start + len("urn:"); n > MaxURNLength {
+		return "", &InvalidURNError{
+			Kind:   KindTooLong,
+			Op:     OpCompose,
+			Limit:  MaxURNLength,
+			Actual: n,
+		}
+	}
+	return string(b), nil
+}
+
+// checkStructure checks the segment layout of urnStr without decoding
+// it, returning the offset past the scheme and the number of segments
+// after it.
+func checkStructure(urnStr string, o *options) (start, segs int, err error) {
+	start = o.schemeEnd(urnStr)
+	if start < 0 {
+		return 0, 0, &InvalidURNError{
+			Kind: KindScheme,
+		}
+	}
+	for i := start; i >= 0; segs++ {
+		seg, next := nextSegment(urnStr, i)
+		if seg == "" {
+			if segs < 2 {
+				return 0, 0, &InvalidURNError{
+					Kind:   KindEmptyComponent,
+					Offset: i,
+				}
+			}
+			return 0, 0, &InvalidURNError{
+				Kind:   KindEmptyAttribute,
+				Offset: i,
+			}
+		}
+		i = next
+	}
+	if segs < 2 {
+		return 0, 0, &InvalidURNError{
+			Kind:   KindMissingComponent,
+			Offset: len(urnStr),
+		}
+	}
+	if segs%2 != 0 {
+		return 0, 0, &InvalidURNError{
+			Kind:   KindUnpairedKey,
+			Offset: len(urnStr),
+		}
+	}
+	return start, segs, nil
+}
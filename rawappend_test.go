@@ -0,0 +1,79 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAppendAttributeRaw(t *testing.T) {
+	tests := []struct {
+		in, key, value, want string
+	}{
+		// Lowercase and unnecessary escapes are left exactly as they were.
+		{"urn:file:a%2fb:path:%2e%2e", "trace", "t-1", "urn:file:a%2fb:path:%2e%2e:trace:t-1"},
+		{"URN:File:1", "trace", "a:b", "URN:File:1:trace:a%3Ab"},
+		{"urn:user:1:trace:old", "trace", "new", "urn:user:1:trace:old:trace:new"},
+	}
+	for _, tt := range tests {
+		got, err := AppendAttributeRaw(tt.in, tt.key, tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("AppendAttributeRaw(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+		if !strings.HasPrefix(got, tt.in+":") {
+			t.Errorf("existing bytes changed: %q", got)
+		}
+	}
+
+	// Parse/Compose would have normalized the escapes.
+	viaAdd, _ := AddAttribute("urn:file:a%2fb", "trace", "t-1")
+	if viaAdd == "urn:file:a%2fb:trace:t-1" {
+		t.Error("AddAttribute kept the lowercase escape; the test no longer shows the difference")
+	}
+}
+
+func TestAppendAttributeRawStructure(t *testing.T) {
+	tests := []struct {
+		in   string
+		kind ErrorKind
+	}{
+		{"", KindScheme},
+		{"urx:user:1", KindScheme},
+		{"urn:user", KindMissingComponent},
+		{"urn::1", KindEmptyComponent},
+		{"urn:user:", KindEmptyComponent},
+		{"urn:user:1:k", KindUnpairedKey},
+		{"urn:user:1:k:", KindEmptyAttribute},
+		{"urn:user:1::v", KindEmptyAttribute},
+	}
+	for _, tt := range tests {
+		_, err := AppendAttributeRaw(tt.in, "trace", "t")
+		var ue *InvalidURNError
+		if !errors.As(err, &ue) || ue.Kind != tt.kind {
+			t.Errorf("AppendAttributeRaw(%q) err = %v, want kind %v", tt.in, err, tt.kind)
+		}
+	}
+}
+
+func TestAppendAttributeRawLimits(t *testing.T) {
+	base := "urn:user:" + strings.Repeat("x", MaxURNLength-len("urn:user:")-len(":k:vv"))
+	if _, err := AppendAttributeRaw(base, "k", "vv"); err != nil {
+		t.Errorf("exact fit: %v", err)
+	}
+	_, err := AppendAttributeRaw(base, "k", "v:")
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Kind != KindTooLong || ue.Actual != MaxURNLength+2 {
+		t.Errorf("escaped value over the limit: %v", err)
+	}
+
+	long := "urn:user:1" + strings.Repeat(":k:v", (DefaultMaxSegments-3)/2)
+	if _, err := AppendAttributeRaw(long, "k", "v"); !errors.Is(err, ErrTooManySegments) {
+		t.Errorf("segment limit: %v", err)
+	}
+
+	for _, kv := range [][2]string{{"", "v"}, {"k", ""}} {
+		if _, err := AppendAttributeRaw("urn:user:1", kv[0], kv[1]); err == nil {
+			t.Errorf("accepted pair %q", kv)
+		}
+	}
+}
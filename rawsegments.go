@@ -0,0 +1,63 @@
+package urn
+
+import "strings"
+
+// rawSegments splits the retained input into its undecoded segments after
+// the scheme, or returns nil when no input was retained.
+func (u *URN) rawSegments() []string {
+	if u.raw == "" {
+		return nil
+	}
+	return strings.Split(u.raw[strings.IndexByte(u.raw, ':')+1:], u.dialect.separator())
+}
+
+// rawOr returns raw when it decodes to decoded, and otherwise the escaped
+// form of decoded. This keeps the accessors truthful for URNs modified
+// after parsing.
+func rawOr(raw, decoded string) string {
+	if v, err := unescape(raw); err == nil && v == decoded {
+		return raw
+	}
+	return escape(decoded)
+}
+
+// RawEntity returns the entity exactly as it appeared in the parsed input,
+// escapes included. For URNs that were built, modified, or parsed with
+// DiscardRaw, it returns the escaped form String would emit.
+func (u *URN) RawEntity() string {
+	if segs := u.rawSegments(); len(segs) >= 2 {
+		return rawOr(segs[0], u.Entity)
+	}
+	return escape(u.Entity)
+}
+
+// RawID returns the ID exactly as it appeared in the parsed input. See
+// RawEntity.
+func (u *URN) RawID() string {
+	if segs := u.rawSegments(); len(segs) >= 2 {
+		return rawOr(segs[1], u.ID)
+	}
+	return escape(u.ID)
+}
+
+// RawAttributePairs returns the attributes exactly as they appeared in the
+// parsed input, in order. See RawEntity; when the attributes no longer
+// line up with the input, every pair is returned in escaped form.
+func (u *URN) RawAttributePairs() []Attribute {
+	if len(u.attributes) == 0 {
+		return nil
+	}
+	segs := u.rawSegments()
+	if len(segs) != 2+2*len(u.attributes) {
+		segs = nil
+	}
+	pairs := make([]Attribute, len(u.attributes))
+	for i, p := range u.attributes {
+		if segs == nil {
+			pairs[i] = Attribute{Key: escape(p.Key), Value: escape(p.Value)}
+			continue
+		}
+		pairs[i] = Attribute{Key: rawOr(segs[2+2*i], p.Key), Value: rawOr(segs[3+2*i], p.Value)}
+	}
+	return pairs
+}
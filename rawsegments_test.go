@@ -0,0 +1,49 @@
+package urn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRawSegmentAccessors(t *testing.T) {
+	u, err := Parse("urn:orders:a%2Fb:path:x%2fy:note:%41")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != "a/b" || u.RawID() != "a%2Fb" {
+		t.Errorf("ID %q, RawID %q", u.ID, u.RawID())
+	}
+	if u.RawEntity() != "orders" {
+		t.Errorf("RawEntity %q", u.RawEntity())
+	}
+	want := []Attribute{{"path", "x%2fy"}, {"note", "%41"}}
+	if got := u.RawAttributePairs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RawAttributePairs = %v, want %v", got, want)
+	}
+	if got := u.AttributePairs(); got[0].Value != "x/y" || got[1].Value != "A" {
+		t.Errorf("decoded pairs %v", got)
+	}
+}
+
+func TestRawSegmentAccessorsWithoutInput(t *testing.T) {
+	u, _ := NewBuilder("orders", "a/b").Attr("path", "x/y").Build()
+	if u.RawID() != "a%2Fb" {
+		t.Errorf("RawID %q", u.RawID())
+	}
+	if got := u.RawAttributePairs(); !reflect.DeepEqual(got, []Attribute{{"path", "x%2Fy"}}) {
+		t.Errorf("RawAttributePairs %v", got)
+	}
+
+	p := NewProcessor(DiscardRaw())
+	u, _ = p.Parse("urn:orders:a%2fb")
+	if u.RawID() != "a%2Fb" {
+		t.Errorf("RawID with DiscardRaw %q", u.RawID())
+	}
+
+	// A modified URN reports the escaped form of the new value.
+	u, _ = Parse("urn:orders:a%2fb")
+	u.ID = "c/d"
+	if u.RawID() != "c%2Fd" {
+		t.Errorf("RawID after modification %q", u.RawID())
+	}
+}
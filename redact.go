@@ -0,0 +1,66 @@
+package urn
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// RedactionMask replaces the hidden part of a value redacted by
+// RedactPartial. It is a single character whatever the length hidden, so
+// the output does not reveal the length of the value.
+const RedactionMask = "…"
+
+// minPartialRedactLen is the shortest value, in runes, RedactPartial
+// reveals any of; shorter values give too much away with even one
+// character shown.
+const minPartialRedactLen = 4
+
+// RedactPartial masks the values of the attributes whose key is in keys,
+// keeping their first keepPrefix and last keepSuffix characters around
+// RedactionMask, as in "sk_live_ab…yz". Characters are counted as decoded
+// runes, so multi-byte characters are never split. At most half of a
+// value is revealed: when keepPrefix+keepSuffix exceeds that, the suffix
+// is shortened first, then the prefix. Values shorter than four runes are
+// replaced by RedactionMask entirely. Every attribute is parsed, even under
+// MaxAttributesParsed, so none escapes redaction; the result is composed
+// as by AddAttribute and is a valid URN.
+func RedactPartial(urnStr string, keys []string, keepPrefix, keepSuffix int) (string, error) {
+	o, _ := defaultProcessor.snapshot()
+	all := *o
+	all.maxAttrs = 0
+	u, err := parse(urnStr, &all)
+	if err != nil {
+		return "", err
+	}
+	for i, p := range u.attributes {
+		if slices.Contains(keys, p.Key) {
+			u.attributes[i].Value = redactValue(p.Value, max(keepPrefix, 0), max(keepSuffix, 0))
+		}
+	}
+	return composeURN(o.scheme(), u)
+}
+
+// redactValue masks the middle of v, revealing at most half of its runes.
+func redactValue(v string, keepPrefix, keepSuffix int) string {
+	n := utf8.RuneCountInString(v)
+	if n < minPartialRedactLen {
+		return RedactionMask
+	}
+	budget := n / 2
+	keepPrefix = min(keepPrefix, budget)
+	keepSuffix = min(keepSuffix, budget-keepPrefix)
+	if keepPrefix == 0 && keepSuffix == 0 {
+		return RedactionMask
+	}
+	start := 0
+	for range keepPrefix {
+		_, w := utf8.DecodeRuneInString(v[start:])
+		start += w
+	}
+	end := len(v)
+	for range keepSuffix {
+		_, w := utf8.DecodeLastRuneInString(v[:end])
+		end -= w
+	}
+	return v[:start] + RedactionMask + v[end:]
+}
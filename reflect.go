@@ -0,0 +1,251 @@
+package urn
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError reports a struct field that could not be mapped to or from a
+// URN component.
+type FieldError struct {
+	Field string
+	Tag   string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("Cannot map field %s (urn:%q): %s", e.Field, e.Tag, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// urnField describes one urn-tagged struct field.
+type urnField struct {
+	index     int
+	name      string
+	tag       string
+	part      string // "entity", "id" or "attr"
+	key       string
+	omitEmpty bool
+}
+
+// urnFields returns the tagged fields of t and whether any carries the
+// "strict" option.
+func urnFields(t reflect.Type) ([]urnField, bool, error) {
+	var fields []urnField
+	strict := false
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("urn")
+		if !ok || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		f := urnField{index: i, name: sf.Name, tag: tag}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "strict":
+				strict = true
+			case "":
+			default:
+				return nil, false, &FieldError{Field: sf.Name, Tag: tag, Err: fmt.Errorf("unknown option %q", opt)}
+			}
+		}
+		switch {
+		case name == "entity" || name == "id":
+			f.part = name
+		case strings.HasPrefix(name, "attr:") && len(name) > len("attr:"):
+			f.part, f.key = "attr", name[len("attr:"):]
+		case name == "":
+			continue
+		default:
+			return nil, false, &FieldError{Field: sf.Name, Tag: tag, Err: fmt.Errorf("unknown component %q", name)}
+		}
+		if !sf.IsExported() {
+			return nil, false, &FieldError{Field: sf.Name, Tag: tag, Err: fmt.Errorf("field is unexported")}
+		}
+		fields = append(fields, f)
+	}
+	return fields, strict, nil
+}
+
+// MarshalURN composes a URN from a struct whose fields carry urn tags:
+// `urn:"entity"`, `urn:"id"` and `urn:"attr:<key>"`. Supported field types
+// are string, integer kinds, bool and time.Time (RFC 3339), or pointers to
+// them. Attributes are emitted in field order. A nil pointer or empty
+// string is an error unless the tag has ",omitempty", which also skips
+// zero integers and false booleans.
+func MarshalURN(v any) (string, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return "", fmt.Errorf("Cannot marshal URN: %T is not a struct", v)
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "", fmt.Errorf("Cannot marshal URN: nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return "", fmt.Errorf("Cannot marshal URN: %s is not a struct", rv.Type())
+	}
+	fields, _, err := urnFields(rv.Type())
+	if err != nil {
+		return "", err
+	}
+
+	var entity, id string
+	var attrs []Attribute
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		s, present, err := formatField(fv)
+		if err != nil {
+			return "", &FieldError{Field: f.name, Tag: f.tag, Err: err}
+		}
+		if !present || (f.omitEmpty && fv.IsZero()) {
+			if f.omitEmpty && f.part == "attr" {
+				continue
+			}
+			return "", &FieldError{Field: f.name, Tag: f.tag, Err: fmt.Errorf("required value is empty")}
+		}
+		switch f.part {
+		case "entity":
+			entity = s
+		case "id":
+			id = s
+		default:
+			attrs = append(attrs, Attribute{Key: f.key, Value: s})
+		}
+	}
+	return ComposeAttrs(entity, id, attrs)
+}
+
+// formatField converts a field value to its string form. present is false
+// for nil pointers and empty strings.
+func formatField(fv reflect.Value) (s string, present bool, err error) {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return "", false, nil
+		}
+		fv = fv.Elem()
+	}
+	if fv.Type() == timeType {
+		return fv.Interface().(time.Time).Format(time.RFC3339Nano), true, nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), fv.String() != "", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fv.Uint(), 10), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), true, nil
+	}
+	return "", false, fmt.Errorf("unsupported type %s", fv.Type())
+}
+
+// UnmarshalURN parses urnStr into the struct pointed to by v, using the
+// same tags as MarshalURN. A missing attribute is an error unless its tag
+// has ",omitempty". Attributes without a matching field are ignored unless
+// any tag carries ",strict" (conventionally the entity tag).
+func UnmarshalURN(urnStr string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Cannot unmarshal URN: need a non-nil pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+	fields, strict, err := urnFields(rv.Type())
+	if err != nil {
+		return err
+	}
+	u, err := Parse(urnStr)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		var s string
+		switch f.part {
+		case "entity":
+			s = u.Entity
+		case "id":
+			s = u.ID
+		default:
+			known[f.key] = true
+			var found bool
+			if s, found = u.get(f.key); !found {
+				if f.omitEmpty {
+					continue
+				}
+				return &FieldError{Field: f.name, Tag: f.tag, Err: fmt.Errorf("attribute %q is missing", f.key)}
+			}
+		}
+		if err := setField(rv.Field(f.index), s); err != nil {
+			return &FieldError{Field: f.name, Tag: f.tag, Err: err}
+		}
+	}
+	if strict {
+		for _, p := range u.attributes {
+			if !known[p.Key] {
+				return fmt.Errorf("Cannot unmarshal URN: unknown attribute %q", p.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// setField parses s into fv, allocating pointers as needed.
+func setField(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Pointer {
+		p := reflect.New(fv.Type().Elem())
+		if err := setField(p.Elem(), s); err != nil {
+			return err
+		}
+		fv.Set(p)
+		return nil
+	}
+	if fv.Type() == timeType {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type orderEvent struct {
+	Entity   string     `urn:"entity"`
+	ID       int64      `urn:"id"`
+	Vendor   string     `urn:"attr:vendor"`
+	Quantity int        `urn:"attr:qty"`
+	Express  bool       `urn:"attr:express,omitempty"`
+	Shipped  *time.Time `urn:"attr:shipped,omitempty"`
+	Note     *string    `urn:"attr:note,omitempty"`
+	Internal string
+}
+
+func TestMarshalURN(t *testing.T) {
+	shipped := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	s, err := MarshalURN(&orderEvent{
+		Entity:   "orders",
+		ID:       42,
+		Vendor:   "amazon",
+		Quantity: 0,
+		Shipped:  &shipped,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "urn:orders:42:vendor:amazon:qty:0:shipped:2024-01-02T03%3A04%3A05Z"
+	if s != want {
+		t.Errorf("got %s, want %s", s, want)
+	}
+}
+
+func TestUnmarshalURN(t *testing.T) {
+	var ev orderEvent
+	err := UnmarshalURN("urn:orders:42:vendor:amazon:qty:3:express:true:note:fragile:shipped:2024-01-02T03%3A04%3A05Z:extra:1", &ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Entity != "orders" || ev.ID != 42 || ev.Vendor != "amazon" || ev.Quantity != 3 || !ev.Express {
+		t.Errorf("unexpected struct: %+v", ev)
+	}
+	if ev.Note == nil || *ev.Note != "fragile" {
+		t.Errorf("unexpected note: %v", ev.Note)
+	}
+	if ev.Shipped == nil || !ev.Shipped.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected shipped time: %v", ev.Shipped)
+	}
+
+	round, err := MarshalURN(ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again orderEvent
+	if err := UnmarshalURN(round, &again); err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != ev.ID || *again.Note != *ev.Note {
+		t.Errorf("round trip mismatch: %+v", again)
+	}
+}
+
+func TestUnmarshalURNMissingRequired(t *testing.T) {
+	var ev orderEvent
+	err := UnmarshalURN("urn:orders:42:qty:1", &ev)
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Field != "Vendor" {
+		t.Errorf("expected FieldError for Vendor, got %v", err)
+	}
+}
+
+func TestUnmarshalURNConversionFailure(t *testing.T) {
+	var ev orderEvent
+	err := UnmarshalURN("urn:orders:42:vendor:x:qty:many", &ev)
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Field != "Quantity" {
+		t.Errorf("expected FieldError for Quantity, got %v", err)
+	}
+	if err := UnmarshalURN("urn:orders:abc:vendor:x:qty:1", &ev); err == nil {
+		t.Error("expected error for non-numeric ID")
+	}
+}
+
+func TestUnmarshalURNStrict(t *testing.T) {
+	type strictRef struct {
+		Entity string `urn:"entity,strict"`
+		ID     string `urn:"id"`
+		Vendor string `urn:"attr:vendor,omitempty"`
+	}
+	var ref strictRef
+	if err := UnmarshalURN("urn:orders:1:vendor:amazon", &ref); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := UnmarshalURN("urn:orders:1:vendor:amazon:debug:1", &ref)
+	if err == nil || !strings.Contains(err.Error(), `unknown attribute "debug"`) {
+		t.Errorf("expected unknown attribute error, got %v", err)
+	}
+}
+
+func TestMarshalURNErrors(t *testing.T) {
+	if _, err := MarshalURN(orderEvent{Entity: "orders", ID: 1}); err == nil {
+		t.Error("expected error for empty required attribute")
+	}
+	if _, err := MarshalURN("not a struct"); err == nil {
+		t.Error("expected error for non-struct")
+	}
+	if _, err := MarshalURN(nil); err == nil {
+		t.Error("expected error for nil")
+	}
+	if _, err := MarshalURN((*orderEvent)(nil)); err == nil {
+		t.Error("expected error for nil pointer")
+	}
+	type bad struct {
+		Entity string  `urn:"entity"`
+		ID     float64 `urn:"id"`
+	}
+	if _, err := MarshalURN(bad{Entity: "orders", ID: 1}); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+	var ev orderEvent
+	if err := UnmarshalURN("urn:orders:1", ev); err == nil {
+		t.Error("expected error for non-pointer target")
+	}
+}
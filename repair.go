@@ -0,0 +1,64 @@
+package urn
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// RepairDoubleEscaping rewrites segments of urnStr that were percent-encoded
+// twice, as the old AddAttribute did, so "john%2520doe" becomes
+// "john%20doe". A segment is repaired only when its decoded form still
+// contains %XX sequences and decoding once more succeeds, yields valid
+// UTF-8 and leaves no %XX behind. Segments that decode once to a value
+// without %XX, such as "100%25", are copied byte for byte, as are the
+// scheme and all unrepaired segments. The boolean reports whether anything
+// was repaired.
+//
+// A value that legitimately contained a literal "%20" before escaping is
+// indistinguishable from a double-escaped space and will be repaired.
+func RepairDoubleEscaping(urnStr string) (string, bool, error) {
+	if _, err := Parse(urnStr); err != nil {
+		return "", false, err
+	}
+	segs := strings.Split(urnStr, ":")
+	repaired := false
+	for i := 1; i < len(segs); i++ {
+		if fixed, ok := repairSegment(segs[i]); ok {
+			segs[i] = fixed
+			repaired = true
+		}
+	}
+	if !repaired {
+		return urnStr, false, nil
+	}
+	return strings.Join(segs, ":"), true, nil
+}
+
+// repairSegment returns the single-escaped form of a double-escaped
+// segment, or false when the segment does not look double-escaped.
+func repairSegment(seg string) (string, bool) {
+	once, err := unescape(seg)
+	if err != nil || !hasPercentEscape(once) {
+		return "", false
+	}
+	twice, err := url.PathUnescape(once)
+	if err != nil || hasPercentEscape(twice) || !utf8.ValidString(twice) {
+		return "", false
+	}
+	return escape(twice), true
+}
+
+// hasPercentEscape reports whether s contains a %XX sequence.
+func hasPercentEscape(s string) bool {
+	for i := 0; i+2 < len(s); i++ {
+		if s[i] == '%' && ishex(s[i+1]) && ishex(s[i+2]) {
+			return true
+		}
+	}
+	return false
+}
+
+func ishex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,37 @@
+package urn
+
+import "testing"
+
+func TestRepairDoubleEscaping(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     string
+		repaired bool
+	}{
+		{"urn:orders:1:customer:john%2520doe", "urn:orders:1:customer:john%20doe", true},
+		{"urn:orders:1:customer:john%20doe", "urn:orders:1:customer:john%20doe", false},
+		{"urn:orders:a%252Fb:k:v", "urn:orders:a%2Fb:k:v", true},
+		// Literal percent signs that only decode once are left alone.
+		{"urn:orders:1:discount:100%25", "urn:orders:1:discount:100%25", false},
+		{"urn:orders:1:discount:50%25off", "urn:orders:1:discount:50%25off", false},
+		{"urn:orders:1:a:100%25:b:x%252Fy", "urn:orders:1:a:100%25:b:x%2Fy", true},
+		// Triple escaping leaves %XX after two decodes and is not guessed at.
+		{"urn:orders:1:k:a%252520b", "urn:orders:1:k:a%252520b", false},
+		// Unrepaired segments keep their exact bytes.
+		{"URN:orders:1:p:a%2fb:c:x%2520y", "URN:orders:1:p:a%2fb:c:x%20y", true},
+	}
+	for _, tt := range tests {
+		got, repaired, err := RepairDoubleEscaping(tt.in)
+		if err != nil {
+			t.Errorf("RepairDoubleEscaping(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want || repaired != tt.repaired {
+			t.Errorf("RepairDoubleEscaping(%q) = %q, %v; want %q, %v", tt.in, got, repaired, tt.want, tt.repaired)
+		}
+	}
+
+	if _, _, err := RepairDoubleEscaping("not-a-urn"); err == nil {
+		t.Error("expected error for invalid input")
+	}
+}
@@ -0,0 +1,83 @@
+package urn
+
+import (
+	"strings"
+)
+
+// ReplaceID returns urnStr with its ID replaced by newID. The scheme,
+// entity and attribute section are copied byte for byte, keeping their
+// escapes and order; only newID is validated.
+func ReplaceID(urnStr, newID string) (string, error) {
+	entity, _, entityEnd, idEnd, err := headBounds(urnStr)
+	if err != nil {
+		return "", err
+	}
+	if newID == "" {
+		return "", &InvalidURNError{Kind: KindEmptyComponent, Reason: ReasonID, Op: OpCompose}
+	}
+	if v := loadConfig().idValidator(entity); v != nil {
+		if err := v(newID); err != nil {
+			return "", &InvalidURNError{
+				Kind:    KindInvalidID,
+				Op:      OpCompose,
+				Segment: newID,
+				Entity:  entity,
+				ID:      newID,
+				Err:     err,
+			}
+		}
+	}
+	return splice(urnStr, entityEnd+1, idEnd, escape(newID))
+}
+
+// ReplaceEntity returns urnStr with its entity replaced by newEntity. The
+// scheme, ID and attribute section are copied byte for byte; only
+// newEntity is validated, as Compose validates entities: dotted entities
+// need EntityAllowDots and deprecated aliases need AllowDeprecated.
+func ReplaceEntity(urnStr, newEntity string) (string, error) {
+	_, start, entityEnd, _, err := headBounds(urnStr)
+	if err != nil {
+		return "", err
+	}
+	if newEntity == "" {
+		return "", &InvalidURNError{Kind: KindEmptyComponent, Op: OpCompose}
+	}
+	o, c := defaultProcessor.snapshot()
+	if err := checkEntity(c, o, newEntity, OpCompose); err != nil {
+		return "", err
+	}
+	return splice(urnStr, start, entityEnd, newEntity)
+}
+
+// headBounds validates the head of urnStr and returns the decoded entity
+// and the byte offsets of the raw entity and ID segments. The entity spans
+// [start, entityEnd) and the ID [entityEnd+1, idEnd).
+func headBounds(urnStr string) (entity string, start, entityEnd, idEnd int, err error) {
+	o := &loadConfig().opts
+	entity, _, tailOff, err := parseHead(urnStr, o)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	start = o.schemeEnd(urnStr)
+	entityEnd = start + strings.IndexByte(urnStr[start:], ':')
+	idEnd = len(urnStr)
+	if tailOff >= 0 {
+		idEnd = tailOff - 1
+	}
+	return entity, start, entityEnd, idEnd, nil
+}
+
+// splice replaces urnStr[from:to] with repl, enforcing the length limit.
+func splice(urnStr string, from, to int, repl string) (string, error) {
+	result := urnStr[:from] + repl + urnStr[to:]
+	if len(result) > MaxURNLength {
+		return "", &InvalidURNError{
+			Kind:   KindTooLong,
+			Reason: ReasonComposed,
+			Op:     OpCompose,
+			Limit:  MaxURNLength,
+			Actual: len(result),
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package urn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReplaceIDPreservesAttributes(t *testing.T) {
+	in := "urn:orders:1:path:a%2fb%3Ac:note:x%20y:path:z"
+	got, err := ReplaceID(in, "new:id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "urn:orders:new%3Aid:path:a%2fb%3Ac:note:x%20y:path:z"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if got, _ := ReplaceID("URN:orders:1", "2"); got != "URN:orders:2" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestReplaceEntityPreservesAttributes(t *testing.T) {
+	in := "urn:orders:a%2Fb:tag:%E2%9C%93"
+	got, err := ReplaceEntity(in, "invoices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "urn:invoices:a%2Fb:tag:%E2%9C%93"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestReplaceEntityFollowsDefaults(t *testing.T) {
+	if _, err := ReplaceEntity("urn:orders:1", "shop.orders"); err == nil {
+		t.Error("dotted entity accepted without EntityAllowDots")
+	}
+	SetDefaults(EntityAllowDots())
+	t.Cleanup(func() { SetDefaults() })
+	if got, err := ReplaceEntity("urn:orders:1", "shop.orders"); err != nil || got != "urn:shop.orders:1" {
+		t.Errorf("ReplaceEntity under EntityAllowDots = %q, %v", got, err)
+	}
+
+	if err := SetEntityAliases(map[string]string{"order": "orders"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetEntityAliases(nil) })
+	var ue *InvalidURNError
+	if _, err := ReplaceEntity("urn:orders:1", "Order"); !errors.As(err, &ue) || ue.Reason != ReasonDeprecatedAlias {
+		t.Errorf("deprecated entity: %v", err)
+	}
+}
+
+func TestReplaceErrors(t *testing.T) {
+	if _, err := ReplaceID("urn:orders:1", ""); err == nil {
+		t.Error("expected error for empty ID")
+	}
+	if _, err := ReplaceEntity("urn:orders:1", "bad entity"); err == nil {
+		t.Error("expected error for invalid entity")
+	}
+	if _, err := ReplaceEntity("urn:orders:1", ""); err == nil {
+		t.Error("expected error for empty entity")
+	}
+	if _, err := ReplaceID("not-a-urn", "1"); err == nil {
+		t.Error("expected error for invalid input")
+	}
+
+	RegisterIDValidator("orders", NumericValidator)
+	t.Cleanup(func() { RegisterIDValidator("orders", nil) })
+	_, err := ReplaceID("urn:orders:1:k:v", "abc")
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Kind != KindInvalidID {
+		t.Errorf("expected KindInvalidID, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package urn
+
+import (
+	"strings"
+)
+
+// ResolveReference resolves ref against base, in the spirit of
+// (*url.URL).ResolveReference. A ref starting with the URN scheme is a
+// full URN and is returned, normalized, regardless of base. Otherwise ref
+// has the form "entity:id[:key:value...]" with escaped segments, where an
+// empty entity or ID inherits the base's:
+//
+//	":5678"          base entity, ID 5678
+//	"::vendor:ebay"  base entity and ID, vendor set to ebay
+//	"invoices:9"     entity invoices, ID 9
+//
+// The base's attributes are kept; an attribute in ref replaces the first
+// base attribute with the same key or is appended. An empty ref resolves
+// to base. Keys set by ref are subject to the key policy, as with
+// AddAttribute, and the resolved entity must be well-formed, as with
+// Compose. A ref with more segments than the segment limit allows is
+// rejected before it is split further.
+func ResolveReference(base, ref string) (string, error) {
+	o, c := defaultProcessor.snapshot()
+	b, err := parse(base, o)
+	if err != nil {
+		return "", err
+	}
+	if o.schemeEnd(ref) >= 0 {
+		u, err := parse(ref, o)
+		if err != nil {
+			return "", err
+		}
+		if err := checkEntity(nil, o, u.Entity, OpReference); err != nil {
+			return "", err
+		}
+		return composeURN(o.scheme(), u)
+	}
+
+	// With the scheme, a ref of limit segments is already one too many.
+	limit := o.segmentLimit()
+	segs := strings.SplitN(ref, ":", limit)
+	if len(segs) >= limit {
+		return "", &InvalidURNError{
+			Kind:   KindTooManySegments,
+			Op:     OpReference,
+			Offset: len(ref) - len(segs[limit-1]),
+			Limit:  limit,
+			Err:    ErrTooManySegments,
+		}
+	}
+	if len(segs) > 2 && len(segs)%2 != 0 {
+		return "", &InvalidURNError{
+			Kind:    KindUnpairedKey,
+			Op:      OpReference,
+			Segment: segs[len(segs)-1],
+		}
+	}
+	decoded := make([]string, len(segs))
+	for i, s := range segs {
+		if decoded[i], err = unescape(s); err != nil {
+			return "", err
+		}
+	}
+	if decoded[0] != "" {
+		b.Entity = decoded[0]
+	}
+	if len(decoded) > 1 && decoded[1] != "" {
+		b.ID = decoded[1]
+	}
+	for i := 2; i < len(decoded); i += 2 {
+		key, value := decoded[i], decoded[i+1]
+		if key == "" || value == "" {
+			return "", &InvalidURNError{
+				Kind:    KindEmptyAttribute,
+				Reason:  ReasonValue,
+				Op:      OpReference,
+				Segment: key,
+				Key:     key,
+			}
+		}
+		if err := c.checkKey(key); err != nil {
+			return "", err
+		}
+		b.set(o, key, value)
+	}
+	if err := validateComponentsOpts(nil, o, b.Entity, b.ID, b.attributes); err != nil {
+		return "", err
+	}
+	return composeScheme(o.scheme(), b.Entity, b.ID, b.attributes)
+}
+
+// set replaces the value of the first attribute matching key under o, or
+// appends the attribute.
+func (u *URN) set(o *options, key, value string) {
+	for i, a := range u.attributes {
+		if o.keyEqual(a.Key, key) {
+			u.ownAttributes(0)
+			u.attributes[i].Value = value
+			return
+		}
+	}
+	u.ownAttributes(1)
+	u.attributes = append(u.attributes, Attribute{Key: key, Value: value})
+}
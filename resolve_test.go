@@ -0,0 +1,59 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveReference(t *testing.T) {
+	const base = "urn:orders:1234:vendor:amazon:region:eu"
+	tests := []struct{ ref, want string }{
+		{":5678", "urn:orders:5678:vendor:amazon:region:eu"},
+		{"::vendor:ebay", "urn:orders:1234:vendor:ebay:region:eu"},
+		{"::carrier:ups", "urn:orders:1234:vendor:amazon:region:eu:carrier:ups"},
+		{"invoices:9", "urn:invoices:9:vendor:amazon:region:eu"},
+		{"invoices", "urn:invoices:1234:vendor:amazon:region:eu"},
+		{":a%3Ab", "urn:orders:a%3Ab:vendor:amazon:region:eu"},
+		{"", base},
+		{"urn:users:7", "urn:users:7"},
+		{"URN:users:7:k:v", "urn:users:7:k:v"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveReference(base, tt.ref)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveReference(%q) = %q, %v, want %q", tt.ref, got, err, tt.want)
+		}
+	}
+}
+
+func TestResolveReferenceErrors(t *testing.T) {
+	const base = "urn:orders:1"
+	var e *InvalidURNError
+	tests := []struct {
+		base, ref string
+		kind      ErrorKind
+	}{
+		{"orders:1", ":2", KindScheme},
+		{base, "::vendor", KindUnpairedKey},
+		{base, "::", KindUnpairedKey},
+		{base, "::vendor:", KindEmptyAttribute},
+		{base, ":%zz", KindMalformedEscape},
+		{base, "bad entity:1", KindInvalidEntity},
+		{base, "urn:users", KindMissingComponent},
+		{base, "urn:bad%20entity:1", KindInvalidEntity},
+		{base, strings.Repeat(":k:v", DefaultMaxSegments), KindTooManySegments},
+	}
+	for _, tt := range tests {
+		_, err := ResolveReference(tt.base, tt.ref)
+		if !errors.As(err, &e) || e.Kind != tt.kind {
+			t.Errorf("ResolveReference(%q, %q) = %v, want kind %v", tt.base, tt.ref, err, tt.kind)
+		}
+	}
+
+	SetReservedKeys("internal")
+	t.Cleanup(func() { SetReservedKeys() })
+	if _, err := ResolveReference(base, "::internal:x"); !errors.As(err, &e) || e.Kind != KindReservedKey {
+		t.Errorf("reserved key: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package urn
+
+import "fmt"
+
+// Router dispatches URNs to handlers registered by Pattern. Patterns are
+// stored in a trie of segments, so a lookup costs time proportional to
+// the number of segments rather than the number of patterns. When several
+// patterns match, at each segment a literal beats a wildcard ("*" or
+// "{name}"), which beats "**". A Router is not safe for concurrent
+// registration, but Lookup may be called concurrently once it is set up.
+type Router struct {
+	root routeNode
+}
+
+type routeNode struct {
+	literal map[string]*routeNode
+	wild    *routeNode
+	// end and multi hold the routes ending at this node, and those ending
+	// in "**" after it.
+	end   *route
+	multi *route
+}
+
+type route struct {
+	pattern *Pattern
+	handler any
+}
+
+// NewRouter returns an empty Router.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Handle registers h for pattern. It panics if the pattern is invalid or
+// a pattern with the same shape is already registered.
+func (r *Router) Handle(pattern string, h any) {
+	p := MustCompilePattern(pattern)
+	n := &r.root
+	for _, s := range p.segs {
+		switch s.kind {
+		case segLiteral:
+			if n.literal == nil {
+				n.literal = make(map[string]*routeNode)
+			}
+			next := n.literal[s.value]
+			if next == nil {
+				next = &routeNode{}
+				n.literal[s.value] = next
+			}
+			n = next
+		case segWild:
+			if n.wild == nil {
+				n.wild = &routeNode{}
+			}
+			n = n.wild
+		case segMulti:
+			if n.multi != nil {
+				panic(fmt.Sprintf("urn: pattern %q conflicts with %q", pattern, n.multi.pattern))
+			}
+			n.multi = &route{p, h}
+			return
+		}
+	}
+	if n.end != nil {
+		panic(fmt.Sprintf("urn: pattern %q conflicts with %q", pattern, n.end.pattern))
+	}
+	n.end = &route{p, h}
+}
+
+// Lookup returns the handler of the most specific pattern matching
+// urnStr and its captured parameters. ok is false if urnStr does not
+// parse or no pattern matches.
+func (r *Router) Lookup(urnStr string) (h any, params map[string]string, ok bool) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return nil, nil, false
+	}
+	segs := u.segments()
+	rt := r.root.lookup(segs)
+	if rt == nil {
+		return nil, nil, false
+	}
+	params, _ = rt.pattern.match(segs)
+	return rt.handler, params, true
+}
+
+func (n *routeNode) lookup(segs []string) *route {
+	if len(segs) == 0 {
+		if n.end != nil {
+			return n.end
+		}
+		return n.multi
+	}
+	if next := n.literal[segs[0]]; next != nil {
+		if rt := next.lookup(segs[1:]); rt != nil {
+			return rt
+		}
+	}
+	if n.wild != nil {
+		if rt := n.wild.lookup(segs[1:]); rt != nil {
+			return rt
+		}
+	}
+	return n.multi
+}
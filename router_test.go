@@ -0,0 +1,114 @@
+package urn
+
+import (
+	"fmt"
+	"maps"
+	"testing"
+)
+
+func newTestRouter() *Router {
+	r := NewRouter()
+	r.Handle("urn:orders:*", "order")
+	r.Handle("urn:orders:special", "special")
+	r.Handle("urn:orders:{id}:vendor:{vendor}", "vendor")
+	r.Handle("urn:orders:{id}:vendor:amazon", "amazon")
+	r.Handle("urn:orders:**", "orders-any")
+	r.Handle("urn:*:{id}:status:shipped", "shipped")
+	r.Handle("urn:**", "fallback")
+	return r
+}
+
+func TestRouterLookup(t *testing.T) {
+	r := newTestRouter()
+	tests := []struct {
+		in     string
+		want   any
+		params map[string]string
+	}{
+		{"urn:orders:1", "order", nil},
+		{"urn:ORDERS:special", "special", nil},
+		{"urn:orders:1:vendor:ebay", "vendor", map[string]string{"id": "1", "vendor": "ebay"}},
+		{"urn:orders:1:vendor:amazon", "amazon", map[string]string{"id": "1"}},
+		{"urn:orders:1:region:eu", "orders-any", nil},
+		// Precedence is decided segment by segment: the literal entity
+		// beats the wildcard one even though "**" then matches the rest.
+		{"urn:orders:7:status:shipped", "orders-any", nil},
+		{"urn:users:7:status:shipped", "shipped", map[string]string{"id": "7"}},
+		{"urn:users:7", "fallback", nil},
+	}
+	for _, tt := range tests {
+		h, params, ok := r.Lookup(tt.in)
+		if !ok || h != tt.want || !maps.Equal(params, tt.params) {
+			t.Errorf("Lookup(%s) = %v, %v, %v, want %v, %v", tt.in, h, params, ok, tt.want, tt.params)
+		}
+	}
+
+	// A literal branch that fails deeper falls back to the wildcard.
+	fb := NewRouter()
+	fb.Handle("urn:orders:1", "literal")
+	fb.Handle("urn:*:1:k:v", "wild")
+	if h, _, _ := fb.Lookup("urn:orders:1:k:v"); h != "wild" {
+		t.Errorf("backtracking: got %v", h)
+	}
+
+	if _, _, ok := r.Lookup("not a urn"); ok {
+		t.Error("invalid URN matched")
+	}
+	if _, _, ok := NewRouter().Lookup("urn:a:1"); ok {
+		t.Error("empty router matched")
+	}
+}
+
+func TestRouterConflicts(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"urn:orders:{id}", "urn:orders:*"},
+		{"urn:orders:**", "urn:orders:**"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s after %s did not panic", pair[1], pair[0])
+				}
+			}()
+			r := NewRouter()
+			r.Handle(pair[0], 1)
+			r.Handle(pair[1], 2)
+		}()
+	}
+}
+
+const benchRules = 500
+
+func benchPatterns() []string {
+	patterns := make([]string, benchRules)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf("urn:entity%d:{id}:vendor:{vendor}", i)
+	}
+	return patterns
+}
+
+func BenchmarkRouterLookup(b *testing.B) {
+	r := NewRouter()
+	for i, p := range benchPatterns() {
+		r.Handle(p, i)
+	}
+	in := fmt.Sprintf("urn:entity%d:1:vendor:amazon", benchRules-1)
+	for b.Loop() {
+		r.Lookup(in)
+	}
+}
+
+func BenchmarkPatternScan(b *testing.B) {
+	var patterns []*Pattern
+	for _, p := range benchPatterns() {
+		patterns = append(patterns, MustCompilePattern(p))
+	}
+	in := fmt.Sprintf("urn:entity%d:1:vendor:amazon", benchRules-1)
+	for b.Loop() {
+		for _, p := range patterns {
+			if _, ok := p.Match(in); ok {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package urn
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ComposeRows composes one URN per row of column-oriented data: row i has
+// ID idColumn[i] and, for every key of attrColumns, the attribute
+// attrColumns[key][i]. Attributes are emitted in sorted key order, and an
+// empty cell omits that attribute from its row.
+//
+// Every column must have as many values as idColumn; otherwise ComposeRows
+// composes nothing and returns the mismatch as its only error. A row that
+// cannot be composed, such as one with an empty ID, is left as "" in the
+// result and reported as a *BatchError carrying its index, while the other
+// rows are still composed.
+func ComposeRows(entity string, idColumn []string, attrColumns map[string][]string) ([]string, []error) {
+	return defaultProcessor.ComposeRows(entity, idColumn, attrColumns)
+}
+
+// ComposeRows is like the package-level ComposeRows, under the Processor's
+// options.
+func (p *Processor) ComposeRows(entity string, idColumn []string, attrColumns map[string][]string) ([]string, []error) {
+	keys := make([]string, 0, len(attrColumns))
+	for k := range attrColumns {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if n := len(attrColumns[k]); n != len(idColumn) {
+			return nil, []error{fmt.Errorf("Cannot compose rows: column %q has %d values, want %d", k, n, len(idColumn))}
+		}
+	}
+
+	o, c := p.snapshot()
+	out := make([]string, len(idColumn))
+	var errs []error
+
+	// The scheme, entity and escaped keys are shared by every row, and the
+	// attribute slice and output buffer are reused, so each row costs only
+	// the allocation of its result string.
+	head := appendEscape([]byte(o.scheme()+":"), entity)
+	head = append(head, ':')
+	escKeys := make([]string, len(keys))
+	for i, k := range keys {
+		escKeys[i] = escape(k)
+	}
+	pairs := make([]Attribute, 0, len(keys))
+	cols := make([]int, 0, len(keys)) // cols[j] is the key index of pairs[j]
+	var buf []byte
+	for i, id := range idColumn {
+		pairs, cols = pairs[:0], cols[:0]
+		for ki, k := range keys {
+			if v := attrColumns[k][i]; v != "" {
+				pairs = append(pairs, Attribute{Key: k, Value: v})
+				cols = append(cols, ki)
+			}
+		}
+		err := validateComponentsOpts(c, o, entity, id, pairs)
+		if err == nil && o.quoting {
+			out[i], err = composeQuoted(o.scheme(), entity, id, pairs)
+		} else if err == nil {
+			buf = appendEscape(append(buf[:0], head...), id)
+			for j, p := range pairs {
+				buf = append(buf, ':')
+				buf = append(buf, escKeys[cols[j]]...)
+				buf = append(buf, ':')
+				buf = appendEscape(buf, p.Value)
+			}
+			out[i] = string(buf)
+		}
+		observeCompose(err)
+		if err != nil {
+			errs = append(errs, &BatchError{Index: i, Input: id, Err: err})
+		}
+	}
+	return out, errs
+}
@@ -0,0 +1,111 @@
+package urn
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestComposeRows(t *testing.T) {
+	ids := []string{"1", "", "3:x"}
+	cols := map[string][]string{
+		"region": {"eu", "us", ""},
+		"env":    {"prod", "dev", "test"},
+	}
+	got, errs := ComposeRows("user", ids, cols)
+	want := []string{
+		"urn:user:1:env:prod:region:eu",
+		"",
+		"urn:user:3%3Ax:env:test",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %q", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if len(errs) != 1 {
+		t.Fatalf("errs = %v", errs)
+	}
+	var be *BatchError
+	var ie *InvalidURNError
+	if !errors.As(errs[0], &be) || be.Index != 1 || !errors.As(errs[0], &ie) || ie.Kind != KindEmptyComponent {
+		t.Errorf("err = %v", errs[0])
+	}
+
+	// Each row matches ComposeAttrs with the non-empty attributes in key order.
+	s, _ := ComposeAttrs("user", "3:x", []Attribute{{Key: "env", Value: "test"}})
+	if got[2] != s {
+		t.Errorf("row 2 = %q, ComposeAttrs = %q", got[2], s)
+	}
+}
+
+func TestComposeRowsRagged(t *testing.T) {
+	got, errs := ComposeRows("user", []string{"1", "2"}, map[string][]string{
+		"a": {"x", "y"},
+		"b": {"x"},
+	})
+	if got != nil || len(errs) != 1 {
+		t.Fatalf("got %q, errs %v", got, errs)
+	}
+	if want := `Cannot compose rows: column "b" has 1 values, want 2`; errs[0].Error() != want {
+		t.Errorf("err = %q", errs[0])
+	}
+}
+
+func TestComposeRowsInvalidEntity(t *testing.T) {
+	got, errs := ComposeRows("a b", []string{"1", "2"}, nil)
+	if len(got) != 2 || got[0] != "" || len(errs) != 2 {
+		t.Errorf("got %q, errs %v", got, errs)
+	}
+}
+
+func TestComposeRowsNoRows(t *testing.T) {
+	got, errs := ComposeRows("user", nil, map[string][]string{"a": nil})
+	if len(got) != 0 || errs != nil {
+		t.Errorf("got %q, errs %v", got, errs)
+	}
+}
+
+func rowColumns(n int) ([]string, map[string][]string) {
+	ids := make([]string, n)
+	cols := map[string][]string{"env": make([]string, n), "region": make([]string, n), "owner": make([]string, n)}
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+		cols["env"][i] = "prod"
+		cols["region"][i] = "eu-west"
+		cols["owner"][i] = "team:" + ids[i]
+	}
+	return ids, cols
+}
+
+func BenchmarkComposeRows(b *testing.B) {
+	ids, cols := rowColumns(100_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, errs := ComposeRows("user", ids, cols); errs != nil {
+			b.Fatal(errs)
+		}
+	}
+}
+
+func BenchmarkComposeRowsNaive(b *testing.B) {
+	ids, cols := rowColumns(100_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		out := make([]string, len(ids))
+		for i, id := range ids {
+			row := make(map[string]string, len(cols))
+			for k, col := range cols {
+				row[k] = col[i]
+			}
+			s, err := Compose("user", id, row)
+			if err != nil {
+				b.Fatal(err)
+			}
+			out[i] = s
+		}
+	}
+}
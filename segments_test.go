@@ -0,0 +1,82 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseTooManySegments(t *testing.T) {
+	s := "urn:a:1" + strings.Repeat(":k:v", 31) // 65 segments
+	_, err := Parse(s)
+	if !errors.Is(err, ErrTooManySegments) {
+		t.Fatalf("expected ErrTooManySegments, got %v", err)
+	}
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Kind != KindTooManySegments {
+		t.Errorf("expected KindTooManySegments, got %v", err)
+	}
+
+	if _, err := Parse("urn:a:1" + strings.Repeat(":k:v", 30)); err != nil {
+		t.Errorf("64 segments should parse: %v", err)
+	}
+
+	p := NewProcessor(WithMaxSegments(5))
+	if _, err := p.Parse("urn:a:1:k:v"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := p.Parse("urn:a:1:k:v:x:y"); !errors.Is(err, ErrTooManySegments) {
+		t.Errorf("expected ErrTooManySegments, got %v", err)
+	}
+}
+
+func TestParseRejectsLongInputBeforeScanning(t *testing.T) {
+	_, err := Parse("urn:a:1" + strings.Repeat(":", 1<<20))
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Kind != KindTooLong {
+		t.Errorf("expected KindTooLong, got %v", err)
+	}
+}
+
+func TestParsePathologicalAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts differ under the race detector")
+	}
+	inputs := []string{
+		"urn:a:1" + strings.Repeat(":", 248),
+		"urn:a:1" + strings.Repeat(":k", 124),
+		"urn:a:1" + strings.Repeat(":", 1<<16),
+	}
+	for _, in := range inputs {
+		allocs := testing.AllocsPerRun(20, func() {
+			_, _ = Parse(in)
+		})
+		if allocs > 4 {
+			t.Errorf("Parse(%.20q…) made %.0f allocations", in, allocs)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"urn:orders:1:vendor:amazon",
+		"urn:a:1" + strings.Repeat(":", 200),
+		"urn:a:1" + strings.Repeat(":k:v", 40),
+		"urn:a%3A:%ZZ",
+		"urn::",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		u, err := Parse(s)
+		if err != nil {
+			return
+		}
+		if len(u.attributes)*2+3 > DefaultMaxSegments {
+			t.Errorf("parsed %d attributes, over the segment limit", len(u.attributes))
+		}
+		if len(s) > MaxURNLength {
+			t.Errorf("parsed input of %d bytes", len(s))
+		}
+	})
+}
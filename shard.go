@@ -0,0 +1,146 @@
+package urn
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultReplicas is the number of points per shard on a
+// ConsistentSharder's ring when NewConsistentSharder is given fewer than
+// one.
+const DefaultReplicas = 100
+
+// ShardOption configures ShardFor and NewConsistentSharder.
+type ShardOption func(*shardConfig)
+
+type shardConfig struct {
+	identity bool
+}
+
+// ShardByIdentity hashes only the entity and ID (see Identity), so every
+// URN of a resource lands on the same shard whatever its attributes. Like
+// Identity, it ignores IDComparators.
+func ShardByIdentity() ShardOption {
+	return func(c *shardConfig) {
+		c.identity = true
+	}
+}
+
+// shardKey returns the 64-bit hash a URN is sharded by: its canonical
+// form, or its identity under ShardByIdentity, hashed with FNV-1a and the
+// SplitMix64 finalizer. It is stable across processes and versions.
+func shardKey(urnStr string, c *shardConfig) (uint64, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return 0, err
+	}
+	if c.identity {
+		s, err := compose(asciiToLower(u.Entity), u.ID, nil)
+		if err != nil {
+			return 0, err
+		}
+		return hashID(s), nil
+	}
+	return hashID(u.Canonical()), nil
+}
+
+// ShardFor maps urnStr to one of shards buckets, numbered from 0, with
+// jump consistent hashing: equivalent URNs share a bucket, and growing
+// from n to n+1 buckets moves only about 1/(n+1) of URNs, all of them to
+// the new bucket. Buckets cannot be removed from the middle; use a
+// ConsistentSharder for named shards.
+func ShardFor(urnStr string, shards int, opts ...ShardOption) (int, error) {
+	if shards < 1 {
+		return 0, fmt.Errorf("Cannot shard URN: %d shards, want at least 1", shards)
+	}
+	var c shardConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	key, err := shardKey(urnStr, &c)
+	if err != nil {
+		return 0, err
+	}
+	return jumpHash(key, shards), nil
+}
+
+// jumpHash is the jump consistent hash of Lamping and Veach.
+func jumpHash(key uint64, buckets int) int {
+	b, j := int64(-1), int64(0)
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// ConsistentSharder assigns URNs to named shards on a hash ring. Each shard
+// owns replicas points on the ring, and a URN goes to the shard owning the
+// first point at or after its hash, so adding or removing one of n shards
+// moves only about 1/n of URNs. It is immutable and safe for concurrent
+// use.
+type ConsistentSharder struct {
+	cfg    shardConfig
+	points []uint64 // sorted ring positions
+	owners []string // owners[i] is the shard at points[i]
+}
+
+type ringPoint struct {
+	pos   uint64
+	owner string
+}
+
+// NewConsistentSharder returns a sharder over the named shards, with
+// replicas points per shard, or DefaultReplicas when replicas is below 1.
+// Duplicate names count once. Assignments depend only on the names, not
+// on their order, and are stable across processes.
+func NewConsistentSharder(shardNames []string, replicas int, opts ...ShardOption) *ConsistentSharder {
+	if replicas < 1 {
+		replicas = DefaultReplicas
+	}
+	s := &ConsistentSharder{}
+	for _, opt := range opts {
+		opt(&s.cfg)
+	}
+	names := slices.Clone(shardNames)
+	slices.Sort(names)
+	names = slices.Compact(names)
+	ring := make([]ringPoint, 0, len(names)*replicas)
+	for _, name := range names {
+		for i := range replicas {
+			ring = append(ring, ringPoint{hashID(name + "#" + strconv.Itoa(i)), name})
+		}
+	}
+	// Ties, which are vanishingly rare, go to the smaller name so the ring
+	// does not depend on input order.
+	slices.SortFunc(ring, func(a, b ringPoint) int {
+		return cmp.Or(cmp.Compare(a.pos, b.pos), strings.Compare(a.owner, b.owner))
+	})
+	s.points = make([]uint64, len(ring))
+	s.owners = make([]string, len(ring))
+	for i, p := range ring {
+		s.points[i], s.owners[i] = p.pos, p.owner
+	}
+	return s
+}
+
+// Assign returns the shard for urnStr. A sharder without shards is an
+// error.
+func (s *ConsistentSharder) Assign(urnStr string) (string, error) {
+	if len(s.points) == 0 {
+		return "", fmt.Errorf("Cannot shard URN: no shards")
+	}
+	key, err := shardKey(urnStr, &s.cfg)
+	if err != nil {
+		return "", err
+	}
+	i, _ := slices.BinarySearch(s.points, key)
+	if i == len(s.points) {
+		i = 0
+	}
+	return s.owners[i], nil
+}
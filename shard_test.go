@@ -0,0 +1,112 @@
+package urn
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestShardForStable(t *testing.T) {
+	// Fixed expectations: assignments must not change across processes or
+	// releases, or every deployment would reshuffle on upgrade.
+	tests := []struct {
+		in    string
+		shard int
+		owner string
+	}{
+		{"urn:orders:1", 13, "gamma"},
+		{"urn:orders:2:a:b", 2, "gamma"},
+		{"urn:users:ada", 7, "alpha"},
+	}
+	s := NewConsistentSharder([]string{"gamma", "alpha", "beta"}, 0)
+	for _, tt := range tests {
+		if got, err := ShardFor(tt.in, 16); err != nil || got != tt.shard {
+			t.Errorf("ShardFor(%q, 16) = %d, %v, want %d", tt.in, got, err, tt.shard)
+		}
+		if got, err := s.Assign(tt.in); err != nil || got != tt.owner {
+			t.Errorf("Assign(%q) = %q, %v, want %q", tt.in, got, err, tt.owner)
+		}
+	}
+}
+
+func TestShardForEquivalentAndIdentity(t *testing.T) {
+	a, _ := ShardFor("URN:Orders:1:b:2:a:1", 64)
+	b, _ := ShardFor("urn:orders:1:a:1:b:2", 64)
+	if a != b {
+		t.Errorf("equivalent URNs on shards %d and %d", a, b)
+	}
+
+	s := NewConsistentSharder([]string{"a", "b", "c", "d"}, 0, ShardByIdentity())
+	diff := 0
+	for i := range 200 {
+		id := fmt.Sprint(i)
+		x, _ := ShardFor("urn:orders:"+id+":status:new", 64, ShardByIdentity())
+		y, _ := ShardFor("urn:ORDERS:"+id+":status:done", 64, ShardByIdentity())
+		if x != y {
+			t.Fatalf("ShardByIdentity moved order %s from %d to %d", id, x, y)
+		}
+		p, _ := s.Assign("urn:orders:" + id + ":status:new")
+		q, _ := s.Assign("urn:orders:" + id)
+		if p != q {
+			t.Fatalf("ShardByIdentity sharder moved order %s from %s to %s", id, p, q)
+		}
+		if x2, _ := ShardFor("urn:orders:"+id+":status:done", 64); x2 != x {
+			diff++
+		}
+	}
+	if diff == 0 {
+		t.Error("attributes never changed the canonical shard")
+	}
+}
+
+// movedFraction returns the fraction of n URNs whose assignment differs
+// between before and after.
+func movedFraction[T comparable](n int, before, after func(string) T) float64 {
+	moved := 0
+	for i := range n {
+		s := fmt.Sprintf("urn:orders:%d", i)
+		if before(s) != after(s) {
+			moved++
+		}
+	}
+	return float64(moved) / float64(n)
+}
+
+func TestShardMovementOnGrowth(t *testing.T) {
+	const n = 20000
+	frac := movedFraction(n,
+		func(s string) int { i, _ := ShardFor(s, 10); return i },
+		func(s string) int { i, _ := ShardFor(s, 11); return i })
+	if frac < 0.07 || frac > 0.11 {
+		t.Errorf("ShardFor 10→11 moved %.3f of URNs, want about 1/11", frac)
+	}
+
+	names := []string{"s0", "s1", "s2", "s3", "s4", "s5", "s6", "s7", "s8", "s9"}
+	before := NewConsistentSharder(names, 0)
+	after := NewConsistentSharder(append(names, "s10"), 0)
+	frac = movedFraction(n,
+		func(s string) string { o, _ := before.Assign(s); return o },
+		func(s string) string { o, _ := after.Assign(s); return o })
+	if frac < 0.05 || frac > 0.14 {
+		t.Errorf("ConsistentSharder 10→11 moved %.3f of URNs, want about 1/11", frac)
+	}
+	for i := range 1000 {
+		s := fmt.Sprintf("urn:orders:%d", i)
+		o1, _ := before.Assign(s)
+		o2, _ := after.Assign(s)
+		if o1 != o2 && o2 != "s10" {
+			t.Fatalf("%s moved from %s to %s, want moves only to the new shard", s, o1, o2)
+		}
+	}
+}
+
+func TestShardErrors(t *testing.T) {
+	if _, err := ShardFor("urn:orders:1", 0); err == nil {
+		t.Error("ShardFor accepted 0 shards")
+	}
+	if _, err := ShardFor("orders:1", 4); err == nil {
+		t.Error("ShardFor accepted an invalid URN")
+	}
+	if _, err := NewConsistentSharder(nil, 10).Assign("urn:orders:1"); err == nil {
+		t.Error("Assign succeeded without shards")
+	}
+}
@@ -0,0 +1,73 @@
+package urn
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ShortID returns the last n runes of the decoded ID of urnStr, or the
+// whole ID when it is shorter. It is meant for display handles; use
+// ShortIDs to keep handles unique across a set of URNs.
+func ShortID(urnStr string, n int) (string, error) {
+	if n <= 0 {
+		return "", fmt.Errorf("Invalid short ID length %d: must be positive", n)
+	}
+	u, err := Parse(urnStr)
+	if err != nil {
+		return "", err
+	}
+	return lastRunes(u.ID, n), nil
+}
+
+// ShortIDs returns a short handle for each URN, keyed by the input string.
+// Handles are the last m runes of each decoded ID, where m is the smallest
+// length of at least n for which URNs with different IDs get different
+// handles; when the last n runes collide, every handle is extended. URNs
+// whose IDs are identical share a handle. Inputs that fail to parse are
+// reported as a *BatchError carrying their index.
+func ShortIDs(urns []string, n int) (map[string]string, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("Invalid short ID length %d: must be positive", n)
+	}
+	ids := make(map[string]string, len(urns))
+	for i, s := range urns {
+		u, err := Parse(s)
+		if err != nil {
+			return nil, &BatchError{Index: i, Input: s, Err: err}
+		}
+		ids[s] = u.ID
+	}
+	// Once m reaches the longest ID every handle is a full ID, so distinct
+	// IDs can no longer collide and the loop ends.
+	for m := n; ; m++ {
+		if out, ok := shortHandles(ids, m); ok {
+			return out, nil
+		}
+	}
+}
+
+// shortHandles maps each key of ids to the last m runes of its ID and
+// reports whether different IDs received different handles.
+func shortHandles(ids map[string]string, m int) (map[string]string, bool) {
+	out := make(map[string]string, len(ids))
+	owner := make(map[string]string, len(ids))
+	for s, id := range ids {
+		h := lastRunes(id, m)
+		if prev, ok := owner[h]; ok && prev != id {
+			return nil, false
+		}
+		owner[h] = id
+		out[s] = h
+	}
+	return out, true
+}
+
+// lastRunes returns the last n runes of s.
+func lastRunes(s string, n int) string {
+	i := len(s)
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+	}
+	return s[i:]
+}
@@ -0,0 +1,86 @@
+package urn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestShortID(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"urn:user:0123456789abcdef", 8, "89abcdef"},
+		{"urn:user:abc", 8, "abc"},
+		{"urn:user:abc", 3, "abc"},
+		{"urn:user:prüfung-日本語", 4, "-日本語"},
+		{"urn:user:a%3Ab%3Ac:k:v", 3, "b:c"},
+		{"urn:user:%F0%9F%98%80xyz", 4, "😀xyz"},
+	}
+	for _, tt := range tests {
+		got, err := ShortID(tt.in, tt.n)
+		if err != nil || got != tt.want {
+			t.Errorf("ShortID(%q, %d) = %q, %v; want %q", tt.in, tt.n, got, err, tt.want)
+		}
+	}
+	if _, err := ShortID("urn:user:abc", 0); err == nil {
+		t.Error("ShortID accepted n = 0")
+	}
+	if _, err := ShortID("nope", 8); err == nil {
+		t.Error("ShortID accepted an invalid URN")
+	}
+}
+
+func TestShortIDs(t *testing.T) {
+	urns := []string{
+		"urn:user:aaaa-1234",
+		"urn:user:bbbb-1234",
+		"urn:user:cccc-5678",
+		"urn:order:cccc-5678:k:v",
+		"urn:user:xy",
+	}
+	got, err := ShortIDs(urns, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "1234" collides, so every handle grows until "a-1234" and "b-1234"
+	// differ; identical IDs share a handle.
+	want := map[string]string{
+		urns[0]: "a-1234",
+		urns[1]: "b-1234",
+		urns[2]: "c-5678",
+		urns[3]: "c-5678",
+		urns[4]: "xy",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v", got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s → %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestShortIDsSuffixOfAnother(t *testing.T) {
+	// "234" is a whole ID and a suffix of "1234"; handles grow to full IDs.
+	got, err := ShortIDs([]string{"urn:user:234", "urn:user:1234"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["urn:user:234"] != "234" || got["urn:user:1234"] != "1234" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestShortIDsErrors(t *testing.T) {
+	_, err := ShortIDs([]string{"urn:user:1", "bad"}, 4)
+	var be *BatchError
+	if !errors.As(err, &be) || be.Index != 1 {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := ShortIDs(nil, -1); err == nil {
+		t.Error("ShortIDs accepted n = -1")
+	}
+}
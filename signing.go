@@ -0,0 +1,64 @@
+package urn
+
+import (
+	"encoding/binary"
+	"slices"
+	"strings"
+)
+
+// SigningBytes returns a byte form of the URN for signatures, stable
+// across library versions and implementations. Every string below is
+// written as its length in bytes as an unsigned LEB128 varint, followed by
+// the bytes themselves, without percent-encoding:
+//
+//	"urn"
+//	entity, ASCII letters lowercased
+//	id
+//	varint number of attributes, then for each attribute: key, value
+//
+// Attributes are sorted by decoded key, then decoded value, comparing
+// bytes; duplicates are kept. Attributes left unparsed under
+// MaxAttributesParsed are decoded and signed with the rest, so the tail
+// cannot change without changing the bytes. An attribute under
+// ChecksumKey is left out only when its value is the checksum of the rest
+// of the URN; any other value is signed like other data. URNs that are not Valid, or whose tail does not decode, are an
+// error.
+//
+// For example "urn:Orders:1:b:2:a:x" encodes as
+//
+//	03 75 72 6e  06 6f 72 64 65 72 73  01 31  02  01 61 01 78  01 62 01 32
+func (u *URN) SigningBytes() ([]byte, error) {
+	if err := u.Valid(); err != nil {
+		return nil, err
+	}
+	pairs, err := u.allUserAttributes()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(pairs, func(a, b Attribute) int {
+		if c := strings.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	entity := asciiToLower(u.Entity)
+	n := 3*binary.MaxVarintLen64 + len("urn") + len(entity) + len(u.ID)
+	for _, p := range pairs {
+		n += 2*binary.MaxVarintLen64 + len(p.Key) + len(p.Value)
+	}
+	b := make([]byte, 0, n)
+	b = appendSigningString(b, "urn")
+	b = appendSigningString(b, entity)
+	b = appendSigningString(b, u.ID)
+	b = binary.AppendUvarint(b, uint64(len(pairs)))
+	for _, p := range pairs {
+		b = appendSigningString(b, p.Key)
+		b = appendSigningString(b, p.Value)
+	}
+	return b, nil
+}
+
+func appendSigningString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
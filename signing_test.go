@@ -0,0 +1,105 @@
+package urn
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSigningBytesGolden(t *testing.T) {
+	inputs := []string{
+		"urn:Orders:1:b:2:a:x",
+		"urn:orders:1",
+		// Components are length-prefixed, so "ab"+"c" and "a"+"bc" differ.
+		"urn:orders:1:ab:c",
+		"urn:orders:1:a:bc",
+		// Decoded bytes are signed, sorted by decoded key then value.
+		"urn:orders:a%3Ab:k:%C3%A9:k:e:K:z",
+		// A value longer than 127 bytes takes a two-byte length.
+		"urn:blobs:1:data:" + strings.Repeat("x", 130),
+	}
+	var b strings.Builder
+	for _, in := range inputs {
+		u, err := Parse(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb, err := u.SigningBytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.WriteString(in + "\n\t" + hex.EncodeToString(sb) + "\n")
+	}
+	got := b.String()
+
+	golden := filepath.Join("testdata", "signing.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("signing bytes differ from %s:\n%s", golden, got)
+	}
+}
+
+func TestSigningBytesEquivalentEncodings(t *testing.T) {
+	a, _ := Parse("urn:Orders:a%2fb:vendor:amazon:region:eu")
+	b, _ := Parse("URN:orders:a%2Fb:region:%65u:vendor:amazon")
+	sa, err := a.SigningBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, _ := b.SigningBytes()
+	if !bytes.Equal(sa, sb) {
+		t.Errorf("%x != %x", sa, sb)
+	}
+
+	withSum, _ := WithChecksum(a.String())
+	c, _ := Parse(withSum)
+	if sc, _ := c.SigningBytes(); !bytes.Equal(sa, sc) {
+		t.Error("checksum attribute changed the signing bytes")
+	}
+
+	ab, _ := Parse("urn:orders:1:ab:c")
+	bc, _ := Parse("urn:orders:1:a:bc")
+	x, _ := ab.SigningBytes()
+	y, _ := bc.SigningBytes()
+	if bytes.Equal(x, y) {
+		t.Error("ambiguous encoding")
+	}
+
+	if _, err := (&URN{Entity: "orders"}).SigningBytes(); err == nil {
+		t.Error("invalid URN accepted")
+	}
+}
+
+func TestSigningBytesCoverChecksumKey(t *testing.T) {
+	red, _ := Parse("urn:orders:1:c:red")
+	blue, _ := Parse("urn:orders:1:c:blue")
+	sr, err := red.SigningBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, _ := blue.SigningBytes()
+	if bytes.Equal(sr, sb) {
+		t.Error("changing a c attribute did not change the signing bytes")
+	}
+
+	// A checksum that no longer verifies is signed as data.
+	s, _ := WithChecksum("urn:orders:1:a:1")
+	stale, _ := Parse(strings.Replace(s, ":a:1", ":a:2", 1))
+	plain, _ := Parse("urn:orders:1:a:2")
+	ss, _ := stale.SigningBytes()
+	sp, _ := plain.SigningBytes()
+	if bytes.Equal(ss, sp) {
+		t.Error("a stale checksum was left out of the signing bytes")
+	}
+}
@@ -0,0 +1,88 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+)
+
+// SpanKind identifies the part of a URN a Span covers.
+type SpanKind int
+
+const (
+	// SpanScheme covers the scheme, e.g. "urn".
+	SpanScheme SpanKind = iota
+	// SpanEntity covers the entity.
+	SpanEntity
+	// SpanID covers the ID.
+	SpanID
+	// SpanKey covers an attribute key.
+	SpanKey
+	// SpanValue covers an attribute value.
+	SpanValue
+)
+
+// String returns the span kind name.
+func (k SpanKind) String() string {
+	switch k {
+	case SpanScheme:
+		return "scheme"
+	case SpanEntity:
+		return "entity"
+	case SpanID:
+		return "id"
+	case SpanKey:
+		return "key"
+	default:
+		return "value"
+	}
+}
+
+// Span locates a part of a URN as the byte range [Start, End) of the
+// input, escapes included and separators excluded.
+type Span struct {
+	Kind       SpanKind
+	Start, End int
+}
+
+// ParseWithSpans parses urnStr like Parse and also returns the span of the
+// scheme, entity, ID and every key and value. When parsing fails, the
+// spans up to and including the one containing the error offset are still
+// returned, so tools can underline the offending bytes.
+func ParseWithSpans(urnStr string) (*URN, []Span, error) {
+	o := &loadConfig().opts
+	u, err := parse(urnStr, o)
+	spans := segmentSpans(urnStr, o)
+	if err != nil {
+		var e *InvalidURNError
+		if !errors.As(err, &e) {
+			return nil, nil, err
+		}
+		n := 0
+		for n < len(spans) && spans[n].Start <= e.Offset {
+			n++
+		}
+		return nil, spans[:n], err
+	}
+	return u, spans, nil
+}
+
+// segmentSpans splits urnStr into spans without validating it.
+func segmentSpans(urnStr string, o *options) []Span {
+	start := o.schemeEnd(urnStr)
+	if start < 0 {
+		return nil
+	}
+	spans := []Span{{SpanScheme, 0, start - 1}}
+	for i, n := start, 0; ; n++ {
+		kind := SpanKey + SpanKind(n%2)
+		if n < 2 {
+			kind = SpanEntity + SpanKind(n)
+		}
+		j := strings.IndexByte(urnStr[i:], ':')
+		if j < 0 {
+			return append(spans, Span{kind, i, len(urnStr)})
+		}
+		spans = append(spans, Span{kind, i, i + j})
+		i += j + 1
+	}
+}
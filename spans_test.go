@@ -0,0 +1,50 @@
+package urn
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseWithSpans(t *testing.T) {
+	const in = "urn:ord%65rs:a%2Fb:k%C3%A9y:v"
+	u, spans, err := ParseWithSpans(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Span{
+		{SpanScheme, 0, 3},
+		{SpanEntity, 4, 12},
+		{SpanID, 13, 18},
+		{SpanKey, 19, 27},
+		{SpanValue, 28, 29},
+	}
+	if !slices.Equal(spans, want) {
+		t.Fatalf("spans = %v, want %v", spans, want)
+	}
+	got := []string{u.Entity, u.ID, "kéy", "v"}
+	for i, sp := range spans[1:] {
+		raw, _ := unescape(in[sp.Start:sp.End])
+		if raw != got[i] {
+			t.Errorf("span %v covers %q, decoding to %q", sp, in[sp.Start:sp.End], raw)
+		}
+	}
+}
+
+func TestParseWithSpansError(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Span
+	}{
+		// The malformed escape is in the value; spans stop there.
+		{"urn:a%41:1:k:%zz:j:v", []Span{{SpanScheme, 0, 3}, {SpanEntity, 4, 8}, {SpanID, 9, 10}, {SpanKey, 11, 12}, {SpanValue, 13, 16}}},
+		{"urn:a:1:k", []Span{{SpanScheme, 0, 3}, {SpanEntity, 4, 5}, {SpanID, 6, 7}, {SpanKey, 8, 9}}},
+		{"urn::1", []Span{{SpanScheme, 0, 3}, {SpanEntity, 4, 4}}},
+		{"nope:a:1", nil},
+	}
+	for _, tt := range tests {
+		u, spans, err := ParseWithSpans(tt.in)
+		if err == nil || u != nil || !slices.Equal(spans, tt.want) {
+			t.Errorf("ParseWithSpans(%q) = %v, %v, %v, want spans %v", tt.in, u, spans, err, tt.want)
+		}
+	}
+}
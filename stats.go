@@ -0,0 +1,114 @@
+package urn
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// StatsCollector receives counters for parse and compose activity. Methods
+// may be called concurrently.
+type StatsCollector interface {
+	IncParse(ok bool)
+	IncCompose(ok bool)
+	ObserveEntity(entity string)
+}
+
+type collectorHolder struct {
+	c StatsCollector
+}
+
+var statsCollector atomic.Pointer[collectorHolder]
+
+// SetStatsCollector installs c as the package-wide stats collector. Passing
+// nil disables collection; when unset, the only overhead is a nil check.
+func SetStatsCollector(c StatsCollector) {
+	if c == nil {
+		statsCollector.Store(nil)
+		return
+	}
+	statsCollector.Store(&collectorHolder{c: c})
+}
+
+func observeParse(u *URN, err error) {
+	h := statsCollector.Load()
+	if h == nil {
+		return
+	}
+	h.c.IncParse(err == nil)
+	if err == nil {
+		h.c.ObserveEntity(u.Entity)
+	}
+}
+
+func observeCompose(err error) {
+	h := statsCollector.Load()
+	if h == nil {
+		return
+	}
+	h.c.IncCompose(err == nil)
+}
+
+// MemoryStats is an in-memory StatsCollector.
+type MemoryStats struct {
+	parses        atomic.Uint64
+	parseErrors   atomic.Uint64
+	composes      atomic.Uint64
+	composeErrors atomic.Uint64
+
+	mu       sync.Mutex
+	entities map[string]uint64
+}
+
+// StatsSnapshot is a point-in-time copy of MemoryStats counters.
+type StatsSnapshot struct {
+	Parses        uint64
+	ParseErrors   uint64
+	Composes      uint64
+	ComposeErrors uint64
+	Entities      map[string]uint64
+}
+
+// NewMemoryStats creates an empty MemoryStats.
+func NewMemoryStats() *MemoryStats {
+	return &MemoryStats{entities: make(map[string]uint64)}
+}
+
+// IncParse counts a parse attempt.
+func (s *MemoryStats) IncParse(ok bool) {
+	s.parses.Add(1)
+	if !ok {
+		s.parseErrors.Add(1)
+	}
+}
+
+// IncCompose counts a compose attempt.
+func (s *MemoryStats) IncCompose(ok bool) {
+	s.composes.Add(1)
+	if !ok {
+		s.composeErrors.Add(1)
+	}
+}
+
+// ObserveEntity counts an occurrence of entity.
+func (s *MemoryStats) ObserveEntity(entity string) {
+	s.mu.Lock()
+	s.entities[entity]++
+	s.mu.Unlock()
+}
+
+// Snapshot returns a copy of the current counters.
+func (s *MemoryStats) Snapshot() StatsSnapshot {
+	s.mu.Lock()
+	entities := make(map[string]uint64, len(s.entities))
+	for k, v := range s.entities {
+		entities[k] = v
+	}
+	s.mu.Unlock()
+	return StatsSnapshot{
+		Parses:        s.parses.Load(),
+		ParseErrors:   s.parseErrors.Load(),
+		Composes:      s.composes.Load(),
+		ComposeErrors: s.composeErrors.Load(),
+		Entities:      entities,
+	}
+}
@@ -0,0 +1,51 @@
+package urn
+
+import "testing"
+
+func TestMemoryStats(t *testing.T) {
+	stats := NewMemoryStats()
+	SetStatsCollector(stats)
+	t.Cleanup(func() { SetStatsCollector(nil) })
+
+	Parse("urn:orders:1")
+	Parse("urn:orders:2")
+	Parse("urn:customers:7")
+	Parse("bogus")
+	Compose("orders", "3")
+	Compose("", "3")
+	IsValid("urn:orders:4")
+
+	snap := stats.Snapshot()
+	if snap.Parses != 5 || snap.ParseErrors != 1 {
+		t.Errorf("unexpected parse counters: %+v", snap)
+	}
+	if snap.Composes != 2 || snap.ComposeErrors != 1 {
+		t.Errorf("unexpected compose counters: %+v", snap)
+	}
+	if snap.Entities["orders"] != 3 || snap.Entities["customers"] != 1 {
+		t.Errorf("unexpected entity counts: %v", snap.Entities)
+	}
+
+	SetStatsCollector(nil)
+	Parse("urn:orders:5")
+	if stats.Snapshot().Parses != 5 {
+		t.Error("expected no counting after unsetting the collector")
+	}
+}
+
+func BenchmarkParseNoStats(b *testing.B) {
+	SetStatsCollector(nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Parse("urn:orders:1234:vendor:amazon")
+	}
+}
+
+func BenchmarkParseWithStats(b *testing.B) {
+	SetStatsCollector(NewMemoryStats())
+	defer SetStatsCollector(nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Parse("urn:orders:1234:vendor:amazon")
+	}
+}
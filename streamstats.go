@@ -0,0 +1,123 @@
+package urn
+
+import (
+	"cmp"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"slices"
+	"sync"
+)
+
+// hllPrecision is the number of hash bits selecting a HyperLogLog
+// register: 2¹² one-byte registers per entity give a standard error of
+// about 1.6%.
+const hllPrecision = 12
+
+// hll is a HyperLogLog distinct-count sketch.
+type hll [1 << hllPrecision]uint8
+
+func (h *hll) add(x uint64) {
+	i := x >> (64 - hllPrecision)
+	// The remaining bits, with a sentinel so the rank stays in range.
+	w := x<<hllPrecision | 1<<(hllPrecision-1)
+	if r := uint8(bits.LeadingZeros64(w) + 1); r > h[i] {
+		h[i] = r
+	}
+}
+
+func (h *hll) estimate() uint64 {
+	const m = float64(len(h))
+	sum, zeros := 0.0, 0
+	for _, r := range h {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	e := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if e <= 2.5*m && zeros > 0 {
+		// Linear counting is more accurate for small cardinalities.
+		e = m * math.Log(m/float64(zeros))
+	}
+	return uint64(e + 0.5)
+}
+
+// hashID hashes an ID for the sketch. FNV-1a is finalized with the
+// SplitMix64 mixer, since HyperLogLog needs well-spread high bits.
+func hashID(id string) uint64 {
+	f := fnv.New64a()
+	f.Write([]byte(id))
+	x := f.Sum64()
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// StreamStats counts URNs per entity and estimates the number of distinct
+// IDs per entity without storing them, using a HyperLogLog sketch of
+// about 4 KiB per entity. Entities are compared case-insensitively. The
+// zero value is ready to use, and a StreamStats is safe for concurrent
+// use.
+type StreamStats struct {
+	mu       sync.Mutex
+	entities map[string]*entityStats
+}
+
+type entityStats struct {
+	count uint64
+	ids   hll
+}
+
+// EntityStats is a row of a StreamStats report.
+type EntityStats struct {
+	// Entity is the lowercased entity.
+	Entity string
+	// Count is the number of URNs observed.
+	Count uint64
+	// DistinctIDs estimates the number of distinct IDs observed.
+	DistinctIDs uint64
+}
+
+// Observe records urnStr. Unparsable input is an error and is not
+// recorded.
+func (s *StreamStats) Observe(urnStr string) error {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return err
+	}
+	entity := asciiToLower(u.Entity)
+	h := hashID(u.ID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.entities[entity]
+	if e == nil {
+		if s.entities == nil {
+			s.entities = make(map[string]*entityStats)
+		}
+		e = &entityStats{}
+		s.entities[entity] = e
+	}
+	e.count++
+	e.ids.add(h)
+	return nil
+}
+
+// Report returns a row per observed entity, the most frequent first and
+// ties in entity order.
+func (s *StreamStats) Report() []EntityStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows := make([]EntityStats, 0, len(s.entities))
+	for name, e := range s.entities {
+		rows = append(rows, EntityStats{Entity: name, Count: e.count, DistinctIDs: e.ids.estimate()})
+	}
+	slices.SortFunc(rows, func(a, b EntityStats) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Entity, b.Entity)
+	})
+	return rows
+}
@@ -0,0 +1,79 @@
+package urn
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStreamStatsEstimates(t *testing.T) {
+	// distinct IDs per entity; each ID is observed three times.
+	corpus := map[string]int{"user": 20_000, "order": 1_500, "invoice": 40, "tiny": 1}
+	var s StreamStats
+	var wg sync.WaitGroup
+	for entity, n := range corpus {
+		for rep := range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range n {
+					// Entity case and attributes vary between repetitions.
+					in := fmt.Sprintf("urn:%s:id-%d:k:v", entity, i)
+					if rep == 1 {
+						in = fmt.Sprintf("urn:%s:id-%d", strings.ToUpper(entity), i)
+					}
+					if err := s.Observe(in); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	rows := s.Report()
+	if len(rows) != len(corpus) {
+		t.Fatalf("rows = %+v", rows)
+	}
+	order := []string{"user", "order", "invoice", "tiny"}
+	for i, r := range rows {
+		n := corpus[r.Entity]
+		if r.Entity != order[i] {
+			t.Errorf("row %d is %q, want %q", i, r.Entity, order[i])
+		}
+		if r.Count != uint64(3*n) {
+			t.Errorf("%s: Count = %d, want %d", r.Entity, r.Count, 3*n)
+		}
+		if err := math.Abs(float64(r.DistinctIDs)-float64(n)) / float64(n); err > 0.05 {
+			t.Errorf("%s: DistinctIDs = %d, want %d ± 5%%", r.Entity, r.DistinctIDs, n)
+		}
+	}
+}
+
+func TestStreamStatsCaseAndErrors(t *testing.T) {
+	var s StreamStats
+	s.Observe("urn:User:1")
+	s.Observe("urn:USER:1")
+	s.Observe("urn:a1:1")
+	s.Observe("urn:b1:1")
+	if err := s.Observe("bad"); err == nil {
+		t.Error("Observe accepted an invalid URN")
+	}
+	rows := s.Report()
+	want := []EntityStats{{"user", 2, 1}, {"a1", 1, 1}, {"b1", 1, 1}}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v", rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+	var empty StreamStats
+	if rows := empty.Report(); len(rows) != 0 {
+		t.Errorf("empty report = %+v", rows)
+	}
+}
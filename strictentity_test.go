@@ -0,0 +1,60 @@
+package urn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrictRejectsEncodedEntity(t *testing.T) {
+	tests := []struct {
+		in     string
+		offset int
+		seg    string
+	}{
+		{"urn:%6Frders:123", 4, "%6Frders"},
+		{"urn:orders%2D1:123", 10, "orders%2D1"},
+		{"URN:%6f%72ders:123:k:v", 4, "%6f%72ders"},
+	}
+	for _, tt := range tests {
+		_, err := ParseStrict(tt.in)
+		var ue *InvalidURNError
+		if !errors.As(err, &ue) || ue.Kind != KindEncodedEntity || ue.Offset != tt.offset || ue.Segment != tt.seg {
+			t.Errorf("ParseStrict(%q) = %#v, want KindEncodedEntity at %d", tt.in, err, tt.offset)
+		}
+		if IsValid(tt.in) {
+			t.Errorf("IsValid(%q) = true", tt.in)
+		}
+	}
+	// Escapes in the ID and attributes are still fine.
+	if _, err := ParseStrict("urn:orders:a%3Ab:k:%2F"); err != nil {
+		t.Errorf("ParseStrict rejected escapes outside the entity: %v", err)
+	}
+}
+
+func TestStrictRejectsEncodedScheme(t *testing.T) {
+	p := NewProcessor(WithSchemeAliases(map[string]string{"u%72n": "urn"}))
+	if _, err := p.Parse("u%72n:orders:1"); err != nil {
+		t.Fatalf("lenient Parse: %v", err)
+	}
+	_, err := p.ParseStrict("u%72n:orders:1")
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Kind != KindEncodedEntity || ue.Offset != 1 {
+		t.Errorf("ParseStrict = %v, want KindEncodedEntity", err)
+	}
+}
+
+// Parse decodes the entity on purpose: it is lenient, and callers that
+// check entities against an allowlist must use ParseStrict.
+func TestLenientParseDecodesEntity(t *testing.T) {
+	u, err := Parse("urn:%6Frders:123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Entity != "orders" {
+		t.Errorf("Entity = %q, want the decoded %q", u.Entity, "orders")
+	}
+	_, vs, err := ParseAudit("urn:%6Frders:123")
+	if err != nil || len(vs) != 1 || vs[0].Kind != KindEncodedEntity {
+		t.Errorf("ParseAudit = %+v, %v", vs, err)
+	}
+}
@@ -0,0 +1,100 @@
+package urn
+
+// SubResource is a child resource addressed by a leading attribute pair,
+// such as "lineitem:7" in "urn:order:123:lineitem:7".
+type SubResource struct {
+	Type string
+	ID   string
+}
+
+// RegisterSubResources declares the attribute keys that name sub-resources
+// of entity (matched ASCII case-insensitively). Types match exactly.
+// Calling it with no types removes the registration.
+func RegisterSubResources(entity string, types ...string) {
+	key := asciiToLower(entity)
+	var set map[string]bool
+	if len(types) > 0 {
+		set = make(map[string]bool, len(types))
+		for _, t := range types {
+			set[t] = true
+		}
+	}
+	updateConfig(func(c *config) {
+		m := make(map[string]map[string]bool, len(c.subResources)+1)
+		for k, v := range c.subResources {
+			m[k] = v
+		}
+		if set == nil {
+			delete(m, key)
+		} else {
+			m[key] = set
+		}
+		c.subResources = m
+	})
+}
+
+func (c *config) subResourceTypes(entity string) map[string]bool {
+	return c.subResources[asciiToLower(entity)]
+}
+
+// splitSubResources returns how many leading attribute pairs name
+// registered sub-resources of the entity.
+func splitSubResources(c *config, u *URN) int {
+	types := c.subResourceTypes(u.Entity)
+	n := 0
+	for n < len(u.attributes) && types[u.attributes[n].Key] {
+		n++
+	}
+	return n
+}
+
+// ParseWithSubResources parses urnStr and consumes leading attribute pairs
+// whose keys are sub-resource types registered for the entity with
+// RegisterSubResources. Consumption stops at the first other key; the
+// returned URN holds the remaining pairs as attributes.
+func ParseWithSubResources(urnStr string) (*URN, []SubResource, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := splitSubResources(loadConfig(), u)
+	if n == 0 {
+		return u, nil, nil
+	}
+	subs := make([]SubResource, n)
+	for i, p := range u.attributes[:n] {
+		subs[i] = SubResource{Type: p.Key, ID: p.Value}
+	}
+	u.attributes = u.attributes[n:]
+	if len(u.attributes) == 0 {
+		u.attributes = nil
+	}
+	return u, subs, nil
+}
+
+// AppendSubResource adds a typ:id pair after the URN's existing
+// sub-resources and before its attributes. typ must be registered for the
+// entity.
+func AppendSubResource(urnStr, typ, id string) (string, error) {
+	u, err := Parse(urnStr)
+	if err != nil {
+		return "", err
+	}
+	cfg := loadConfig()
+	if !cfg.subResourceTypes(u.Entity)[typ] {
+		return "", &InvalidURNError{
+			Kind:    KindInvalidKey,
+			Reason:  ReasonNotSubResource,
+			Op:      OpCompose,
+			Segment: typ,
+			Entity:  u.Entity,
+			Key:     typ,
+		}
+	}
+	n := splitSubResources(cfg, u)
+	pairs := make([]Attribute, 0, len(u.attributes)+1)
+	pairs = append(pairs, u.attributes[:n]...)
+	pairs = append(pairs, Attribute{Key: typ, Value: id})
+	pairs = append(pairs, u.attributes[n:]...)
+	return ComposeAttrs(u.Entity, u.ID, pairs)
+}
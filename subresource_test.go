@@ -0,0 +1,54 @@
+package urn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWithSubResources(t *testing.T) {
+	RegisterSubResources("order", "lineitem", "shipment")
+	t.Cleanup(func() { RegisterSubResources("order") })
+
+	u, subs, err := ParseWithSubResources("urn:order:123:lineitem:7:shipment:2:vendor:amazon:lineitem:9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SubResource{{Type: "lineitem", ID: "7"}, {Type: "shipment", ID: "2"}}
+	if !reflect.DeepEqual(subs, want) {
+		t.Errorf("got %+v, want %+v", subs, want)
+	}
+	// Consumption stops at the first non-sub-resource key.
+	if got := u.AttributePairs(); !reflect.DeepEqual(got, []Attribute{{"vendor", "amazon"}, {"lineitem", "9"}}) {
+		t.Errorf("unexpected attributes %v", got)
+	}
+
+	u, subs, _ = ParseWithSubResources("urn:order:123:vendor:amazon:lineitem:7")
+	if subs != nil || len(u.AttributePairs()) != 2 {
+		t.Errorf("sub-resources must lead: %v", subs)
+	}
+
+	// Without a registration nothing is consumed.
+	_, subs, _ = ParseWithSubResources("urn:invoice:1:lineitem:7")
+	if subs != nil {
+		t.Errorf("unregistered entity: got %v", subs)
+	}
+}
+
+func TestAppendSubResource(t *testing.T) {
+	RegisterSubResources("order", "lineitem")
+	t.Cleanup(func() { RegisterSubResources("order") })
+
+	s, err := AppendSubResource("urn:order:123:lineitem:7:vendor:amazon", "lineitem", "8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "urn:order:123:lineitem:7:lineitem:8:vendor:amazon" {
+		t.Errorf("got %s", s)
+	}
+	if _, err := AppendSubResource("urn:order:123", "vendor", "x"); err == nil {
+		t.Error("expected error for unregistered type")
+	}
+	if _, err := AppendSubResource("urn:order:123", "lineitem", ""); err == nil {
+		t.Error("expected error for empty ID")
+	}
+}
@@ -0,0 +1,25 @@
+package urn
+
+// MarshalText implements encoding.TextMarshaler, so URNs encode as plain
+// strings in JSON, YAML and similar formats. URNs that cannot be composed
+// or are not Valid are an error.
+func (u *URN) MarshalText() ([]byte, error) {
+	if err := u.Valid(); err != nil {
+		return nil, err
+	}
+	s, err := composeURN("urn", u)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using Parse.
+func (u *URN) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*u = *parsed
+	return nil
+}
@@ -0,0 +1,107 @@
+package urn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompositionStage identifies the component that pushed a composed URN
+// over the length limit.
+type CompositionStage int
+
+const (
+	// StageEntity means "urn:" plus the entity alone exceed the limit.
+	StageEntity CompositionStage = iota
+	// StageID means the limit was crossed by the ID.
+	StageID
+	// StageAttribute means the limit was crossed by an attribute pair.
+	StageAttribute
+)
+
+func (s CompositionStage) String() string {
+	switch s {
+	case StageEntity:
+		return "entity"
+	case StageID:
+		return "id"
+	default:
+		return "attribute"
+	}
+}
+
+// TooLongError breaks down a composed URN that exceeds the length limit.
+// All lengths are of the escaped form. The total length is
+// len("urn:") + EntityLen + 1 + IDLen + the sum of PairLens, where each
+// pair length includes its two ':' separators.
+//
+// It is returned wrapped in an *InvalidURNError of kind KindTooLong; use
+// errors.As to retrieve it.
+type TooLongError struct {
+	Length    int
+	Max       int
+	EntityLen int
+	IDLen     int
+	PairKeys  []string
+	PairLens  []int
+	// Stage is the component during which the running length first
+	// exceeded Max; Pair is the index of that attribute pair, or -1.
+	Stage CompositionStage
+	Pair  int
+}
+
+// Overflow returns how many characters over the limit the URN is.
+func (e *TooLongError) Overflow() int {
+	return e.Length - e.Max
+}
+
+func (e *TooLongError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Composed URN is too long (%d chars, max %d)", e.Length, e.Max)
+	if e.Stage == StageAttribute {
+		fmt.Fprintf(&b, "; limit crossed at attribute %d (%s)", e.Pair, e.PairKeys[e.Pair])
+	} else {
+		fmt.Fprintf(&b, "; limit crossed at %s", e.Stage)
+	}
+	fmt.Fprintf(&b, "; entity %d, id %d", e.EntityLen, e.IDLen)
+	for i, n := range e.PairLens {
+		fmt.Fprintf(&b, ", %s %d", e.PairKeys[i], n)
+	}
+	return b.String()
+}
+
+// checkLength returns an error wrapping a *TooLongError when the composed
+// URN would exceed max, or nil.
+func checkLength(entity, id string, pairs []Attribute, max int) error {
+	if composedLen(entity, id, pairs) <= max {
+		return nil
+	}
+	e := &TooLongError{
+		Max:       max,
+		EntityLen: escapedLen(entity),
+		IDLen:     escapedLen(id),
+		Pair:      -1,
+	}
+	n := len("urn:") + e.EntityLen
+	crossed := n > max
+	n += 1 + e.IDLen
+	if !crossed && n > max {
+		e.Stage, crossed = StageID, true
+	}
+	if len(pairs) > 0 {
+		e.PairKeys = make([]string, len(pairs))
+		e.PairLens = make([]int, len(pairs))
+	}
+	for i, p := range pairs {
+		e.PairKeys[i] = p.Key
+		e.PairLens[i] = 2 + escapedLen(p.Key) + escapedLen(p.Value)
+		n += e.PairLens[i]
+		if !crossed && n > max {
+			e.Stage, e.Pair, crossed = StageAttribute, i, true
+		}
+	}
+	if !crossed {
+		return nil
+	}
+	e.Length = n
+	return &InvalidURNError{Kind: KindTooLong, Reason: ReasonComposed, Op: OpCompose, Limit: max, Actual: n, Err: e}
+}
@@ -0,0 +1,71 @@
+package urn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func tooLongFrom(t *testing.T, err error) *TooLongError {
+	t.Helper()
+	var tl *TooLongError
+	if !errors.As(err, &tl) {
+		t.Fatalf("expected *TooLongError, got %v", err)
+	}
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Kind != KindTooLong {
+		t.Fatalf("expected *InvalidURNError of kind too long, got %v", err)
+	}
+	return tl
+}
+
+func TestTooLongAtID(t *testing.T) {
+	_, err := ComposeAttrs("orders", strings.Repeat("x", 250), []Attribute{{Key: "a", Value: "1"}})
+	tl := tooLongFrom(t, err)
+	if tl.Stage != StageID || tl.Pair != -1 {
+		t.Errorf("unexpected stage: %s pair %d", tl.Stage, tl.Pair)
+	}
+	if tl.EntityLen != 6 || tl.IDLen != 250 || len(tl.PairLens) != 1 || tl.PairLens[0] != 4 {
+		t.Errorf("unexpected breakdown: %+v", tl)
+	}
+	if tl.Length != 4+6+1+250+4 || tl.Overflow() != tl.Length-MaxURNLength {
+		t.Errorf("unexpected length: %d", tl.Length)
+	}
+}
+
+func TestTooLongAtAttribute(t *testing.T) {
+	_, err := ComposeAttrs("orders", "1", []Attribute{
+		{Key: "a", Value: strings.Repeat("x", 100)},
+		{Key: "b", Value: strings.Repeat(":", 50)},
+		{Key: "d", Value: "1"},
+	})
+	tl := tooLongFrom(t, err)
+	if tl.Stage != StageAttribute || tl.Pair != 1 {
+		t.Errorf("unexpected stage: %s pair %d", tl.Stage, tl.Pair)
+	}
+	if tl.PairLens[1] != 2+1+150 {
+		t.Errorf("expected escaped pair length, got %d", tl.PairLens[1])
+	}
+	if !strings.Contains(err.Error(), "limit crossed at attribute 1 (b)") {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestTooLongAtEntity(t *testing.T) {
+	err := checkLength(strings.Repeat("e", 300), "1", nil, MaxURNLength)
+	tl := tooLongFrom(t, err)
+	if tl.Stage != StageEntity {
+		t.Errorf("unexpected stage: %s", tl.Stage)
+	}
+}
+
+func TestTooLongFromAddAttribute(t *testing.T) {
+	_, err := AddAttribute("urn:orders:1", "note", strings.Repeat("y", 260))
+	tl := tooLongFrom(t, err)
+	if tl.Stage != StageAttribute || tl.Pair != 0 || tl.PairKeys[0] != "note" {
+		t.Errorf("unexpected breakdown: %+v", tl)
+	}
+	if !strings.HasPrefix(err.Error(), "Composed URN is too long (") {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
@@ -0,0 +1,52 @@
+package urn
+
+import "maps"
+
+// SetTransient attaches in-memory metadata, such as a trace ID, to u.
+// Transient values are never serialized and are ignored by String,
+// Canonical, Equal, Equivalent, Hash and every marshaler. Setting a key
+// again replaces its value. SetTransient is not safe for concurrent use
+// with other methods of the same URN.
+func (u *URN) SetTransient(key string, value any) {
+	if u.transients == nil {
+		u.transients = make(map[string]any)
+	}
+	u.transients[key] = value
+}
+
+// Transient returns the value attached with SetTransient under key.
+func (u *URN) Transient(key string) (any, bool) {
+	v, ok := u.transients[key]
+	return v, ok
+}
+
+// CloneOption configures Clone.
+type CloneOption func(*cloneConfig)
+
+type cloneConfig struct {
+	dropTransients bool
+}
+
+// DropTransients makes Clone leave out the values attached with
+// SetTransient. By default the clone carries a copy of them; the values
+// themselves are shared.
+func DropTransients() CloneOption {
+	return func(c *cloneConfig) {
+		c.dropTransients = true
+	}
+}
+
+// cloneTransients returns the transients a clone of u carries under opts.
+func (u *URN) cloneTransients(opts []CloneOption) map[string]any {
+	if len(u.transients) == 0 {
+		return nil
+	}
+	var c cloneConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.dropTransients {
+		return nil
+	}
+	return maps.Clone(u.transients)
+}
@@ -0,0 +1,60 @@
+package urn
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTransient(t *testing.T) {
+	u, _ := Parse("urn:user:1:k:v")
+	plain, _ := Parse("urn:user:1:k:v")
+	if _, ok := u.Transient("trace"); ok {
+		t.Error("fresh URN has a transient")
+	}
+	u.SetTransient("trace", "abc123")
+	u.SetTransient("fetched", 42)
+	if v, ok := u.Transient("trace"); !ok || v != "abc123" {
+		t.Errorf("Transient = %v, %v", v, ok)
+	}
+
+	if u.String() != plain.String() || u.Canonical() != plain.Canonical() || u.Hash() != plain.Hash() {
+		t.Error("transients changed the string form")
+	}
+	if !u.Equal(plain) || !u.Equivalent(plain) {
+		t.Error("transients changed equality")
+	}
+	for name, marshal := range map[string]func(*URN) ([]byte, error){
+		"text":         (*URN).MarshalText,
+		"ordered json": (*URN).MarshalOrderedJSON,
+		"json object":  (*URN).MarshalJSONOrderedObject,
+		"json":         func(u *URN) ([]byte, error) { return json.Marshal(u) },
+	} {
+		a, err1 := marshal(u)
+		b, err2 := marshal(plain)
+		if err1 != nil || err2 != nil || string(a) != string(b) {
+			t.Errorf("%s: %s vs %s (%v, %v)", name, a, b, err1, err2)
+		}
+	}
+}
+
+func TestCloneTransients(t *testing.T) {
+	u, _ := Parse("urn:user:1")
+	u.SetTransient("trace", "abc")
+
+	c := u.Clone()
+	if v, ok := c.Transient("trace"); !ok || v != "abc" {
+		t.Errorf("Clone dropped transient: %v, %v", v, ok)
+	}
+	c.SetTransient("trace", "changed")
+	if v, _ := u.Transient("trace"); v != "abc" {
+		t.Errorf("clone shares the transient map: original now %v", v)
+	}
+
+	d := u.Clone(DropTransients())
+	if _, ok := d.Transient("trace"); ok {
+		t.Error("Clone(DropTransients()) kept the transient")
+	}
+	if !d.Equal(u) {
+		t.Error("clone not Equal")
+	}
+}
@@ -2,28 +2,25 @@ package urn
 
 import (
 	"fmt"
-	"net/url"
 	"regexp"
 	"strings"
-
-	"github.com/google/uuid"
 )
 
 const MaxURNLength = 255
 
-var entityRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{1,31}$`)
+// DefaultMaxSegments is the default cap on colon-separated segments,
+// scheme included, accepted by parsing. See WithMaxSegments.
+const DefaultMaxSegments = 64
 
-// InvalidURNError is returned when a URN string is malformed.
-type InvalidURNError struct {
-	Message string
-}
+var entityRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{1,31}$`)
 
-func (e *InvalidURNError) Error() string {
-	return e.Message
-}
+// dottedEntityRegex matches entities made of dot-separated parts; see
+// EntityAllowDots. Overall length is checked separately.
+var dottedEntityRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*(\.[A-Za-z0-9][A-Za-z0-9-]*)*$`)
 
-// attrPair preserves insertion order of attributes.
-type attrPair struct {
+// Attribute is a single key-value pair. Slices of Attribute preserve the
+// order in which pairs appear in a URN.
+type Attribute struct {
 	Key   string
 	Value string
 }
@@ -32,7 +29,31 @@ type attrPair struct {
 type URN struct {
 	Entity     string
 	ID         string
-	attributes []attrPair
+	attributes []Attribute
+	raw        string
+	// trailingFlag records that the last attribute was a bare flag key
+	// accepted under TrailingKeyAsFlag.
+	trailingFlag bool
+	// dialect records the separator style ParseAny found in the input.
+	dialect Dialect
+	// aliasedFrom is the deprecated entity ParseWithAliases replaced.
+	aliasedFrom string
+	// quoted records that the input used quoted values (WithQuoting).
+	quoted bool
+	// sharedAttrs records that attributes may be shared with other URNs,
+	// so it must be copied before it is changed in place. Only the copy is
+	// marked: a URN reachable by callers never changes its attributes in
+	// place, so a URN may be cloned concurrently without writes to it.
+	sharedAttrs bool
+	// parsedEntity records that Entity was read by Parse, which does not
+	// enforce the entity format, so Valid does not either.
+	parsedEntity bool
+	// rest is the raw attribute section left unparsed under
+	// MaxAttributesParsed, re-emitted verbatim after the attributes.
+	rest string
+	// transients holds the values attached with SetTransient; it is
+	// allocated on first use and never serialized.
+	transients map[string]any
 }
 
 // Attributes returns a copy of the attributes as a map.
@@ -44,95 +65,398 @@ func (u *URN) Attributes() map[string]string {
 	return m
 }
 
-// String returns the composed URN string.
+// get returns the value of the first attribute with the key.
+func (u *URN) get(key string) (string, bool) {
+	for _, p := range u.attributes {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// GetOr returns the value of the first attribute with the key, or def when
+// there is none.
+func (u *URN) GetOr(key, def string) string {
+	if v, ok := u.get(key); ok {
+		return v
+	}
+	return def
+}
+
+// AttributePairs returns a copy of the attributes in their original order,
+// including duplicate keys.
+func (u *URN) AttributePairs() []Attribute {
+	if len(u.attributes) == 0 {
+		return nil
+	}
+	pairs := make([]Attribute, len(u.attributes))
+	copy(pairs, u.attributes)
+	return pairs
+}
+
+// AllAttributePairs is like AttributePairs but also decodes the attribute
+// section MaxAttributesParsed left unparsed and appends its pairs, so the
+// result describes every attribute the URN string carries. A bare flag
+// key at the end of that section is returned with value FlagValue. It
+// fails when the section does not decode, which parsing did not check.
+func (u *URN) AllAttributePairs() ([]Attribute, error) {
+	if u.rest == "" {
+		return u.AttributePairs(), nil
+	}
+	// The section is shorter than MaxURNLength, which bounds its segments.
+	tail, _, _, err := parseTail(u.rest, 0, &options{trailingFlag: true, maxSegments: MaxURNLength})
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]Attribute, 0, len(u.attributes)+len(tail))
+	pairs = append(pairs, u.attributes...)
+	return append(pairs, tail...), nil
+}
+
+// Raw returns the exact input string the URN was parsed from. It is empty
+// for URNs that were built rather than parsed, and when raw retention was
+// disabled with DiscardRaw.
+func (u *URN) Raw() string {
+	return u.raw
+}
+
+// Clone returns a copy of the URN. The copy shares the attribute array
+// with u until one of them is changed, which is invisible to callers since
+// neither can observe the other's changes. Transient values are carried
+// over unless DropTransients is given.
+func (u *URN) Clone(opts ...CloneOption) *URN {
+	c := *u
+	c.shareAttributes()
+	c.transients = u.cloneTransients(opts)
+	return &c
+}
+
+// String returns the composed URN string, or "" if the URN is not Valid.
+// A trailing flag accepted under TrailingKeyAsFlag is re-emitted in its
+// bare legacy form.
 func (u *URN) String() string {
-	s, _ := compose(u.Entity, u.ID, u.attributes)
+	s, _ := u.StringE()
+	return s
+}
+
+// StringE is like String but reports why a URN cannot be serialized.
+func (u *URN) StringE() (string, error) {
+	if err := u.Valid(); err != nil {
+		return "", err
+	}
+	s, err := composeURN("urn", u)
+	if err != nil {
+		return "", err
+	}
+	return u.legacyFlagForm(s), nil
+}
+
+// HasTrailingFlag reports whether the URN was parsed under
+// TrailingKeyAsFlag from input ending in a bare flag key, which was
+// recorded as an attribute with value FlagValue.
+func (u *URN) HasTrailingFlag() bool {
+	return u.trailingFlag
+}
+
+// legacyFlagForm drops the ":true" a trailing flag gained in s, provided
+// the flag attribute is still last and unchanged.
+func (u *URN) legacyFlagForm(s string) string {
+	if u.trailingFlag && len(u.attributes) > 0 && u.attributes[len(u.attributes)-1].Value == FlagValue {
+		return strings.TrimSuffix(s, ":"+FlagValue)
+	}
 	return s
 }
 
-// CreateUUID generates a URN with a new UUID as the identifier.
+// CreateUUID generates a URN with a new UUID as the identifier, taken
+// from the source installed with SetUUIDSource.
 func CreateUUID(entity string) string {
-	id := uuid.New().String()
-	s, _ := Compose(entity, id)
+	s, _ := New(entity, UUIDGenerator)
 	return s
 }
 
-// Compose constructs a URN string from the given components.
+// Compose constructs a URN string from the given components. Violations
+// are reported as by ComposeAttrs.
 func Compose(entity, id string, attrs ...map[string]string) (string, error) {
-	var pairs []attrPair
+	var pairs []Attribute
 	if len(attrs) > 0 && attrs[0] != nil {
 		for k, v := range attrs[0] {
-			pairs = append(pairs, attrPair{Key: k, Value: v})
+			pairs = append(pairs, Attribute{Key: k, Value: v})
 		}
 	}
-	return compose(entity, id, pairs)
+	return ComposeAttrs(entity, id, pairs)
+}
+
+// ComposeAttrs constructs a URN string, emitting attributes exactly in the
+// order of the slice. Duplicate keys are passed through untouched.
+//
+// Every component is validated before failing, so one call reports all
+// problems: a single violation is returned as an *InvalidURNError, and
+// several are joined with errors.Join in component order, each an
+// *InvalidURNError whose Segment names the attribute key it concerns.
+func ComposeAttrs(entity, id string, attrs []Attribute) (string, error) {
+	return defaultProcessor.ComposeAttrs(entity, id, attrs)
+}
+
+func compose(entity, id string, pairs []Attribute) (string, error) {
+	return composeScheme("urn", entity, id, pairs)
+}
+
+// composeScheme composes a URN under the given scheme. The length limit is
+// always applied to the canonical "urn:" form, so that a URN emitted under
+// an alias stays valid once expanded.
+func composeScheme(scheme, entity, id string, pairs []Attribute) (string, error) {
+	return composeRest(scheme, entity, id, pairs, "")
+}
+
+// composeURN composes u under scheme, re-emitting the attribute section
+// MaxAttributesParsed left unparsed.
+func composeURN(scheme string, u *URN) (string, error) {
+	return composeRest(scheme, u.Entity, u.ID, u.attributes, u.rest)
 }
 
-func compose(entity, id string, pairs []attrPair) (string, error) {
+// composeRest is composeScheme followed by rest, a raw attribute section
+// that is appended without escaping.
+func composeRest(scheme, entity, id string, pairs []Attribute, rest string) (string, error) {
 	if entity == "" || id == "" {
-		return "", &InvalidURNError{Message: "Cannot compose URN: 'entity' and 'id' are required"}
+		return "", &InvalidURNError{Kind: KindEmptyComponent, Op: OpCompose}
 	}
 
-	safeEntity := url.PathEscape(entity)
-	safeID := url.PathEscape(id)
+	// Size the output exactly so building it costs a single allocation.
+	n := composedLen(entity, id, pairs)
+	if rest != "" {
+		n += 1 + len(rest)
+		if n > MaxURNLength {
+			return "", &InvalidURNError{
+				Kind:   KindTooLong,
+				Op:     OpCompose,
+				Limit:  MaxURNLength,
+				Actual: n,
+			}
+		}
+	}
+	if n > MaxURNLength {
+		return "", checkLength(entity, id, pairs, MaxURNLength)
+	}
 	var b strings.Builder
-	b.WriteString("urn:")
-	b.WriteString(safeEntity)
-	b.WriteString(":")
-	b.WriteString(safeID)
-
+	b.Grow(n - len("urn") + len(scheme))
+	b.WriteString(scheme)
+	b.WriteByte(':')
+	writeEscape(&b, entity)
+	b.WriteByte(':')
+	writeEscape(&b, id)
 	for _, p := range pairs {
-		b.WriteString(":")
-		b.WriteString(url.PathEscape(p.Key))
-		b.WriteString(":")
-		b.WriteString(url.PathEscape(p.Value))
+		b.WriteByte(':')
+		writeEscape(&b, p.Key)
+		b.WriteByte(':')
+		writeEscape(&b, p.Value)
+	}
+	if rest != "" {
+		b.WriteByte(':')
+		b.WriteString(rest)
+	}
+	return b.String(), nil
+}
+
+// Parse deconstructs a URN string into its components. It is lenient:
+// percent-encoding is decoded in every component, including the entity, so
+// "urn:%6Frders:1" parses with entity "orders". Use ParseStrict, which
+// rejects encoded entities, before checking entities against an allowlist.
+func Parse(urnStr string) (*URN, error) {
+	return parse(urnStr, &loadConfig().opts)
+}
+
+func parse(urnStr string, o *options) (u *URN, err error) {
+	if debugGuards {
+		defer recoverPanic("Parse", &err)
 	}
+	u, err = parseURN(urnStr, o)
+	observeParse(u, err)
+	return u, err
+}
 
-	result := b.String()
-	if len(result) > MaxURNLength {
-		return "", &InvalidURNError{
-			Message: fmt.Sprintf("Composed URN is too long (%d chars, max %d)", len(result), MaxURNLength),
+func parseURN(urnStr string, o *options) (*URN, error) {
+	if o.quoting {
+		return parseQuoted(urnStr, o)
+	}
+	entity, id, tailOff, err := parseHead(urnStr, o)
+	if err != nil {
+		return nil, err
+	}
+	var attrs []Attribute
+	var flagged bool
+	var rest string
+	if tailOff >= 0 {
+		if attrs, flagged, rest, err = parseTail(urnStr, tailOff, o); err != nil {
+			return nil, err
 		}
 	}
-	return result, nil
+	u := &URN{Entity: entity, ID: id, attributes: attrs, trailingFlag: flagged, rest: rest, parsedEntity: true}
+	if !o.discardRaw {
+		u.raw = urnStr
+	}
+	return u, nil
 }
 
-// Parse deconstructs a URN string into its components.
-func Parse(urnStr string) (*URN, error) {
-	if !strings.HasPrefix(strings.ToLower(urnStr), "urn:") {
-		return nil, &InvalidURNError{Message: "Invalid URN: Must start with the 'urn:' scheme"}
+// parseHead parses the scheme, entity and ID. tailOff is the byte offset
+// of the attribute section, or -1 when the URN has none.
+func parseHead(urnStr string, o *options) (entity, id string, tailOff int, err error) {
+	start := o.schemeEnd(urnStr)
+	if start < 0 {
+		return "", "", -1, &InvalidURNError{
+			Kind: KindScheme,
+		}
+	}
+	// Measured in the canonical "urn:" form so aliases share the limit.
+	if n := len(urnStr) - start + len("urn:"); n > MaxURNLength {
+		return "", "", -1, &InvalidURNError{
+			Kind:   KindTooLong,
+			Offset: start + MaxURNLength - len("urn:"),
+			Limit:  MaxURNLength,
+			Actual: n,
+		}
+	}
+	// start, entityEnd and idOff below never pass len(urnStr): each is at
+	// most one past a ':' found in urnStr.
+	entityEnd := strings.IndexByte(urnStr[start:], ':')
+	if entityEnd < 0 {
+		return "", "", -1, &InvalidURNError{
+			Kind:   KindMissingComponent,
+			Offset: len(urnStr),
+		}
+	}
+	entityEnd += start
+	idOff := entityEnd + 1
+	idEnd := len(urnStr)
+	tailOff = -1
+	if i := strings.IndexByte(urnStr[idOff:], ':'); i >= 0 {
+		idEnd = idOff + i
+		tailOff = idEnd + 1
 	}
-	content := urnStr[4:]
-	parts := strings.Split(content, ":")
 
-	if len(parts) < 2 {
-		return nil, &InvalidURNError{Message: "Invalid URN: Missing entity or ID component"}
+	rawEntity, rawID := urnStr[start:entityEnd], urnStr[idOff:idEnd]
+	if rawEntity == "" || rawID == "" {
+		off := start
+		if rawEntity != "" {
+			off = idOff
+		}
+		return "", "", -1, &InvalidURNError{
+			Kind:   KindEmptyComponent,
+			Offset: off,
+		}
 	}
+	if entity, err = unescapeAt(rawEntity, start); err != nil {
+		return "", "", -1, err
+	}
+	if id, err = unescapeAt(rawID, idOff); err != nil {
+		return "", "", -1, err
+	}
+	return entity, id, tailOff, nil
+}
 
-	entity := parts[0]
-	id := parts[1]
-	if entity == "" || id == "" {
-		return nil, &InvalidURNError{Message: "Invalid URN: Entity or ID is empty"}
+// parseTail parses the attribute pairs starting at byte offset off. The
+// scheme, entity and ID count towards the segment limit. flagged reports
+// that a trailing unpaired key was accepted under TrailingKeyAsFlag. Under
+// MaxAttributesParsed, rest is the raw input after the last parsed pair.
+func parseTail(urnStr string, off int, o *options) (attrs []Attribute, flagged bool, rest string, err error) {
+	if off < 0 || off > len(urnStr) {
+		return nil, false, "", &InvalidURNError{
+			Kind:   KindMissingComponent,
+			Offset: len(urnStr),
+		}
+	}
+	maxSegs := o.segmentLimit()
+	// Count segments without allocating, stopping at the limit, so hostile
+	// inputs cannot force large allocations below.
+	n := 1
+	for i := off; ; n++ {
+		j := strings.IndexByte(urnStr[i:], ':')
+		if 3+n > maxSegs {
+			return nil, false, "", &InvalidURNError{
+				Kind:   KindTooManySegments,
+				Offset: i,
+				Limit:  maxSegs,
+				Err:    ErrTooManySegments,
+			}
+		}
+		if j < 0 {
+			break
+		}
+		i += j + 1
 	}
 
-	rest := parts[2:]
-	if len(rest)%2 != 0 {
-		return nil, &InvalidURNError{Message: "Invalid URN: Attribute key without value"}
+	// n is now the exact segment count, so attrs is allocated once and the
+	// pairs are read straight from urnStr.
+	if n%2 != 0 {
+		last := off
+		if j := strings.LastIndexByte(urnStr[off:], ':'); j >= 0 {
+			last += j + 1
+		}
+		if !o.trailingFlag || last == len(urnStr) {
+			return nil, false, "", &InvalidURNError{
+				Kind:    KindUnpairedKey,
+				Offset:  last,
+				Segment: urnStr[last:],
+			}
+		}
+		flagged = true
 	}
 
-	var attrs []attrPair
-	for i := 0; i < len(rest); i += 2 {
-		key := rest[i]
-		value := rest[i+1]
+	pairs := (n + 1) / 2
+	if o.maxAttrs > 0 && pairs > o.maxAttrs {
+		// The unparsed remainder keeps a trailing flag key verbatim.
+		pairs, flagged = o.maxAttrs, false
+	}
+	attrs = make([]Attribute, 0, pairs)
+	for i := off; i >= 0; {
+		if len(attrs) == pairs {
+			rest = urnStr[i:]
+			break
+		}
+		keyOff := i
+		var key, value string
+		key, i = nextSegment(urnStr, i)
+		valueOff := i
+		if i < 0 {
+			value, valueOff = FlagValue, len(urnStr)
+		} else {
+			value, i = nextSegment(urnStr, i)
+		}
 		if key == "" || value == "" {
-			return nil, &InvalidURNError{
-				Message: fmt.Sprintf("Invalid URN: Attribute %s missing value", key),
+			at, seg, reason := keyOff, key, ReasonKey
+			if key != "" {
+				at, reason = valueOff, ReasonValue
+			}
+			return nil, false, "", &InvalidURNError{
+				Kind:    KindEmptyAttribute,
+				Reason:  reason,
+				Offset:  at,
+				Segment: seg,
+				Key:     key,
 			}
 		}
-		attrs = append(attrs, attrPair{Key: key, Value: value})
+		if key, err = unescapeAt(key, keyOff); err != nil {
+			return nil, false, "", err
+		}
+		if value, err = unescapeAt(value, valueOff); err != nil {
+			return nil, false, "", err
+		}
+		attrs = append(attrs, Attribute{Key: key, Value: value})
 	}
+	return attrs, flagged, rest, nil
+}
 
-	return &URN{Entity: entity, ID: id, attributes: attrs}, nil
+// nextSegment returns the segment of s starting at i and the start of the
+// following segment, or -1 if it is the last.
+func nextSegment(s string, i int) (string, int) {
+	if i < 0 || i > len(s) {
+		return "", -1
+	}
+	j := strings.IndexByte(s[i:], ':')
+	if j < 0 {
+		return s[i:], -1
+	}
+	return s[i : i+j], i + j + 1
 }
 
 // Entity extracts the entity from a URN string.
@@ -156,70 +480,281 @@ func ID(urnStr string) (string, error) {
 // Value retrieves the value for a specific attribute key.
 // Returns the value, whether it was found, and any parse error.
 func Value(urnStr, key string) (string, bool, error) {
-	u, err := Parse(urnStr)
+	return defaultProcessor.Value(urnStr, key)
+}
+
+// ValueOr returns the value for key, or def when the URN has no such
+// attribute. Parse errors are still returned.
+func ValueOr(urnStr, key, def string) (string, error) {
+	v, found, err := Value(urnStr, key)
 	if err != nil {
-		return "", false, err
+		return "", err
 	}
-	for _, p := range u.attributes {
-		if p.Key == key {
-			return p.Value, true, nil
-		}
+	if !found {
+		return def, nil
+	}
+	return v, nil
+}
+
+// MustValue returns the value for key and panics if the URN does not
+// parse or has no such attribute. It is intended for tests.
+func MustValue(urnStr, key string) string {
+	v, found, err := Value(urnStr, key)
+	if err != nil {
+		panic(err)
+	}
+	if !found {
+		panic(fmt.Sprintf("urn: %q has no attribute %q", urnStr, key))
 	}
-	return "", false, nil
+	return v
+}
+
+// HasAttribute reports whether the URN carries an attribute with the key.
+func HasAttribute(urnStr, key string) (bool, error) {
+	return defaultProcessor.HasAttribute(urnStr, key)
 }
 
 // IsValid checks whether a string is a valid URN.
 func IsValid(urnStr string) bool {
-	if urnStr == "" || len(urnStr) > MaxURNLength {
-		return false
+	return Check(urnStr) == nil
+}
+
+// Check reports why a string is not a valid URN, or nil when it is. The
+// returned error is an *InvalidURNError whose Kind, Offset and Segment
+// locate the problem.
+func Check(urnStr string) error {
+	_, err := ParseStrict(urnStr)
+	return err
+}
+
+// ValidateString checks whether a string is a valid URN, returning the
+// reason when it is not. It is equivalent to Check and is meant to be
+// plugged into format validators ("format: urn").
+func ValidateString(urnStr string) error {
+	return Check(urnStr)
+}
+
+// ParseStrict parses a URN and additionally enforces the rules checked by
+// IsValid: a length limit, a well-formed entity written without
+// percent-encoding, decoded components that are valid UTF-8 and no raw
+// control characters. Options may tighten or relax the rules; they are
+// applied on top of those passed to SetDefaults.
+func ParseStrict(urnStr string, opts ...Option) (*URN, error) {
+	c := loadConfig()
+	o := c.opts
+	if len(opts) > 0 {
+		o = newOptions(append(append([]Option(nil), c.defaults...), opts...))
 	}
-	u, err := Parse(urnStr)
+	return parseStrict(urnStr, &o, c)
+}
+
+func parseStrict(urnStr string, o *options, c *config) (*URN, error) {
+	if o.trailingFlag {
+		strict := *o
+		strict.trailingFlag = false
+		o = &strict
+	}
+	var sc strictChecker
+	if !sc.checkInput(urnStr) {
+		return nil, sc.errs[0]
+	}
+	u, err := parse(urnStr, o)
 	if err != nil {
-		return false
+		return nil, err
 	}
-	if !entityRegex.MatchString(u.Entity) {
-		return false
+	if !sc.checkURN(urnStr, u, o, c) {
+		return nil, sc.errs[0]
 	}
-	return true
+	return u, nil
 }
 
-// AddAttribute appends or updates an attribute in the URN.
-func AddAttribute(urnStr, key, value string) (string, error) {
-	u, err := Parse(urnStr)
-	if err != nil {
-		return "", err
+// strictChecker runs the checks ParseStrict adds on top of Parse. It stops
+// at the first failure unless all is set, in which case it records every
+// failure and the check methods always report true.
+type strictChecker struct {
+	all  bool
+	errs []*InvalidURNError
+}
+
+// fail records e and reports whether checking should continue.
+func (sc *strictChecker) fail(e *InvalidURNError) bool {
+	sc.errs = append(sc.errs, e)
+	return sc.all
+}
+
+// checkInput runs the checks on the raw input that come before parsing.
+func (sc *strictChecker) checkInput(urnStr string) bool {
+	if urnStr == "" {
+		return sc.fail(&InvalidURNError{Kind: KindEmpty})
+	}
+	if len(urnStr) > MaxURNLength {
+		ok := sc.fail(&InvalidURNError{
+			Kind:   KindTooLong,
+			Offset: MaxURNLength,
+			Limit:  MaxURNLength,
+			Actual: len(urnStr),
+		})
+		if !ok {
+			return false
+		}
 	}
-	safeKey := url.PathEscape(key)
-	safeValue := url.PathEscape(value)
+	if i := indexControl(urnStr); i >= 0 {
+		return sc.fail(&InvalidURNError{
+			Kind:   KindControlChar,
+			Offset: i,
+		})
+	}
+	return true
+}
 
-	found := false
+// checkURN runs the checks on u, parsed from urnStr.
+func (sc *strictChecker) checkURN(urnStr string, u *URN, o *options, c *config) bool {
+	if !isASCII(u.Entity) {
+		seg, off := segmentAt(urnStr, 0)
+		ok := sc.fail(&InvalidURNError{
+			Kind:    KindInvalidEntity,
+			Reason:  ReasonNonASCII,
+			Offset:  off,
+			Segment: seg,
+			Entity:  u.Entity,
+		})
+		if !ok {
+			return false
+		}
+	} else if !o.validEntity(u.Entity) {
+		seg, off := segmentAt(urnStr, 0)
+		ok := sc.fail(&InvalidURNError{
+			Kind:    KindInvalidEntity,
+			Offset:  off,
+			Segment: seg,
+			Entity:  u.Entity,
+		})
+		if !ok {
+			return false
+		}
+	}
+	// An encoded entity only matches an allowlist once decoded, and layers
+	// that compare raw and decoded forms disagree about it, so the scheme
+	// and entity must be written literally.
+	seg, next := nextSegment(urnStr, 0)
+	off := 0
+	if !strings.Contains(seg, "%") && next >= 0 {
+		seg, _ = nextSegment(urnStr, next)
+		off = next
+	}
+	if i := strings.IndexByte(seg, '%'); i >= 0 {
+		ok := sc.fail(&InvalidURNError{
+			Kind:    KindEncodedEntity,
+			Offset:  off + i,
+			Segment: seg,
+		})
+		if !ok {
+			return false
+		}
+	}
+	if v := c.idValidator(u.Entity); v != nil {
+		if err := v(u.ID); err != nil {
+			if !sc.fail(invalidIDError(urnStr, u, err).(*InvalidURNError)) {
+				return false
+			}
+		}
+	}
+	// A valid checksum passes even when ChecksumKey is reserved.
+	sum := u.checksumIndex()
 	for i, p := range u.attributes {
-		if p.Key == safeKey {
-			u.attributes[i].Value = safeValue
-			found = true
-			break
+		if i == sum {
+			continue
+		}
+		if err := c.checkKey(p.Key); err != nil {
+			ue := err.(*InvalidURNError)
+			ue.Segment, ue.Offset = segmentAt(urnStr, 2+2*i)
+			if !sc.fail(ue) {
+				return false
+			}
 		}
 	}
-	if !found {
-		u.attributes = append(u.attributes, attrPair{Key: safeKey, Value: safeValue})
+	if !o.binaryComponents && !sc.checkUTF8(urnStr, u) {
+		return false
+	}
+	if !o.rejectControlChars {
+		return true
+	}
+	if indexControl(u.ID) >= 0 {
+		seg, off := segmentAt(urnStr, 1)
+		ok := sc.fail(&InvalidURNError{
+			Kind:    KindControlChar,
+			Reason:  ReasonID,
+			Offset:  off,
+			Segment: seg,
+			ID:      u.ID,
+		})
+		if !ok {
+			return false
+		}
+	}
+	for i, p := range u.attributes {
+		if indexControl(p.Key) >= 0 || indexControl(p.Value) >= 0 {
+			reason := ReasonKey
+			if indexControl(p.Key) < 0 {
+				reason = ReasonValue
+			}
+			seg, off := segmentAt(urnStr, 2+2*i)
+			ok := sc.fail(&InvalidURNError{
+				Kind:    KindControlChar,
+				Reason:  reason,
+				Offset:  off,
+				Segment: seg,
+				Key:     p.Key,
+			})
+			if !ok {
+				return false
+			}
+		}
 	}
-	return compose(u.Entity, u.ID, u.attributes)
+	return true
 }
 
-// RemoveAttribute removes an attribute by key from the URN.
-func RemoveAttribute(urnStr, key string) (string, error) {
-	u, err := Parse(urnStr)
-	if err != nil {
-		return "", err
+// segmentAt returns the n-th raw segment after the scheme (0 is the entity)
+// and its byte offset within urnStr.
+func segmentAt(urnStr string, n int) (string, int) {
+	off := strings.IndexByte(urnStr, ':') + 1
+	if off == 0 || n < 0 {
+		return "", len(urnStr)
 	}
-	filtered := make([]attrPair, 0, len(u.attributes))
-	for _, p := range u.attributes {
-		if p.Key != key {
-			filtered = append(filtered, p)
+	for ; n > 0; n-- {
+		i := strings.IndexByte(urnStr[off:], ':')
+		if i < 0 {
+			return "", len(urnStr)
 		}
+		off += i + 1
+	}
+	end := strings.IndexByte(urnStr[off:], ':')
+	if end < 0 {
+		return urnStr[off:], off
 	}
-	u.attributes = filtered
-	return compose(u.Entity, u.ID, u.attributes)
+	return urnStr[off : off+end], off
+}
+
+// AddAttribute appends or updates an attribute in the URN.
+func AddAttribute(urnStr, key, value string) (string, error) {
+	return defaultProcessor.AddAttribute(urnStr, key, value)
+}
+
+// RemoveAttribute removes an attribute by key from the URN.
+func RemoveAttribute(urnStr, key string) (string, error) {
+	return defaultProcessor.RemoveAttribute(urnStr, key)
+}
+
+// SetAttributeIf sets key to value when cond approves the current value.
+// See (*Processor).SetAttributeIf.
+func SetAttributeIf(urnStr, key, value string, cond func(current string, exists bool) bool) (string, bool, error) {
+	return defaultProcessor.SetAttributeIf(urnStr, key, value, cond)
+}
+
+// CompareAndSwapAttribute sets key to newValue only if its current value
+// is old, reporting whether the swap happened.
+func CompareAndSwapAttribute(urnStr, key, old, newValue string) (string, bool, error) {
+	return defaultProcessor.CompareAndSwapAttribute(urnStr, key, old, newValue)
 }
 
 // GetAllAttributes returns all key-value attribute pairs from a URN.
@@ -236,11 +771,23 @@ func Vendor(urnStr string) (string, bool, error) {
 	return Value(urnStr, "vendor")
 }
 
-// Normalize lowercases the entity and re-composes the URN.
+// Normalize lowercases the entity and re-composes the URN. Only ASCII
+// letters are folded; other characters are left as they are. Normalizers
+// registered with RegisterNormalizer in SetDefaults run afterwards.
 func Normalize(urnStr string) (string, error) {
-	u, err := Parse(urnStr)
-	if err != nil {
-		return "", err
+	return defaultProcessor.Normalize(urnStr)
+}
+
+// invalidIDError reports that an ID validator rejected the ID of u, parsed
+// from urnStr.
+func invalidIDError(urnStr string, u *URN, err error) error {
+	seg, off := segmentAt(urnStr, 1)
+	return &InvalidURNError{
+		Kind:    KindInvalidID,
+		Offset:  off,
+		Segment: seg,
+		Entity:  u.Entity,
+		ID:      u.ID,
+		Err:     err,
 	}
-	return compose(strings.ToLower(u.Entity), u.ID, u.attributes)
 }
@@ -222,3 +222,145 @@ func TestURNString(t *testing.T) {
 		t.Errorf("unexpected String(): %s", u.String())
 	}
 }
+
+func TestParseDecodesEscapes(t *testing.T) {
+	u, err := Parse("urn:orders:a%3Ab:customer:john%20doe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != "a:b" || u.Attributes()["customer"] != "john doe" {
+		t.Errorf("unexpected decode: %+v", u)
+	}
+	if u.String() != "urn:orders:a%3Ab:customer:john%20doe" {
+		t.Errorf("unexpected String(): %s", u.String())
+	}
+}
+
+func TestParseMalformedEscape(t *testing.T) {
+	if _, err := Parse("urn:orders:12%zz"); err == nil {
+		t.Fatal("expected error for malformed escape")
+	}
+}
+
+func TestAddAttributeEscapesOnce(t *testing.T) {
+	updated, err := AddAttribute("urn:orders:1234", "customer", "john doe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != "urn:orders:1234:customer:john%20doe" {
+		t.Errorf("unexpected escaping: %s", updated)
+	}
+}
+
+func TestValidateString(t *testing.T) {
+	if err := ValidateString("urn:orders:1234"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, s := range []string{"", "invalid:orders:1234", "urn:o:1234", "urn:long" + strings.Repeat(":a", 250)} {
+		err := ValidateString(s)
+		if err == nil {
+			t.Errorf("expected error for %q", s)
+			continue
+		}
+		if _, ok := err.(*InvalidURNError); !ok {
+			t.Errorf("expected *InvalidURNError for %q, got %T", s, err)
+		}
+	}
+}
+
+func TestComposeAttrsPreservesOrder(t *testing.T) {
+	result, err := ComposeAttrs("order", "12345", []Attribute{
+		{Key: "status", Value: "shipped"},
+		{Key: "vendor", Value: "amazon"},
+		{Key: "a", Value: "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "urn:order:12345:status:shipped:vendor:amazon:a:1" {
+		t.Errorf("unexpected order: %s", result)
+	}
+}
+
+func TestComposeAttrsDuplicateKeys(t *testing.T) {
+	attrs := []Attribute{
+		{Key: "tag", Value: "red"},
+		{Key: "tag", Value: "blue"},
+	}
+	result, err := ComposeAttrs("order", "1", attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "urn:order:1:tag:red:tag:blue" {
+		t.Errorf("unexpected result: %s", result)
+	}
+	u, err := Parse(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs := u.AttributePairs()
+	if len(pairs) != 2 || pairs[0] != attrs[0] || pairs[1] != attrs[1] {
+		t.Errorf("unexpected pairs: %v", pairs)
+	}
+}
+
+func TestAttributePairsIsCopy(t *testing.T) {
+	u, _ := Parse("urn:order:1:status:pending")
+	pairs := u.AttributePairs()
+	pairs[0].Value = "changed"
+	if u.String() != "urn:order:1:status:pending" {
+		t.Errorf("URN was modified through AttributePairs: %s", u.String())
+	}
+	empty, _ := Parse("urn:order:1")
+	if empty.AttributePairs() != nil {
+		t.Error("expected nil pairs")
+	}
+}
+
+func TestHasAttribute(t *testing.T) {
+	has, err := HasAttribute("urn:orders:1234:vendor:amazon", "vendor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !has {
+		t.Error("expected attribute to be present")
+	}
+	has, _ = HasAttribute("urn:orders:1234:vendor:amazon", "amazon")
+	if has {
+		t.Error("values must not match as keys")
+	}
+}
+
+func TestValueOr(t *testing.T) {
+	const s = "urn:orders:1234:vendor:amazon"
+	if v, err := ValueOr(s, "vendor", "none"); err != nil || v != "amazon" {
+		t.Errorf("present: %q, %v", v, err)
+	}
+	if v, err := ValueOr(s, "carrier", "none"); err != nil || v != "none" {
+		t.Errorf("absent: %q, %v", v, err)
+	}
+	if v, err := ValueOr("urn:orders", "vendor", "none"); err == nil || v != "" {
+		t.Errorf("parse error: %q, %v", v, err)
+	}
+
+	u, _ := Parse(s)
+	if u.GetOr("vendor", "none") != "amazon" || u.GetOr("carrier", "none") != "none" {
+		t.Error("GetOr")
+	}
+}
+
+func TestMustValue(t *testing.T) {
+	if v := MustValue("urn:orders:1:vendor:amazon", "vendor"); v != "amazon" {
+		t.Errorf("MustValue = %q", v)
+	}
+	for _, in := range []string{"urn:orders:1", "urn:orders"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("MustValue(%q) did not panic", in)
+				}
+			}()
+			MustValue(in, "vendor")
+		}()
+	}
+}
@@ -0,0 +1,151 @@
+// Package urncbor encodes URNs as compact CBOR arrays. It lives in its own
+// package so the core urn package stays free of the CBOR dependency.
+//
+// A URN is encoded as [entity, id] or, with attributes,
+// [entity, id, [key, value, ...]]. An entity registered with
+// RegisterCompactEntity is written as its integer code instead of a
+// string.
+package urncbor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/layerfly/go-urn"
+)
+
+var (
+	mu       sync.RWMutex
+	codes    = make(map[string]uint64)
+	entities = make(map[uint64]string)
+)
+
+// RegisterCompactEntity makes entity encode as code. Encoder and decoder
+// must share the same registrations. Registering an entity or code twice
+// with a different counterpart panics.
+func RegisterCompactEntity(entity string, code uint64) {
+	mu.Lock()
+	defer mu.Unlock()
+	if c, ok := codes[entity]; ok && c != code {
+		panic(fmt.Sprintf("urncbor: entity %q already registered as %d", entity, c))
+	}
+	if e, ok := entities[code]; ok && e != entity {
+		panic(fmt.Sprintf("urncbor: code %d already registered for %q", code, e))
+	}
+	codes[entity] = code
+	entities[code] = entity
+}
+
+// Marshal encodes u, including attributes left unparsed under
+// urn.MaxAttributesParsed.
+func Marshal(u *urn.URN) ([]byte, error) {
+	pairs, err := u.AllAttributePairs()
+	if err != nil {
+		return nil, err
+	}
+	mu.RLock()
+	code, compact := codes[u.Entity]
+	mu.RUnlock()
+	doc := make([]any, 2, 3)
+	doc[0], doc[1] = u.Entity, u.ID
+	if compact {
+		doc[0] = code
+	}
+	if len(pairs) > 0 {
+		kv := make([]string, 0, 2*len(pairs))
+		for _, p := range pairs {
+			kv = append(kv, p.Key, p.Value)
+		}
+		doc = append(doc, kv)
+	}
+	return cbor.Marshal(doc)
+}
+
+// Unmarshal decodes a URN encoded by Marshal and validates it with
+// urn.ParseStrict. An unregistered entity code is an error.
+func Unmarshal(data []byte) (*urn.URN, error) {
+	var doc []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("Invalid CBOR URN: %w", err)
+	}
+	if len(doc) != 2 && len(doc) != 3 {
+		return nil, fmt.Errorf("Invalid CBOR URN: array has %d elements, want 2 or 3", len(doc))
+	}
+	entity, err := decodeEntity(doc[0])
+	if err != nil {
+		return nil, err
+	}
+	var id string
+	if err := cbor.Unmarshal(doc[1], &id); err != nil {
+		return nil, fmt.Errorf("Invalid CBOR URN: id: %w", err)
+	}
+	var kv []string
+	if len(doc) == 3 {
+		if err := cbor.Unmarshal(doc[2], &kv); err != nil {
+			return nil, fmt.Errorf("Invalid CBOR URN: attributes: %w", err)
+		}
+		if len(kv)%2 != 0 {
+			return nil, fmt.Errorf("Invalid CBOR URN: attribute key without value")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("urn:")
+	b.WriteString(urn.EscapeSegment(entity))
+	b.WriteByte(':')
+	b.WriteString(urn.EscapeSegment(id))
+	for _, s := range kv {
+		b.WriteByte(':')
+		b.WriteString(urn.EscapeSegment(s))
+	}
+	return urn.ParseStrict(b.String())
+}
+
+// decodeEntity reads an entity name or a registered entity code.
+func decodeEntity(raw cbor.RawMessage) (string, error) {
+	var entity string
+	if err := cbor.Unmarshal(raw, &entity); err == nil {
+		return entity, nil
+	}
+	var code uint64
+	if err := cbor.Unmarshal(raw, &code); err != nil {
+		return "", fmt.Errorf("Invalid CBOR URN: entity must be a string or an entity code")
+	}
+	mu.RLock()
+	entity, ok := entities[code]
+	mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("Invalid CBOR URN: unknown entity code %d", code)
+	}
+	return entity, nil
+}
+
+// URN wraps a *urn.URN to implement cbor.Marshaler and cbor.Unmarshaler,
+// for use as a field in CBOR-encoded structs.
+type URN struct {
+	*urn.URN
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (u URN) MarshalCBOR() ([]byte, error) {
+	if u.URN == nil {
+		return cbor.Marshal(nil)
+	}
+	return Marshal(u.URN)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (u *URN) UnmarshalCBOR(data []byte) error {
+	if len(data) == 1 && data[0] == 0xf6 { // null
+		u.URN = nil
+		return nil
+	}
+	parsed, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	u.URN = parsed
+	return nil
+}
@@ -0,0 +1,121 @@
+package urncbor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/layerfly/go-urn"
+)
+
+func init() {
+	RegisterCompactEntity("orders", 1)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"urn:orders:1234",
+		"urn:orders:1234:vendor:amazon:region:eu",
+		"urn:invoices:a%3Ab:note:x%2Fy",
+	} {
+		u, err := urn.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := Marshal(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Unmarshal(data)
+		if err != nil {
+			t.Fatalf("Unmarshal(%s): %v", s, err)
+		}
+		if !got.Equal(u) {
+			t.Errorf("round trip of %s = %s", s, got)
+		}
+	}
+}
+
+func TestCompactEntitySize(t *testing.T) {
+	const s = "urn:orders:1234:vendor:amazon"
+	u, _ := urn.Parse(s)
+	compact, _ := Marshal(u)
+	plain, _ := cbor.Marshal([]any{"orders", "1234", []string{"vendor", "amazon"}})
+	if len(compact) != len(plain)-len("orders") {
+		t.Errorf("dictionary encoding is %d bytes, string encoding %d", len(compact), len(plain))
+	}
+	if len(plain) >= len(s) {
+		t.Errorf("CBOR encoding (%d bytes) not smaller than the string (%d)", len(plain), len(s))
+	}
+}
+
+func TestUnknownEntityCode(t *testing.T) {
+	data, _ := cbor.Marshal([]any{uint64(99), "1"})
+	_, err := Unmarshal(data)
+	if err == nil || !strings.Contains(err.Error(), "unknown entity code 99") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestInvalidDocuments(t *testing.T) {
+	for _, doc := range []any{
+		[]any{"orders"},
+		[]any{"orders", "1", []string{"k"}},
+		[]any{true, "1"},
+		[]any{"bad entity", "1"},
+		"urn:orders:1",
+	} {
+		data, _ := cbor.Marshal(doc)
+		if _, err := Unmarshal(data); err == nil {
+			t.Errorf("Unmarshal(%v) succeeded", doc)
+		}
+	}
+}
+
+func TestStructField(t *testing.T) {
+	type event struct {
+		Subject URN `cbor:"s"`
+		Other   URN `cbor:"o"`
+	}
+	u, _ := urn.Parse("urn:orders:7:status:shipped")
+	data, err := cbor.Marshal(event{Subject: URN{u}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got event
+	if err := cbor.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Subject.Equal(u) || got.Other.URN != nil {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestRegisterConflict(t *testing.T) {
+	RegisterCompactEntity("orders", 1) // same pair again is fine
+	defer func() {
+		if recover() == nil {
+			t.Error("conflicting registration did not panic")
+		}
+	}()
+	RegisterCompactEntity("users", 1)
+}
+
+func TestMarshalKeepsUnparsedTail(t *testing.T) {
+	const s = "urn:orders:1234:vendor:amazon:region:eu"
+	u, err := urn.NewProcessor(urn.MaxAttributesParsed(1)).Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != s {
+		t.Errorf("round trip = %s, want %s", got, s)
+	}
+}
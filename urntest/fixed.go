@@ -0,0 +1,26 @@
+package urntest
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FixedUUIDSource returns a source for urn.SetUUIDSource that yields ids in
+// order, starting over after the last one; without ids it yields
+// uuid.Nil. It is safe for concurrent use.
+func FixedUUIDSource(ids ...uuid.UUID) func() uuid.UUID {
+	var n atomic.Uint64
+	return func() uuid.UUID {
+		if len(ids) == 0 {
+			return uuid.Nil
+		}
+		return ids[(n.Add(1)-1)%uint64(len(ids))]
+	}
+}
+
+// FixedClock returns a clock for urn.SetClock that always reports t.
+func FixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
@@ -0,0 +1,127 @@
+// Package urntest provides test helpers for comparing URNs component by
+// component. It lives in its own package so the core urn package stays
+// free of testing and go-cmp dependencies.
+package urntest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/layerfly/go-urn"
+)
+
+type config struct {
+	exact bool
+}
+
+// Option configures Diff and AssertEqual.
+type Option func(*config)
+
+// Exact compares with (*urn.URN).Equal instead of Equivalent: entity case
+// and attribute order matter.
+func Exact() Option {
+	return func(c *config) {
+		c.exact = true
+	}
+}
+
+// Diff returns a component-level description of how got differs from
+// want, or "" when they match. By default URNs match when they are
+// equivalent (see urn.Equivalent); use Exact for identical URNs.
+func Diff(want, got *urn.URN, opts ...Option) string {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.exact && want.Equal(got) || !c.exact && want.Equivalent(got) {
+		return ""
+	}
+
+	var diffs []string
+	if c.exact && want.Entity != got.Entity || !urn.EqualEntities(want.Entity, got.Entity) {
+		diffs = append(diffs, fmt.Sprintf("entity differs: want %s got %s", want.Entity, got.Entity))
+	}
+	if want.ID != got.ID {
+		diffs = append(diffs, fmt.Sprintf("ID differs: want %s got %s", want.ID, got.ID))
+	}
+
+	wantPairs, gotPairs := want.AttributePairs(), got.AttributePairs()
+	wantVals, keys := valuesByKey(wantPairs, nil)
+	gotVals, keys := valuesByKey(gotPairs, keys)
+	for _, k := range keys {
+		w, g := wantVals[k], gotVals[k]
+		if !c.exact {
+			slices.Sort(w)
+			slices.Sort(g)
+		}
+		switch {
+		case g == nil:
+			diffs = append(diffs, fmt.Sprintf("missing attribute '%s'", k))
+		case w == nil:
+			diffs = append(diffs, fmt.Sprintf("extra attribute '%s'", k))
+		case !slices.Equal(w, g):
+			diffs = append(diffs, fmt.Sprintf("attribute '%s': want %s got %s", k, strings.Join(w, ","), strings.Join(g, ",")))
+		}
+	}
+	if len(diffs) == 0 {
+		// Only the attribute order differs.
+		diffs = append(diffs, fmt.Sprintf("attribute order differs: want %s got %s", keyList(wantPairs), keyList(gotPairs)))
+	}
+	return strings.Join(diffs, "; ")
+}
+
+// valuesByKey groups attribute values by key, appending newly seen keys to
+// keys in order of first appearance.
+func valuesByKey(pairs []urn.Attribute, keys []string) (map[string][]string, []string) {
+	m := make(map[string][]string)
+	for _, p := range pairs {
+		if m[p.Key] == nil && !slices.Contains(keys, p.Key) {
+			keys = append(keys, p.Key)
+		}
+		m[p.Key] = append(m[p.Key], p.Value)
+	}
+	return m, keys
+}
+
+func keyList(pairs []urn.Attribute) string {
+	keys := make([]string, len(pairs))
+	for i, p := range pairs {
+		keys[i] = p.Key
+	}
+	return "[" + strings.Join(keys, " ") + "]"
+}
+
+// AssertEqual parses want and got and reports a test error describing each
+// differing component when they do not match. Unparsable inputs are
+// reported as errors too.
+func AssertEqual(t testing.TB, want, got string, opts ...Option) {
+	t.Helper()
+	w, err := urn.Parse(want)
+	if err != nil {
+		t.Errorf("urntest: invalid want URN %q: %v", want, err)
+		return
+	}
+	g, err := urn.Parse(got)
+	if err != nil {
+		t.Errorf("urntest: invalid got URN %q: %v", got, err)
+		return
+	}
+	if d := Diff(w, g, opts...); d != "" {
+		t.Errorf("URN mismatch (want %s, got %s): %s", want, got, d)
+	}
+}
+
+// CmpOption returns a go-cmp option that compares *urn.URN values with the
+// same rules as Diff, so cmp.Diff and cmp.Equal work on structs holding
+// URNs. Two nil URNs are equal.
+func CmpOption(opts ...Option) cmp.Option {
+	return cmp.Comparer(func(a, b *urn.URN) bool {
+		if a == nil || b == nil {
+			return a == b
+		}
+		return Diff(a, b, opts...) == ""
+	})
+}
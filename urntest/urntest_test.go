@@ -0,0 +1,126 @@
+package urntest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/layerfly/go-urn"
+)
+
+// recorder captures failures reported through testing.TB.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEqual(t *testing.T) {
+	tests := []struct {
+		name      string
+		want, got string
+		opts      []Option
+		report    string // substring of the failure; "" means pass
+	}{
+		{"equal", "urn:orders:1:a:1", "urn:orders:1:a:1", nil, ""},
+		{"equivalent", "urn:orders:1:a:1:b:2", "urn:ORDERS:1:b:2:a:1", nil, ""},
+		{"entity", "urn:orders:1", "urn:invoices:1", nil, "entity differs: want orders got invoices"},
+		{"id", "urn:orders:123", "urn:orders:124", nil, "ID differs: want 123 got 124"},
+		{"value", "urn:orders:1:status:shipped", "urn:orders:1:status:pending", nil, "attribute 'status': want shipped got pending"},
+		{"extra", "urn:orders:1", "urn:orders:1:debug:1", nil, "extra attribute 'debug'"},
+		{"missing", "urn:orders:1:vendor:x", "urn:orders:1", nil, "missing attribute 'vendor'"},
+		{"duplicates", "urn:orders:1:tag:a:tag:b", "urn:orders:1:tag:a", nil, "attribute 'tag': want a,b got a"},
+		{"exact order", "urn:orders:1:a:1:b:2", "urn:orders:1:b:2:a:1", []Option{Exact()}, "attribute order differs: want [a b] got [b a]"},
+		{"exact entity case", "urn:orders:1", "urn:Orders:1", []Option{Exact()}, "entity differs: want orders got Orders"},
+		{"invalid", "urn:orders:1", "nope", nil, "invalid got URN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			AssertEqual(r, tt.want, tt.got, tt.opts...)
+			if tt.report == "" {
+				if len(r.errors) != 0 {
+					t.Errorf("unexpected failure: %v", r.errors)
+				}
+				return
+			}
+			if len(r.errors) != 1 || !strings.Contains(r.errors[0], tt.report) {
+				t.Errorf("got %v, want a failure containing %q", r.errors, tt.report)
+			}
+		})
+	}
+}
+
+func TestDiffCombinesComponents(t *testing.T) {
+	w, _ := urn.Parse("urn:orders:123:status:shipped")
+	g, _ := urn.Parse("urn:orders:124:status:pending:debug:1")
+	want := "ID differs: want 123 got 124; attribute 'status': want shipped got pending; extra attribute 'debug'"
+	if d := Diff(w, g); d != want {
+		t.Errorf("got %q, want %q", d, want)
+	}
+}
+
+func TestDiffFoldsEntitiesLikeEquivalent(t *testing.T) {
+	w, _ := urn.Parse("urn:keys:1")
+	g, _ := urn.Parse("urn:\u212Aeys:1")
+	if w.Equivalent(g) {
+		t.Fatal("the Kelvin sign folded to k")
+	}
+	if d := Diff(w, g); !strings.Contains(d, "entity differs") {
+		t.Errorf("got %q, want an entity difference", d)
+	}
+	u, _ := urn.Parse("urn:KEYS:1")
+	if d := Diff(w, u); d != "" {
+		t.Errorf("got %q for entities equal under ASCII folding", d)
+	}
+}
+
+func TestCmpOption(t *testing.T) {
+	type order struct {
+		Ref *urn.URN
+	}
+	a, _ := urn.Parse("urn:orders:1:a:1:b:2")
+	b, _ := urn.Parse("urn:orders:1:b:2:a:1")
+	if !cmp.Equal(order{a}, order{b}, CmpOption()) {
+		t.Error("equivalent URNs compared unequal")
+	}
+	if cmp.Equal(order{a}, order{b}, CmpOption(Exact())) {
+		t.Error("reordered URNs compared equal in exact mode")
+	}
+	if !cmp.Equal(order{}, order{}, CmpOption()) || cmp.Equal(order{a}, order{}, CmpOption()) {
+		t.Error("unexpected nil handling")
+	}
+}
+
+func TestFixedSources(t *testing.T) {
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	t.Cleanup(urn.SetUUIDSource(FixedUUIDSource(id)))
+	for range 3 {
+		if got := urn.CreateUUID("orders"); got != "urn:orders:"+id.String() {
+			t.Fatalf("CreateUUID = %q", got)
+		}
+	}
+
+	at := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(urn.SetClock(FixedClock(at)))
+	s, _ := urn.ExpiresIn("urn:job:1", time.Minute)
+	if exp, _, _ := urn.Expiry(s); !exp.Equal(at.Add(time.Minute)) {
+		t.Errorf("Expiry = %v", exp)
+	}
+
+	next := FixedUUIDSource(id, uuid.Nil)
+	if next() != id || next() != uuid.Nil || next() != id {
+		t.Error("FixedUUIDSource does not cycle through its IDs")
+	}
+	if FixedUUIDSource()() != uuid.Nil {
+		t.Error("empty FixedUUIDSource is not uuid.Nil")
+	}
+}
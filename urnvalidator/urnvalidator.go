@@ -0,0 +1,23 @@
+// Package urnvalidator adapts urn.ValidateString to go-playground/validator.
+// It lives in its own package so the core urn package stays free of the
+// validator dependency.
+package urnvalidator
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/layerfly/go-urn"
+)
+
+// Tag is the struct tag name the validation is registered under.
+const Tag = "urn"
+
+// Func validates that a string field holds a valid URN. Empty strings are
+// rejected; combine with "omitempty" for optional fields.
+var Func validator.Func = func(fl validator.FieldLevel) bool {
+	return urn.ValidateString(fl.Field().String()) == nil
+}
+
+// RegisterValidation registers Func on v under Tag.
+func RegisterValidation(v *validator.Validate) error {
+	return v.RegisterValidation(Tag, Func)
+}
@@ -0,0 +1,46 @@
+package urnvalidator
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type order struct {
+	URN    string `validate:"urn"`
+	Parent string `validate:"omitempty,urn"`
+}
+
+func newValidate(t *testing.T) *validator.Validate {
+	v := validator.New()
+	if err := RegisterValidation(v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestValidURN(t *testing.T) {
+	v := newValidate(t)
+	if err := v.Struct(order{URN: "urn:orders:1234"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInvalidURN(t *testing.T) {
+	v := newValidate(t)
+	for _, s := range []string{"", "invalid:orders:1234", "urn:orders:1234:status"} {
+		if err := v.Struct(order{URN: s}); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
+
+func TestOptionalURN(t *testing.T) {
+	v := newValidate(t)
+	if err := v.Struct(order{URN: "urn:orders:1", Parent: "urn:orders:0"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := v.Struct(order{URN: "urn:orders:1", Parent: "bogus"}); err == nil {
+		t.Error("expected error for invalid optional URN")
+	}
+}
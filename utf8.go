@@ -0,0 +1,72 @@
+package urn
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// checkUTF8 fails for each decoded ID, attribute key or value of u that is
+// not valid UTF-8. The entity is covered by the ASCII check.
+func (sc *strictChecker) checkUTF8(urnStr string, u *URN) bool {
+	if !utf8.ValidString(u.ID) {
+		seg, off := segmentAt(urnStr, 1)
+		ok := sc.fail(&InvalidURNError{
+			Kind:    KindInvalidUTF8,
+			Reason:  ReasonID,
+			Offset:  off,
+			Segment: seg,
+			ID:      u.ID,
+			Err:     ErrInvalidUTF8,
+		})
+		if !ok {
+			return false
+		}
+	}
+	for i, p := range u.attributes {
+		n := 2 + 2*i
+		var reason ErrorReason
+		switch {
+		case !utf8.ValidString(p.Key):
+			reason = ReasonKey
+		case !utf8.ValidString(p.Value):
+			reason = ReasonValue
+			n++
+		default:
+			continue
+		}
+		seg, off := segmentAt(urnStr, n)
+		ok := sc.fail(&InvalidURNError{
+			Kind:    KindInvalidUTF8,
+			Reason:  reason,
+			Offset:  off,
+			Segment: seg,
+			Key:     p.Key,
+			Err:     ErrInvalidUTF8,
+		})
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// checkJSONText returns an error wrapping ErrInvalidUTF8 naming the first
+// component of u that is not valid UTF-8. encoding/json would otherwise
+// replace the bytes with U+FFFD and the document would no longer round-trip.
+func (u *URN) checkJSONText() error {
+	switch {
+	case !utf8.ValidString(u.Entity):
+		return fmt.Errorf("Cannot marshal URN: entity is not valid UTF-8: %w", ErrInvalidUTF8)
+	case !utf8.ValidString(u.ID):
+		return fmt.Errorf("Cannot marshal URN: ID is not valid UTF-8: %w", ErrInvalidUTF8)
+	}
+	for _, p := range u.attributes {
+		if !utf8.ValidString(p.Key) {
+			return fmt.Errorf("Cannot marshal URN: attribute key %q is not valid UTF-8: %w", p.Key, ErrInvalidUTF8)
+		}
+		if !utf8.ValidString(p.Value) {
+			return fmt.Errorf("Cannot marshal URN: value of attribute %q is not valid UTF-8: %w", p.Key, ErrInvalidUTF8)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package urn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseStrictRejectsInvalidUTF8(t *testing.T) {
+	tests := []struct {
+		in     string
+		offset int
+		seg    string
+	}{
+		{"urn:blob:%ff%fe", 9, "%ff%fe"},
+		{"urn:blob:1:k%c3:v", 11, "k%c3"},
+		{"urn:blob:1:a:b:name:%80x", 20, "%80x"},
+	}
+	for _, tt := range tests {
+		_, err := ParseStrict(tt.in)
+		var ue *InvalidURNError
+		if !errors.As(err, &ue) || ue.Kind != KindInvalidUTF8 || ue.Offset != tt.offset || ue.Segment != tt.seg {
+			t.Errorf("ParseStrict(%q) = %#v, want KindInvalidUTF8 at %d", tt.in, err, tt.offset)
+			continue
+		}
+		if !errors.Is(err, ErrInvalidUTF8) {
+			t.Errorf("ParseStrict(%q) error does not wrap ErrInvalidUTF8", tt.in)
+		}
+	}
+
+	if _, err := ParseStrict("urn:blob:%c3%a9t%c3%a9:k:%e2%82%ac"); err != nil {
+		t.Errorf("ParseStrict(valid multi-byte) = %v", err)
+	}
+	if _, err := Parse("urn:blob:%ff%fe"); err != nil {
+		t.Errorf("Parse = %v, want lenient parse to accept invalid UTF-8", err)
+	}
+}
+
+func TestParseStrictInvalidUTF8NamesComponent(t *testing.T) {
+	_, err := ParseStrict("urn:blob:1:name:%ff")
+	if err == nil || err.Error() != `Invalid URN: Value of attribute "name" is not valid UTF-8` {
+		t.Errorf("ParseStrict = %v", err)
+	}
+}
+
+func TestAllowBinaryComponents(t *testing.T) {
+	u, err := ParseStrict("urn:blob:%ff%fe:k:%00%ff", AllowBinaryComponents())
+	if err != nil {
+		t.Fatalf("ParseStrict = %v", err)
+	}
+	if u.ID != "\xff\xfe" {
+		t.Errorf("ID = %q, want raw bytes", u.ID)
+	}
+	if got := u.String(); got != "urn:blob:%FF%FE:k:%00%FF" {
+		t.Errorf("String = %q", got)
+	}
+
+	if _, err := u.MarshalOrderedJSON(); !errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("MarshalOrderedJSON error = %v, want ErrInvalidUTF8", err)
+	}
+	if _, err := u.MarshalJSONOrderedObject(); !errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("MarshalJSONOrderedObject error = %v, want ErrInvalidUTF8", err)
+	}
+	if text, err := u.MarshalText(); err != nil || string(text) != u.String() {
+		t.Errorf("MarshalText = %q, %v", text, err)
+	}
+}
+
+func TestParseAuditReportsInvalidUTF8(t *testing.T) {
+	_, vs, err := ParseAudit("urn:blob:%ff:k:%fe")
+	if err != nil {
+		t.Fatalf("ParseAudit = %v", err)
+	}
+	n := 0
+	for _, v := range vs {
+		if v.Kind == KindInvalidUTF8 {
+			n++
+		}
+	}
+	if n != 2 {
+		t.Errorf("violations = %+v, want 2 of KindInvalidUTF8", vs)
+	}
+}
@@ -0,0 +1,155 @@
+package urn
+
+import (
+	"errors"
+)
+
+// ValidateComponents reports whether Compose would succeed for the given
+// components, without building the URN string. It checks the entity format,
+// that the ID and every attribute key and value are non-empty, and that the
+// escaped result fits within MaxURNLength. All violations are reported,
+// joined with errors.Join when there is more than one.
+func ValidateComponents(entity, id string, attrs map[string]string) error {
+	pairs := make([]Attribute, 0, len(attrs))
+	for k, v := range attrs {
+		pairs = append(pairs, Attribute{Key: k, Value: v})
+	}
+	return validateComponents(entity, id, pairs)
+}
+
+// ValidateComponentsPairs is like ValidateComponents but takes the
+// attributes as alternating key, value arguments.
+func ValidateComponentsPairs(entity, id string, kv ...string) error {
+	if len(kv)%2 != 0 {
+		return &InvalidURNError{Kind: KindUnpairedKey, Op: OpCompose}
+	}
+	pairs := make([]Attribute, 0, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		pairs = append(pairs, Attribute{Key: kv[i], Value: kv[i+1]})
+	}
+	return validateComponents(entity, id, pairs)
+}
+
+func validateComponents(entity, id string, pairs []Attribute) error {
+	c := loadConfig()
+	return validateComponentsOpts(c, &c.opts, entity, id, pairs)
+}
+
+// validateComponentsOpts is validateComponents with the entity format
+// taken from o and the key policy from c. A nil c skips the key policy.
+// Every component is checked: a single violation is returned as is and
+// several are joined with errors.Join, in component order.
+func validateComponentsOpts(c *config, o *options, entity, id string, pairs []Attribute) error {
+	var errs errorList
+	if entity == "" || id == "" {
+		errs.add(&InvalidURNError{Kind: KindEmptyComponent, Op: OpCompose})
+	}
+	if entity != "" {
+		errs.add(checkEntity(c, o, entity, OpCompose))
+	}
+	for _, p := range pairs {
+		errs.add(validatePair(c, p))
+	}
+	errs.add(checkLength(entity, id, pairs, MaxURNLength))
+	return errs.err()
+}
+
+// validatePairs checks that every attribute has a non-empty key and value
+// and, unless c is nil, that keys satisfy the registered key policy. Like
+// validateComponentsOpts, it reports every violation.
+func validatePairs(c *config, pairs []Attribute) error {
+	var errs errorList
+	for _, p := range pairs {
+		errs.add(validatePair(c, p))
+	}
+	return errs.err()
+}
+
+// validatePair checks one attribute. Its error names the key in Segment.
+func validatePair(c *config, p Attribute) error {
+	if p.Key == "" {
+		return &InvalidURNError{Kind: KindEmptyAttribute, Reason: ReasonKey, Op: OpCompose}
+	}
+	if p.Value == "" {
+		return &InvalidURNError{
+			Kind:    KindEmptyAttribute,
+			Reason:  ReasonValue,
+			Op:      OpCompose,
+			Segment: p.Key,
+			Key:     p.Key,
+		}
+	}
+	if c == nil {
+		return nil
+	}
+	return c.checkKey(p.Key)
+}
+
+// checkEntity checks a non-empty entity against the entity format under o
+// and, when c is not nil and o does not allow them, against the deprecated
+// aliases registered in c. op is reported in the error.
+func checkEntity(c *config, o *options, entity string, op ErrorOp) error {
+	switch {
+	case !isASCII(entity):
+		return &InvalidURNError{
+			Kind:    KindInvalidEntity,
+			Reason:  ReasonNonASCII,
+			Op:      op,
+			Segment: entity,
+			Entity:  entity,
+		}
+	case !o.validEntity(entity):
+		return &InvalidURNError{
+			Kind:    KindInvalidEntity,
+			Op:      op,
+			Segment: entity,
+			Entity:  entity,
+		}
+	case c != nil && !o.allowDeprecated:
+		if target, ok := c.entityAliases[asciiToLower(entity)]; ok {
+			return &InvalidURNError{
+				Kind:    KindInvalidEntity,
+				Reason:  ReasonDeprecatedAlias,
+				Op:      op,
+				Segment: entity,
+				Entity:  entity,
+				Want:    target,
+			}
+		}
+	}
+	return nil
+}
+
+// errorList collects errors. It allocates nothing until it holds a second
+// error, so the common single-error case stays as cheap as returning it.
+type errorList struct {
+	first error
+	more  []error
+}
+
+func (l *errorList) add(err error) {
+	switch {
+	case err == nil:
+	case l.first == nil:
+		l.first = err
+	default:
+		l.more = append(l.more, err)
+	}
+}
+
+// err returns nil, the only error, or all of them joined.
+func (l *errorList) err() error {
+	if l.more == nil {
+		return l.first
+	}
+	return errors.Join(append([]error{l.first}, l.more...)...)
+}
+
+// composedLen returns the length of the composed URN string.
+func composedLen(entity, id string, pairs []Attribute) int {
+	n := len("urn:") + escapedLen(entity) + 1 + escapedLen(id)
+	for _, p := range pairs {
+		n += 1 + escapedLen(p.Key) + 1 + escapedLen(p.Value)
+	}
+	return n
+}
@@ -0,0 +1,144 @@
+package urn
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateComponents(t *testing.T) {
+	cases := []struct {
+		name   string
+		entity string
+		id     string
+		attrs  map[string]string
+		ok     bool
+	}{
+		{"valid", "orders", "1234", map[string]string{"vendor": "amazon"}, true},
+		{"empty entity", "", "1234", nil, false},
+		{"empty id", "orders", "", nil, false},
+		{"bad entity", "bad entity!", "1234", nil, false},
+		{"empty key", "orders", "1234", map[string]string{"": "x"}, false},
+		{"empty value", "orders", "1234", map[string]string{"status": ""}, false},
+		{"escaped too long", "orders", strings.Repeat(":", 83), nil, false},
+		{"escaped fits", "orders", strings.Repeat(":", 80), nil, true},
+	}
+	for _, c := range cases {
+		err := ValidateComponents(c.entity, c.id, c.attrs)
+		if (err == nil) != c.ok {
+			t.Errorf("%s: expected ok=%v, got %v", c.name, c.ok, err)
+		}
+	}
+}
+
+func TestValidateComponentsPairs(t *testing.T) {
+	if err := ValidateComponentsPairs("orders", "1", "a", "1", "b", "2"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateComponentsPairs("orders", "1", "a"); err == nil {
+		t.Error("expected error for odd key/value count")
+	}
+}
+
+func TestValidateComponentsAgreesWithCompose(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "Z", "0", "-", ":", " ", "%", "/", "\n", "é", "~", "@"}
+	randString := func(max int) string {
+		var b strings.Builder
+		for n := rng.Intn(max + 1); n > 0; n-- {
+			b.WriteString(alphabet[rng.Intn(len(alphabet))])
+		}
+		return b.String()
+	}
+
+	var valid, invalid int
+	for i := 0; i < 5000; i++ {
+		entity := randString(6)
+		id := randString(90)
+		attrs := map[string]string{}
+		for n := rng.Intn(4); n > 0; n-- {
+			attrs[randString(5)] = randString(40)
+		}
+		_, composeErr := Compose(entity, id, attrs)
+		validateErr := ValidateComponents(entity, id, attrs)
+		if (composeErr == nil) != (validateErr == nil) {
+			t.Fatalf("disagreement for %q %q %v: compose=%v validate=%v",
+				entity, id, attrs, composeErr, validateErr)
+		}
+		if composeErr == nil {
+			valid++
+		} else {
+			invalid++
+		}
+	}
+	if valid == 0 || invalid == 0 {
+		t.Errorf("corpus is one-sided: %d valid, %d invalid", valid, invalid)
+	}
+}
+
+// invalidURNErrors flattens err into the *InvalidURNError values it holds.
+func invalidURNErrors(err error) []*InvalidURNError {
+	var out []*InvalidURNError
+	var ue *InvalidURNError
+	if errors.As(err, &ue) && ue == err {
+		return []*InvalidURNError{ue}
+	}
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range j.Unwrap() {
+			out = append(out, invalidURNErrors(e)...)
+		}
+	}
+	return out
+}
+
+func TestComposeAttrsReportsEveryViolation(t *testing.T) {
+	SetReservedKeys("internal")
+	t.Cleanup(func() { SetReservedKeys() })
+
+	_, err := ComposeAttrs("orders", "1", []Attribute{
+		{Key: "color", Value: ""},
+		{Key: "size", Value: "L"},
+		{Key: "internal", Value: "x"},
+		{Key: "note", Value: ""},
+	})
+	errs := invalidURNErrors(err)
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Kind.String()+" "+e.Segment)
+	}
+	want := []string{"empty attribute color", "reserved key internal", "empty attribute note"}
+	if !slices.Equal(got, want) {
+		t.Errorf("violations = %q, want %q (err: %v)", got, want, err)
+	}
+	var ue *InvalidURNError
+	if !errors.As(err, &ue) || ue.Segment != "color" {
+		t.Errorf("errors.As = %v, want the first violation", ue)
+	}
+}
+
+func TestBuilderReportsEveryViolation(t *testing.T) {
+	_, err := NewBuilder("bad entity", "").Attr("k", "").Build()
+	if n := len(invalidURNErrors(err)); n != 3 {
+		t.Errorf("Build reported %d violations, want 3: %v", n, err)
+	}
+}
+
+func TestComposeSingleViolation(t *testing.T) {
+	_, err := ComposeAttrs("orders", "1", []Attribute{{Key: "k", Value: ""}})
+	if _, ok := err.(*InvalidURNError); !ok {
+		t.Errorf("err = %T, want a bare *InvalidURNError", err)
+	}
+	if raceEnabled {
+		t.Skip("allocation counts differ under the race detector")
+	}
+	pairs := []Attribute{{Key: "k", Value: ""}}
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = ComposeAttrs("orders", "1", pairs)
+	})
+	// The error, its message and the boxed key for fmt; no error list.
+	if allocs > 3 {
+		t.Errorf("single-violation ComposeAttrs made %.0f allocations", allocs)
+	}
+}
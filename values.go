@@ -0,0 +1,67 @@
+package urn
+
+// Values returns the values of the requested keys that urnStr carries,
+// keyed by the requested key. Like Value, a key repeated in the URN
+// yields its first value; keys absent from the URN are absent from the map.
+func Values(urnStr string, keys ...string) (map[string]string, error) {
+	return defaultProcessor.Values(urnStr, keys...)
+}
+
+// ValuesOrdered returns the values of keys positionally: values[i] is the
+// first value of keys[i] and found[i] reports whether the URN has it. A
+// key requested twice is filled at both positions.
+func ValuesOrdered(urnStr string, keys ...string) (values []string, found []bool, err error) {
+	return defaultProcessor.ValuesOrdered(urnStr, keys...)
+}
+
+// Values is like the package-level Values, under the Processor's options.
+func (p *Processor) Values(urnStr string, keys ...string) (map[string]string, error) {
+	values, found, err := p.ValuesOrdered(urnStr, keys...)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string, len(keys))
+	for i, k := range keys {
+		if found[i] {
+			m[k] = values[i]
+		}
+	}
+	return m, nil
+}
+
+// ValuesOrdered is like the package-level ValuesOrdered, under the
+// Processor's options.
+func (p *Processor) ValuesOrdered(urnStr string, keys ...string) (values []string, found []bool, err error) {
+	o, _ := p.snapshot()
+	u, err := parse(urnStr, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, found = u.getMany(o.keyEqual, keys)
+	return values, found, nil
+}
+
+// GetMany returns the first value of each key, positionally, as
+// ValuesOrdered does: values[i] belongs to keys[i] and found[i] reports
+// whether the URN has it.
+func (u *URN) GetMany(keys ...string) (values []string, found []bool) {
+	return u.getMany(func(a, b string) bool { return a == b }, keys)
+}
+
+// getMany looks up every key in a single pass over the attributes.
+func (u *URN) getMany(eq func(a, b string) bool, keys []string) ([]string, []bool) {
+	values, found := make([]string, len(keys)), make([]bool, len(keys))
+	left := len(keys)
+	for _, a := range u.attributes {
+		if left == 0 {
+			break
+		}
+		for i, k := range keys {
+			if !found[i] && eq(a.Key, k) {
+				values[i], found[i] = a.Value, true
+				left--
+			}
+		}
+	}
+	return values, found
+}
@@ -0,0 +1,51 @@
+package urn
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestValuesOrdered(t *testing.T) {
+	in := "urn:item:1:vendor:acme:sku:x1:vendor:other:note:a%2Fb"
+	values, found, err := ValuesOrdered(in, "sku", "missing", "vendor", "note", "sku", "item")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantValues := []string{"x1", "", "acme", "a/b", "x1", ""}
+	wantFound := []bool{true, false, true, true, true, false}
+	if !slices.Equal(values, wantValues) || !slices.Equal(found, wantFound) {
+		t.Errorf("ValuesOrdered = %q, %v, want %q, %v", values, found, wantValues, wantFound)
+	}
+
+	if values, found, err := ValuesOrdered(in); err != nil || len(values) != 0 || len(found) != 0 {
+		t.Errorf("ValuesOrdered() = %q, %v, %v", values, found, err)
+	}
+	if _, _, err := ValuesOrdered("urn:item", "k"); err == nil {
+		t.Error("ValuesOrdered accepted an invalid URN")
+	}
+}
+
+func TestValues(t *testing.T) {
+	got, err := Values("urn:item:1:vendor:acme:sku:x1", "vendor", "sku", "vendor", "color")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"vendor": "acme", "sku": "x1"}; !maps.Equal(got, want) {
+		t.Errorf("Values = %v, want %v", got, want)
+	}
+
+	p := NewProcessor(CaseInsensitiveKeys())
+	got, _ = p.Values("urn:item:1:Vendor:acme", "VENDOR")
+	if got["VENDOR"] != "acme" {
+		t.Errorf("Processor.Values = %v, want keyed by the requested key", got)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	u, _ := Parse("urn:item:1:vendor:acme:sku:x1")
+	values, found := u.GetMany("sku", "Vendor", "vendor", "sku")
+	if !slices.Equal(values, []string{"x1", "", "acme", "x1"}) || !slices.Equal(found, []bool{true, false, true, true}) {
+		t.Errorf("GetMany = %q, %v", values, found)
+	}
+}
@@ -0,0 +1,91 @@
+package urn
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// DefaultVersionKey is the attribute key used by Version, WithVersion and
+// BumpVersion.
+const DefaultVersionKey = "v"
+
+// InvalidVersionError is returned when a version attribute exists but does
+// not hold a non-negative integer, or when a bump would overflow.
+type InvalidVersionError struct {
+	Key     string
+	Value   string
+	Message string
+}
+
+func (e *InvalidVersionError) Error() string {
+	return e.Message
+}
+
+// Versioner reads and writes a version number stored in an attribute.
+type Versioner struct {
+	Key string
+}
+
+var defaultVersioner = Versioner{Key: DefaultVersionKey}
+
+// Version returns the version stored in the URN, whether it was present,
+// and any error.
+func (v Versioner) Version(urnStr string) (int64, bool, error) {
+	raw, found, err := Value(urnStr, v.Key)
+	if err != nil || !found {
+		return 0, false, err
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n < 0 {
+		return 0, true, &InvalidVersionError{
+			Key:     v.Key,
+			Value:   raw,
+			Message: fmt.Sprintf("Invalid version: Attribute %s value %q is not a non-negative integer", v.Key, raw),
+		}
+	}
+	return n, true, nil
+}
+
+// WithVersion sets the version attribute to n.
+func (v Versioner) WithVersion(urnStr string, n int64) (string, error) {
+	if n < 0 {
+		return "", &InvalidVersionError{
+			Key:     v.Key,
+			Value:   strconv.FormatInt(n, 10),
+			Message: fmt.Sprintf("Invalid version: %d is negative", n),
+		}
+	}
+	return AddAttribute(urnStr, v.Key, strconv.FormatInt(n, 10))
+}
+
+// BumpVersion increments the version attribute, setting it to 1 when absent.
+func (v Versioner) BumpVersion(urnStr string) (string, error) {
+	n, _, err := v.Version(urnStr)
+	if err != nil {
+		return "", err
+	}
+	if n == math.MaxInt64 {
+		return "", &InvalidVersionError{
+			Key:     v.Key,
+			Value:   strconv.FormatInt(n, 10),
+			Message: fmt.Sprintf("Cannot bump version: Attribute %s would overflow", v.Key),
+		}
+	}
+	return v.WithVersion(urnStr, n+1)
+}
+
+// Version returns the version stored under DefaultVersionKey.
+func Version(urnStr string) (int64, bool, error) {
+	return defaultVersioner.Version(urnStr)
+}
+
+// WithVersion sets the version stored under DefaultVersionKey.
+func WithVersion(urnStr string, v int64) (string, error) {
+	return defaultVersioner.WithVersion(urnStr, v)
+}
+
+// BumpVersion increments the version stored under DefaultVersionKey.
+func BumpVersion(urnStr string) (string, error) {
+	return defaultVersioner.BumpVersion(urnStr)
+}
@@ -0,0 +1,89 @@
+package urn
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestVersionAbsent(t *testing.T) {
+	_, found, err := Version("urn:doc:42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("expected not found")
+	}
+	bumped, err := BumpVersion("urn:doc:42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bumped != "urn:doc:42:v:1" {
+		t.Errorf("expected urn:doc:42:v:1, got %s", bumped)
+	}
+}
+
+func TestVersionZero(t *testing.T) {
+	v, found, err := Version("urn:doc:42:v:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || v != 0 {
+		t.Errorf("expected 0, got %d (found=%v)", v, found)
+	}
+	bumped, err := BumpVersion("urn:doc:42:v:0:lang:en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bumped != "urn:doc:42:v:1:lang:en" {
+		t.Errorf("expected urn:doc:42:v:1:lang:en, got %s", bumped)
+	}
+}
+
+func TestWithVersion(t *testing.T) {
+	updated, err := WithVersion("urn:doc:42:v:7", 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != "urn:doc:42:v:9" {
+		t.Errorf("expected urn:doc:42:v:9, got %s", updated)
+	}
+	if _, err := WithVersion("urn:doc:42", -1); err == nil {
+		t.Error("expected error for negative version")
+	}
+}
+
+func TestBumpVersionOverflow(t *testing.T) {
+	max := "urn:doc:42:v:" + strconv.FormatInt(math.MaxInt64, 10)
+	_, err := BumpVersion(max)
+	var verr *InvalidVersionError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected InvalidVersionError, got %v", err)
+	}
+}
+
+func TestVersionNonNumeric(t *testing.T) {
+	_, found, err := Version("urn:doc:42:v:seven")
+	var verr *InvalidVersionError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected InvalidVersionError, got %v", err)
+	}
+	if !found || verr.Value != "seven" || verr.Key != "v" {
+		t.Errorf("unexpected error details: %+v (found=%v)", verr, found)
+	}
+	if _, err := BumpVersion("urn:doc:42:v:seven"); !errors.As(err, &verr) {
+		t.Errorf("expected InvalidVersionError from BumpVersion, got %v", err)
+	}
+}
+
+func TestVersionerCustomKey(t *testing.T) {
+	rev := Versioner{Key: "rev"}
+	bumped, err := rev.BumpVersion("urn:doc:42:rev:3:v:8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bumped != "urn:doc:42:rev:4:v:8" {
+		t.Errorf("expected urn:doc:42:rev:4:v:8, got %s", bumped)
+	}
+}
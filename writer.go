@@ -0,0 +1,147 @@
+package urn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// ErrNotCanonical is wrapped by the error Writer.Write returns for an input
+// that is valid but not canonical, under RejectNonCanonical.
+var ErrNotCanonical = errors.New("not canonical")
+
+// ErrWriterClosed is returned by Writer methods called after Close.
+var ErrWriterClosed = errors.New("writer closed")
+
+// RejectNonCanonical makes a Writer reject inputs whose normalized form
+// differs from the input instead of writing the normalized form.
+func RejectNonCanonical() Option {
+	return func(o *options) {
+		o.rejectNonCanonical = true
+	}
+}
+
+// WriterStats summarizes the inputs a Writer was given.
+type WriterStats struct {
+	// Written counts the URNs written, Repaired included.
+	Written int
+	// Repaired counts inputs written in a normalized form that differs
+	// from the input.
+	Repaired int
+	// Rejected counts inputs that were invalid, or not canonical under
+	// RejectNonCanonical, and were not written.
+	Rejected int
+}
+
+// Writer writes URNs one per line in normalized form: the "urn" scheme,
+// the entity with ASCII letters lowercased and every component escaped as
+// by EscapeSegment, with attributes in their original order, after any
+// normalizers registered with RegisterNormalizer. It is the writing
+// counterpart of Scanner. Output is buffered; call Flush or Close when
+// done. A Writer is not safe for concurrent use.
+type Writer struct {
+	bw     *bufio.Writer
+	opts   options
+	stats  WriterStats
+	err    error // sticky write error
+	closed bool
+}
+
+// NewWriter returns a Writer to w. Inputs are checked as by ParseStrict
+// with opts applied on top of the SetDefaults options.
+func NewWriter(w io.Writer, opts ...Option) *Writer {
+	c := loadConfig()
+	return &Writer{
+		bw:   bufio.NewWriter(w),
+		opts: newOptions(append(append([]Option(nil), c.defaults...), opts...)),
+	}
+}
+
+// Write validates urnStr and writes its normalized form followed by a
+// newline. An invalid input, or under RejectNonCanonical one that is not
+// already normalized, is counted as rejected and returned as an error
+// without writing anything; later calls still proceed. An error from the
+// underlying writer is sticky and returned by every later call.
+func (w *Writer) Write(urnStr string) error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+	if w.err != nil {
+		return w.err
+	}
+	u, err := parseStrict(urnStr, &w.opts, loadConfig())
+	if err != nil {
+		w.stats.Rejected++
+		return err
+	}
+	if err := w.opts.normalize(u); err != nil {
+		w.stats.Rejected++
+		return err
+	}
+	s, err := composeURN("urn", u)
+	if err != nil {
+		w.stats.Rejected++
+		return err
+	}
+	if s != urnStr {
+		if w.opts.rejectNonCanonical {
+			w.stats.Rejected++
+			return fmt.Errorf("Invalid URN: %q is %w, want %q", urnStr, ErrNotCanonical, s)
+		}
+		w.stats.Repaired++
+	}
+	w.bw.WriteString(s)
+	if w.err = w.bw.WriteByte('\n'); w.err != nil {
+		return w.err
+	}
+	w.stats.Written++
+	return nil
+}
+
+// WriteSeq writes every URN of urns. Rejected inputs are skipped and
+// returned joined, as *BatchError values carrying their position in the
+// sequence; an error from the underlying writer stops the iteration and
+// is returned alone.
+func (w *Writer) WriteSeq(urns iter.Seq[string]) error {
+	var errs []error
+	i := 0
+	for s := range urns {
+		if err := w.Write(s); err != nil {
+			if w.err != nil || w.closed {
+				return err
+			}
+			errs = append(errs, &BatchError{Index: i, Input: s, Err: err})
+		}
+		i++
+	}
+	return errors.Join(errs...)
+}
+
+// Stats returns the counts so far.
+func (w *Writer) Stats() WriterStats {
+	return w.stats
+}
+
+// Flush writes any buffered output to the underlying writer.
+func (w *Writer) Flush() error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+	if w.err == nil {
+		w.err = w.bw.Flush()
+	}
+	return w.err
+}
+
+// Close flushes the Writer and returns its final counts. It does not close
+// the underlying writer. Later calls to Write fail with ErrWriterClosed.
+func (w *Writer) Close() (WriterStats, error) {
+	if w.closed {
+		return w.stats, ErrWriterClosed
+	}
+	err := w.Flush()
+	w.closed = true
+	return w.stats, err
+}
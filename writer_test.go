@@ -0,0 +1,108 @@
+package urn
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriterNormalizes(t *testing.T) {
+	var buf strings.Builder
+	w := NewWriter(&buf)
+	inputs := []string{
+		"urn:orders:1",
+		"URN:Orders:2:note:a%2fb",
+		"urn:orders:3:k:%41",
+		"urn:orders",
+		"urn:orders:4:b:2:a:1",
+	}
+	for _, s := range inputs {
+		w.Write(s)
+	}
+	stats, err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "urn:orders:1\nurn:orders:2:note:a%2Fb\nurn:orders:3:k:A\nurn:orders:4:b:2:a:1\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+	if stats != (WriterStats{Written: 4, Repaired: 2, Rejected: 1}) {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	sc := NewScanner(strings.NewReader(buf.String()))
+	n := 0
+	for sc.Scan() {
+		if sc.URN().Raw() != sc.URN().String() {
+			t.Errorf("read back %q, not canonical", sc.URN().Raw())
+		}
+		n++
+	}
+	if sc.Err() != nil || n != 4 {
+		t.Errorf("Scanner read %d URNs, err %v", n, sc.Err())
+	}
+}
+
+func TestWriterRejectNonCanonical(t *testing.T) {
+	var buf strings.Builder
+	w := NewWriter(&buf, RejectNonCanonical())
+	if err := w.Write("urn:orders:1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write("urn:Orders:2"); !errors.Is(err, ErrNotCanonical) {
+		t.Errorf("Write(non-canonical) = %v, want ErrNotCanonical", err)
+	}
+	if err := w.Write("urn:orders:3:k:%01"); err != nil {
+		t.Errorf("Write = %v", err)
+	}
+	stats, _ := w.Close()
+	if buf.String() != "urn:orders:1\nurn:orders:3:k:%01\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+	if stats != (WriterStats{Written: 2, Rejected: 1}) {
+		t.Errorf("stats = %+v", stats)
+	}
+	if err := w.Write("urn:orders:4"); !errors.Is(err, ErrWriterClosed) {
+		t.Errorf("Write after Close = %v", err)
+	}
+}
+
+func TestWriterOptions(t *testing.T) {
+	var buf strings.Builder
+	w := NewWriter(&buf, RejectControlChars())
+	if err := w.Write("urn:orders:3:k:%01"); err == nil {
+		t.Error("Write accepted a control character under RejectControlChars")
+	}
+	if w.Stats().Rejected != 1 {
+		t.Errorf("stats = %+v", w.Stats())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriterWriteSeq(t *testing.T) {
+	var buf strings.Builder
+	w := NewWriter(&buf)
+	err := w.WriteSeq(slices.Values([]string{"urn:ab:1", "bad", "urn:Ab:2"}))
+	var be *BatchError
+	if !errors.As(err, &be) || be.Index != 1 || be.Input != "bad" {
+		t.Errorf("WriteSeq = %v, want BatchError at 1", err)
+	}
+	w.Flush()
+	if buf.String() != "urn:ab:1\nurn:ab:2\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+
+	w = NewWriter(failingWriter{})
+	long := slices.Repeat([]string{"urn:orders:" + strings.Repeat("1", 200)}, 100)
+	if err := w.WriteSeq(slices.Values(long)); err == nil || err.Error() != "disk full" {
+		t.Errorf("WriteSeq to failing writer = %v", err)
+	}
+	if err := w.Write("urn:ab:1"); err == nil {
+		t.Error("write error was not sticky")
+	}
+}